@@ -0,0 +1,155 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/codegen"
+)
+
+// config is the content of a -config file, in YAML or JSON, which describes
+// the files generated from one or more specs.
+type config struct {
+	// The spec of the outputs which don't have their own
+	Spec    string         `yaml:"spec"`
+	Outputs []outputConfig `yaml:"outputs"`
+}
+
+// outputConfig describes a generated file, with the same settings as the
+// command line flags.
+type outputConfig struct {
+	Spec           string            `yaml:"spec"`
+	Package        string            `yaml:"package"`
+	Generate       []string          `yaml:"generate"`
+	IncludeTags    []string          `yaml:"include-tags"`
+	ExcludeTags    []string          `yaml:"exclude-tags"`
+	ExcludeSchemas []string          `yaml:"exclude-schemas"`
+	ImportMapping  map[string]string `yaml:"import-mapping"`
+	Templates      string            `yaml:"templates"`
+	Output         string            `yaml:"output"`
+}
+
+// generation is a file to generate, described by either the command line
+// flags or an output of a config file.
+type generation struct {
+	specPath     string
+	packageName  string
+	opts         codegen.Options
+	templatesDir string
+	outputFile   string
+}
+
+// loadConfig reads the config file at path, and returns the files which it
+// describes. The relative paths of its specs, templates and outputs are
+// relative to the directory of the config file, and defaultSpec, if any, is
+// the spec of the outputs which the config doesn't give one.
+func loadConfig(path string, defaultSpec string) ([]generation, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config: %s", err)
+	}
+	var cfg config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config %s: %s", path, err)
+	}
+	generations, err := cfg.generations(filepath.Dir(path), defaultSpec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %s", path, err)
+	}
+	return generations, nil
+}
+
+// generations validates a config, whose relative paths are relative to dir,
+// and returns the files which it describes.
+func (c *config) generations(dir string, defaultSpec string) ([]generation, error) {
+	if len(c.Outputs) == 0 {
+		return nil, fmt.Errorf("no outputs")
+	}
+	if c.Spec != "" {
+		defaultSpec = resolvePath(dir, c.Spec)
+	}
+
+	var generations []generation
+	outputFiles := map[string]int{}
+	for i, output := range c.Outputs {
+		g, err := output.generation(dir, defaultSpec)
+		if err != nil {
+			return nil, fmt.Errorf("outputs[%d]: %s", i, err)
+		}
+		if g.outputFile == "" && len(c.Outputs) > 1 {
+			return nil, fmt.Errorf("outputs[%d]: no output file, which is only optional with a single output", i)
+		}
+		if j, ok := outputFiles[g.outputFile]; ok && g.outputFile != "" {
+			return nil, fmt.Errorf("outputs[%d]: output file %s is also the output of outputs[%d]", i, output.Output, j)
+		}
+		outputFiles[g.outputFile] = i
+		generations = append(generations, g)
+	}
+	return generations, nil
+}
+
+func (o *outputConfig) generation(dir string, defaultSpec string) (generation, error) {
+	g := generation{
+		specPath:    defaultSpec,
+		packageName: o.Package,
+	}
+	if o.Spec != "" {
+		g.specPath = resolvePath(dir, o.Spec)
+	}
+	if g.specPath == "" {
+		return g, fmt.Errorf("no spec")
+	}
+	if g.packageName == "" {
+		g.packageName = defaultPackageName(g.specPath)
+	}
+
+	targets := o.Generate
+	if len(targets) == 0 {
+		targets = splitCSVArg(defaultGenerate)
+	}
+	opts, err := generateOptions(targets)
+	if err != nil {
+		return g, err
+	}
+	if err := validateOptions(opts); err != nil {
+		return g, err
+	}
+	opts.IncludeTags = o.IncludeTags
+	opts.ExcludeTags = o.ExcludeTags
+	opts.ExcludeSchemas = o.ExcludeSchemas
+	opts.ImportMapping = o.ImportMapping
+	g.opts = opts
+
+	if o.Templates != "" {
+		g.templatesDir = resolvePath(dir, o.Templates)
+	}
+	if o.Output != "" {
+		g.outputFile = resolvePath(dir, o.Output)
+	}
+	return g, nil
+}
+
+// resolvePath resolves a path of a config file in dir. URLs of specs are
+// absolute.
+func resolvePath(dir string, path string) string {
+	if filepath.IsAbs(path) || isURL(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
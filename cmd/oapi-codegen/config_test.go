@@ -0,0 +1,105 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oapi-codegen-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeConfig(t, dir, "oapi-codegen.yaml", `
+spec: api/petstore.yaml
+outputs:
+  - package: client
+    generate: [types, client, client-errors]
+    exclude-tags: [admin]
+    import-mapping:
+      ../common.yaml: github.com/example/common
+    output: client/client.gen.go
+  - generate: [types, chi-server, spec]
+    include-tags: [pets]
+    exclude-schemas: [Error]
+    templates: templates
+    output: /tmp/server.gen.go
+  - spec: https://example.com/admin.yaml
+    output: admin/admin.gen.go
+`)
+	generations, err := loadConfig(path, "")
+	require.NoError(t, err)
+	require.Len(t, generations, 3)
+
+	client := generations[0]
+	assert.Equal(t, filepath.Join(dir, "api/petstore.yaml"), client.specPath)
+	assert.Equal(t, "client", client.packageName)
+	assert.True(t, client.opts.GenerateTypes && client.opts.GenerateClient && client.opts.GenerateClientErrors)
+	assert.False(t, client.opts.GenerateEchoServer)
+	assert.Equal(t, []string{"admin"}, client.opts.ExcludeTags)
+	assert.Equal(t, map[string]string{"../common.yaml": "github.com/example/common"}, client.opts.ImportMapping)
+	assert.Equal(t, filepath.Join(dir, "client/client.gen.go"), client.outputFile)
+
+	server := generations[1]
+	assert.Equal(t, "Petstore", server.packageName)
+	assert.True(t, server.opts.GenerateChiServer && server.opts.EmbedSpec)
+	assert.Equal(t, []string{"pets"}, server.opts.IncludeTags)
+	assert.Equal(t, []string{"Error"}, server.opts.ExcludeSchemas)
+	assert.Equal(t, filepath.Join(dir, "templates"), server.templatesDir)
+	assert.Equal(t, "/tmp/server.gen.go", server.outputFile)
+
+	// Outputs default to the targets of the -generate flag.
+	admin := generations[2]
+	assert.Equal(t, "https://example.com/admin.yaml", admin.specPath)
+	assert.Equal(t, "Admin", admin.packageName)
+	assert.True(t, admin.opts.GenerateTypes && admin.opts.GenerateClient && admin.opts.GenerateEchoServer && admin.opts.EmbedSpec)
+
+	// JSON configs work too, and the spec given on the command line is the
+	// default one.
+	path = writeConfig(t, dir, "oapi-codegen.json", `{"outputs": [{"generate": ["types"]}]}`)
+	generations, err = loadConfig(path, "petstore.yaml")
+	require.NoError(t, err)
+	require.Len(t, generations, 1)
+	assert.Equal(t, "petstore.yaml", generations[0].specPath)
+	assert.Empty(t, generations[0].outputFile)
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oapi-codegen-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		config string
+		err    string
+	}{
+		{`outputs: []`, "no outputs"},
+		{`outputs: [{generate: [types]}]`, "outputs[0]: no spec"},
+		{`{spec: a.yaml, outputs: [{generate: [types, clinet]}]}`, "outputs[0]: unknown generate option clinet"},
+		{`{spec: a.yaml, outputs: [{generate: [server, chi-server]}]}`, "outputs[0]: can only specify one of the server, chi-server and std-http targets"},
+		{`{spec: a.yaml, outputs: [{output: a.go}, {generate: [types]}]}`, "outputs[1]: no output file, which is only optional with a single output"},
+		{`{spec: a.yaml, outputs: [{output: a.go}, {output: ./a.go}]}`, "outputs[1]: output file ./a.go is also the output of outputs[0]"},
+		{`{spec: a.yaml, outputs: [{ouptut: a.go}]}`, "field ouptut not found"},
+	}
+	for _, test := range tests {
+		path := writeConfig(t, dir, "config.yaml", test.config)
+		_, err := loadConfig(path, "")
+		if assert.Error(t, err, test.config) {
+			assert.Contains(t, err.Error(), test.err)
+		}
+	}
+
+	_, err = loadConfig(filepath.Join(dir, "missing.yaml"), "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func numberedLines(from, to int) string {
+	var lines strings.Builder
+	for i := from; i <= to; i++ {
+		fmt.Fprintf(&lines, "line %d\n", i)
+	}
+	return lines.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	assert.Equal(t, "", unifiedDiff("a", "b", "same\n", "same\n"))
+
+	a := numberedLines(1, 20)
+	b := strings.Replace(a, "line 2\n", "line two\n", 1)
+	b = strings.Replace(b, "line 18\n", "", 1)
+	b += "line 21\n"
+	assert.Equal(t, `--- old
++++ new
+@@ -1,5 +1,5 @@
+ line 1
+-line 2
++line two
+ line 3
+ line 4
+ line 5
+@@ -15,6 +15,6 @@
+ line 15
+ line 16
+ line 17
+-line 18
+ line 19
+ line 20
++line 21
+`, unifiedDiff("old", "new", a, b))
+
+	// Changes close to each other share a hunk.
+	b = strings.Replace(a, "line 5\n", "line five\n", 1)
+	b = strings.Replace(b, "line 11\n", "line eleven\n", 1)
+	assert.Equal(t, `--- old
++++ new
+@@ -2,13 +2,13 @@
+ line 2
+ line 3
+ line 4
+-line 5
++line five
+ line 6
+ line 7
+ line 8
+ line 9
+ line 10
+-line 11
++line eleven
+ line 12
+ line 13
+ line 14
+`, unifiedDiff("old", "new", a, b))
+
+	// Missing files are diffed as empty ones.
+	assert.Equal(t, "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+line 1\n+line 2\n", unifiedDiff("old", "new", "", numberedLines(1, 2)))
+	assert.Equal(t, "--- old\n+++ new\n@@ -1 +1 @@\n-line 1\n+line 1\n\\ No newline at end of file\n", unifiedDiff("old", "new", "line 1\n", "line 1"))
+}
+
+func TestDiffLinesTooManyEdits(t *testing.T) {
+	var a, b []string
+	for i := 0; i < maxDiffEdits; i++ {
+		a = append(a, fmt.Sprintf("a%d\n", i))
+		b = append(b, fmt.Sprintf("b%d\n", i))
+	}
+	a = append([]string{"same\n"}, a...)
+	b = append([]string{"same\n"}, b...)
+	ops := diffLines(a, b)
+	assert.Len(t, ops, 1+2*maxDiffEdits)
+	assert.Equal(t, diffOp{' ', "same\n"}, ops[0])
+	assert.Equal(t, diffOp{'-', "a0\n"}, ops[1])
+	assert.Equal(t, diffOp{'+', "b0\n"}, ops[1+maxDiffEdits])
+}
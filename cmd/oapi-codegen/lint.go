@@ -0,0 +1,67 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/codegen"
+	"github.com/leslie-wang/oapi-codegen/pkg/util"
+)
+
+// lintFailNone is the -lint-fail value which reports findings without
+// failing on any of them.
+const lintFailNone = "none"
+
+// parseLintFail returns the least severity of the findings which fail the
+// lint, given by the -lint-fail flag, and whether any do.
+func parseLintFail(name string) (codegen.LintSeverity, bool, error) {
+	if name == lintFailNone {
+		return 0, false, nil
+	}
+	severity, err := codegen.ParseLintSeverity(name)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid -lint-fail value: %s", err)
+	}
+	return severity, true, nil
+}
+
+// lintSpecs writes the lint findings of each of the specs at the given paths
+// to w, each prefixed with the path of its spec, and returns the number of
+// findings of at least the severity failOn, if fail is set.
+func lintSpecs(w io.Writer, specPaths []string, failOn codegen.LintSeverity, fail bool) (int, error) {
+	failed := 0
+	linted := make(map[string]bool)
+	for _, specPath := range specPaths {
+		if linted[specPath] {
+			continue
+		}
+		linted[specPath] = true
+
+		swagger, err := util.LoadSwagger(specPath)
+		if err != nil {
+			return failed, fmt.Errorf("error loading swagger spec %s\n: %s", specPath, err)
+		}
+		for _, finding := range codegen.Lint(swagger) {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", specPath, finding); err != nil {
+				return failed, err
+			}
+			if fail && finding.Severity >= failOn {
+				failed++
+			}
+		}
+	}
+	return failed, nil
+}
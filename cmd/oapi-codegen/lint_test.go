@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/codegen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintSpecs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oapi-codegen-lint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeConfig(t, dir, "api.yaml", `
+openapi: 3.0.1
+info:
+  title: Lint
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Pet:
+      oneOf:
+      - type: string
+      - type: integer
+    Id:
+      type: string
+      format: uuid
+`)
+
+	var out bytes.Buffer
+	failed, err := lintSpecs(&out, []string{path, path}, codegen.LintWarning, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t,
+		path+": info: #/components/schemas/Id/format: unknown string format uuid, it's generated as string\n"+
+			path+": warning: #/components/schemas/Pet/oneOf: oneOf is not supported, it's generated as interface{}\n",
+		out.String())
+
+	out.Reset()
+	failed, err = lintSpecs(&out, []string{path}, codegen.LintError, true)
+	require.NoError(t, err)
+	assert.Equal(t, 0, failed)
+
+	failed, err = lintSpecs(&out, []string{path}, codegen.LintInfo, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, failed)
+}
+
+func TestParseLintFail(t *testing.T) {
+	severity, fail, err := parseLintFail("warning")
+	require.NoError(t, err)
+	assert.True(t, fail)
+	assert.Equal(t, codegen.LintWarning, severity)
+
+	_, fail, err = parseLintFail(lintFailNone)
+	require.NoError(t, err)
+	assert.False(t, fail)
+
+	_, _, err = parseLintFail("fatal")
+	assert.EqualError(t, err, "invalid -lint-fail value: unknown lint severity 'fatal', must be one of info, warning, error")
+}
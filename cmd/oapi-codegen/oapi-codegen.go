@@ -14,16 +14,18 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 
-	"github.com/deepmap/oapi-codegen/pkg/codegen"
-	"github.com/deepmap/oapi-codegen/pkg/util"
+	"github.com/leslie-wang/oapi-codegen/pkg/codegen"
+	"github.com/leslie-wang/oapi-codegen/pkg/util"
 )
 
 func errExit(format string, args ...interface{}) {
@@ -31,6 +33,9 @@ func errExit(format string, args ...interface{}) {
 	os.Exit(1)
 }
 
+// defaultGenerate is the code generated when the targets aren't given.
+const defaultGenerate = "types,client,server,spec"
+
 func main() {
 	var (
 		packageName    string
@@ -41,44 +46,208 @@ func main() {
 		templatesDir   string
 		importMapping  string
 		excludeSchemas string
+		configFile     string
+		check          bool
+		lint           bool
+		lintFail       string
 	)
 	flag.StringVar(&packageName, "package", "", "The package name for generated code")
-	flag.StringVar(&generate, "generate", "types,client,server,spec",
-		`Comma-separated list of code to generate; valid options: "types", "client", "chi-server", "server", "spec", "skip-fmt", "skip-prune"`)
+	flag.StringVar(&generate, "generate", defaultGenerate,
+		`Comma-separated list of code to generate; valid options: "types", "builders", "client", "client-errors", "chi-server", "server", "std-http", "strict-server", "mocks", "problem-details", "callbacks", "spec", "skip-fmt", "skip-prune"`)
 	flag.StringVar(&outputFile, "o", "", "Where to output generated code, stdout is default")
 	flag.StringVar(&includeTags, "include-tags", "", "Only include operations with the given tags. Comma-separated list of tags.")
 	flag.StringVar(&excludeTags, "exclude-tags", "", "Exclude operations that are tagged with the given tags. Comma-separated list of tags.")
 	flag.StringVar(&templatesDir, "templates", "", "Path to directory containing user templates")
 	flag.StringVar(&importMapping, "import-mapping", "", "A dict from the external reference to golang package path")
 	flag.StringVar(&excludeSchemas, "exclude-schemas", "", "A comma separated list of schemas which must be excluded from generation")
+	flag.StringVar(&configFile, "config", "", "Path to a YAML or JSON config file describing the files to generate, instead of the other flags")
+	flag.BoolVar(&check, "check", false, "Don't write the output files, but print how they differ from the generated code, and fail if they do")
+	flag.BoolVar(&lint, "lint", false, "Don't generate code, but report the constructs of the spec which won't generate a typed API")
+	flag.StringVar(&lintFail, "lint-fail", "error", `The least severity of the lint findings which fail the lint: "info", "warning", "error", or "none"`)
 	flag.Parse()
 
-	if flag.NArg() < 1 {
-		fmt.Println("Please specify a path to a OpenAPI 3.0 spec file")
-		os.Exit(1)
+	if check && lint {
+		errExit("the -check and -lint flags can't be combined\n")
+	}
+	failOn, fail, err := parseLintFail(lintFail)
+	if err != nil {
+		errExit("%s\n", err)
+	}
+
+	var generations []generation
+	if configFile != "" {
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "config", "check", "lint", "lint-fail":
+			default:
+				errExit("the -%s flag can't be combined with -config, which sets it for each output\n", f.Name)
+			}
+		})
+		generations, err = loadConfig(configFile, flag.Arg(0))
+		if err != nil {
+			errExit("%s\n", err)
+		}
+	} else {
+		if flag.NArg() < 1 {
+			fmt.Println("Please specify a path to a OpenAPI 3.0 spec file")
+			os.Exit(1)
+		}
+
+		// If the package name has not been specified, we will use the name of the
+		// swagger file.
+		if packageName == "" {
+			packageName = defaultPackageName(flag.Arg(0))
+		}
+
+		opts, err := generateOptions(splitCSVArg(generate))
+		if err != nil {
+			fmt.Println(err)
+			flag.PrintDefaults()
+			os.Exit(1)
+		}
+		if err := validateOptions(opts); err != nil {
+			errExit("%s", err)
+		}
+
+		opts.IncludeTags = splitCSVArg(includeTags)
+		opts.ExcludeTags = splitCSVArg(excludeTags)
+		opts.ExcludeSchemas = splitCSVArg(excludeSchemas)
+
+		if len(importMapping) > 0 {
+			opts.ImportMapping, err = util.ParseCommandlineMap(importMapping)
+			if err != nil {
+				errExit("error parsing import-mapping: %s\n", err)
+			}
+		}
+
+		generations = []generation{{
+			specPath:     flag.Arg(0),
+			packageName:  packageName,
+			opts:         opts,
+			templatesDir: templatesDir,
+			outputFile:   outputFile,
+		}}
 	}
 
-	// If the package name has not been specified, we will use the name of the
-	// swagger file.
-	if packageName == "" {
-		path := flag.Arg(0)
-		baseName := filepath.Base(path)
-		// Split the base name on '.' to get the first part of the file.
-		nameParts := strings.Split(baseName, ".")
-		packageName = codegen.ToCamelCase(nameParts[0])
+	if lint {
+		var specPaths []string
+		for _, g := range generations {
+			specPaths = append(specPaths, g.specPath)
+		}
+		failed, err := lintSpecs(os.Stdout, specPaths, failOn, fail)
+		if err != nil {
+			errExit("%s\n", err)
+		}
+		if failed > 0 {
+			errExit("%d lint finding(s) of %s severity or worse\n", failed, failOn)
+		}
+		return
 	}
 
+	outdated := 0
+	for _, g := range generations {
+		if check && g.outputFile == "" {
+			errExit("the -check flag requires the output file to check\n")
+		}
+
+		code, err := g.generate()
+		if err != nil && configFile != "" {
+			errExit("%s: %s\n", g.outputFile, err)
+		} else if err != nil {
+			errExit("%s\n", err)
+		}
+
+		if check {
+			diff, err := g.check(code)
+			if err != nil {
+				errExit("error checking generated code: %s\n", err)
+			}
+			if diff != "" {
+				fmt.Print(diff)
+				outdated++
+			}
+		} else if g.outputFile != "" {
+			err = ioutil.WriteFile(g.outputFile, []byte(code), 0644)
+			if err != nil {
+				errExit("error writing generated code to file: %s", err)
+			}
+		} else {
+			fmt.Println(code)
+		}
+	}
+
+	if outdated > 0 {
+		errExit("%d generated file(s) are out of date\n", outdated)
+	}
+}
+
+// generate generates the code of g.
+func (g *generation) generate() (string, error) {
+	swagger, err := util.LoadSwagger(g.specPath)
+	if err != nil {
+		return "", fmt.Errorf("error loading swagger spec %s\n: %s", g.specPath, err)
+	}
+
+	opts := g.opts
+	opts.UserTemplates, err = loadTemplateOverrides(g.templatesDir)
+	if err != nil {
+		return "", fmt.Errorf("error loading template overrides: %s", err)
+	}
+
+	code, err := codegen.Generate(swagger, g.packageName, opts)
+	if err != nil {
+		return "", fmt.Errorf("error generating code: %s", err)
+	}
+	return code, nil
+}
+
+// check returns the unified diff from the output file of g to the generated
+// code, or an empty string when they're the same. A missing output file is
+// compared as empty.
+func (g *generation) check(code string) (string, error) {
+	existing, err := ioutil.ReadFile(g.outputFile)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return unifiedDiff(g.outputFile, g.outputFile+" (generated)", string(existing), code), nil
+}
+
+// defaultPackageName returns the name of the package generated from the
+// spec at path, which is the first part of the name of its file.
+func defaultPackageName(path string) string {
+	baseName := filepath.Base(path)
+	// Split the base name on '.' to get the first part of the file.
+	nameParts := strings.Split(baseName, ".")
+	return codegen.ToCamelCase(nameParts[0])
+}
+
+// generateOptions returns the options generating the given targets.
+func generateOptions(targets []string) (codegen.Options, error) {
 	opts := codegen.Options{}
-	for _, g := range splitCSVArg(generate) {
+	for _, g := range targets {
 		switch g {
 		case "client":
 			opts.GenerateClient = true
+		case "client-errors":
+			opts.GenerateClientErrors = true
 		case "chi-server":
 			opts.GenerateChiServer = true
 		case "server":
 			opts.GenerateEchoServer = true
+		case "std-http":
+			opts.GenerateStdHTTPServer = true
+		case "strict-server":
+			opts.GenerateStrictServer = true
+		case "mocks":
+			opts.GenerateMocks = true
+		case "problem-details":
+			opts.ProblemDetails = true
+		case "callbacks":
+			opts.GenerateCallbacks = true
 		case "types":
 			opts.GenerateTypes = true
+		case "builders":
+			opts.GenerateBuilders = true
 		case "spec":
 			opts.EmbedSpec = true
 		case "skip-fmt":
@@ -86,51 +255,47 @@ func main() {
 		case "skip-prune":
 			opts.SkipPrune = true
 		default:
-			fmt.Printf("unknown generate option %s\n", g)
-			flag.PrintDefaults()
-			os.Exit(1)
+			return opts, fmt.Errorf("unknown generate option %s", g)
 		}
 	}
+	return opts, nil
+}
 
-	opts.IncludeTags = splitCSVArg(includeTags)
-	opts.ExcludeTags = splitCSVArg(excludeTags)
-	opts.ExcludeSchemas = splitCSVArg(excludeSchemas)
-
-	if opts.GenerateEchoServer && opts.GenerateChiServer {
-		errExit("can not specify both server and chi-server targets simultaneously")
+// validateOptions checks that the targets of opts can be generated together.
+func validateOptions(opts codegen.Options) error {
+	servers := 0
+	for _, generated := range []bool{opts.GenerateEchoServer, opts.GenerateChiServer, opts.GenerateStdHTTPServer} {
+		if generated {
+			servers++
+		}
 	}
-
-	swagger, err := util.LoadSwagger(flag.Arg(0))
-	if err != nil {
-		errExit("error loading swagger spec\n: %s", err)
+	if servers > 1 {
+		return errors.New("can only specify one of the server, chi-server and std-http targets")
 	}
 
-	templates, err := loadTemplateOverrides(templatesDir)
-	if err != nil {
-		errExit("error loading template overrides: %s\n", err)
+	if opts.GenerateStrictServer && servers == 0 {
+		return errors.New("the strict-server target requires the server, chi-server or std-http target")
 	}
-	opts.UserTemplates = templates
 
-	if len(importMapping) > 0 {
-		opts.ImportMapping, err = util.ParseCommandlineMap(importMapping)
-		if err != nil {
-			errExit("error parsing import-mapping: %s\n", err)
-		}
+	if opts.GenerateClientErrors && !opts.GenerateClient {
+		return errors.New("the client-errors target requires the client target")
 	}
 
-	code, err := codegen.Generate(swagger, packageName, opts)
-	if err != nil {
-		errExit("error generating code: %s\n", err)
+	if opts.GenerateMocks && servers == 0 && !opts.GenerateClient {
+		return errors.New("the mocks target requires the server, chi-server, std-http or client target")
 	}
 
-	if outputFile != "" {
-		err = ioutil.WriteFile(outputFile, []byte(code), 0644)
-		if err != nil {
-			errExit("error writing generated code to file: %s", err)
-		}
-	} else {
-		fmt.Println(code)
+	if opts.ProblemDetails && servers == 0 {
+		return errors.New("the problem-details option requires the server, chi-server or std-http target")
 	}
+	return nil
+}
+
+// isURL returns whether path is the URL of a spec, rather than a file path,
+// as util.LoadSwagger tells them apart.
+func isURL(path string) bool {
+	u, err := url.Parse(path)
+	return err == nil && u.Scheme != "" && u.Host != ""
 }
 
 func splitCSVArg(input string) []string {
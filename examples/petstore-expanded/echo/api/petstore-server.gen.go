@@ -1,6 +1,6 @@
 // Package api provides primitives to interact the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen DO NOT EDIT.
+// Code generated by github.com/leslie-wang/oapi-codegen DO NOT EDIT.
 package api
 
 import (
@@ -10,10 +10,12 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
-	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
+	"github.com/leslie-wang/oapi-codegen/pkg/middleware"
+	"github.com/leslie-wang/oapi-codegen/pkg/runtime"
 )
 
 // ServerInterface represents all server handlers.
@@ -47,14 +49,14 @@ func (w *ServerInterfaceWrapper) FindPets(ctx echo.Context) error {
 
 	err = runtime.BindQueryParameter("form", true, false, "tags", ctx.QueryParams(), &params.Tags)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tags: %s", err))
+		return badRequest(ctx, "query", "tags", fmt.Sprintf("Invalid format for parameter tags: %s", err))
 	}
 
 	// ------------- Optional query parameter "limit" -------------
 
 	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+		return badRequest(ctx, "query", "limit", fmt.Sprintf("Invalid format for parameter limit: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -74,12 +76,13 @@ func (w *ServerInterfaceWrapper) AddPet(ctx echo.Context) error {
 // DeletePet converts echo context to params.
 func (w *ServerInterfaceWrapper) DeletePet(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "id" -------------
 	var id int64
 
 	err = runtime.BindStyledParameter("simple", false, "id", ctx.Param("id"), &id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return badRequest(ctx, "path", "id", fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -90,12 +93,13 @@ func (w *ServerInterfaceWrapper) DeletePet(ctx echo.Context) error {
 // FindPetById converts echo context to params.
 func (w *ServerInterfaceWrapper) FindPetById(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "id" -------------
 	var id int64
 
 	err = runtime.BindStyledParameter("simple", false, "id", ctx.Param("id"), &id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return badRequest(ctx, "path", "id", fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -118,9 +122,10 @@ type EchoRouter interface {
 	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
 }
 
-// RegisterHandlers adds each server route to the EchoRouter.
+// RegisterHandlers adds each server route to the EchoRouter, under the
+// ServerBasePath of the spec.
 func RegisterHandlers(router EchoRouter, si ServerInterface) {
-	RegisterHandlersWithBaseURL(router, si, "")
+	RegisterHandlersWithBaseURL(router, si, ServerBasePath)
 }
 
 // Registers handlers, and prepends BaseURL to the paths, so that the paths
@@ -138,59 +143,209 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 
 }
 
+// ServerBasePath is the path of the URL of the first server of the spec,
+// which the handlers are registered under unless another base URL is given.
+const ServerBasePath = "/api"
+
+// badRequest is returned by the wrappers when a parameter or body of the
+// request can't be bound.
+func badRequest(ctx echo.Context, location, name, reason string) error {
+	return echo.NewHTTPError(http.StatusBadRequest, reason)
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+RXW48budH9KwV+32OnNbEXedBTvB4vICBrT+LdvKznoYYsSbXgpYcsaiwM9N+DYrdu",
-	"I3kWiwRBgrzo0s1qnjrnVLH62dgUhhQpSjHzZ1PsmgK2nx9yTll/DDkNlIWpXbbJkX47KjbzIJyimY+L",
-	"od3rzDLlgGLmhqO8fWM6I9uBxr+0omx2nQlUCq6++aD97UNokcxxZXa7zmR6rJzJmfkvZtpwv/x+15mP",
-	"9HRHcok7Yriy3UcMBGkJsiYYSC437Izg6jLup+3wetwLoG13hTdhQ+8/Lc38l2fz/5mWZm7+b3YUYjap",
-	"MJty2XUvk2F3CennyI+VgN05rlMx/vTdFTFeIGVn7nf3O73McZlGyaOgbbgpIHszNziwEIY/lydcrSj3",
-	"nEw3UWw+j9fg3d0CfiIMpjM1a9BaZJjPZicxu+5FEu+gYBg8tWBZo0AtVAA1mSIpE2ABjEBfx2WSwFFI",
-	"sUhGIVgSSs1UgGOj4NNAUZ/0tr+BMpDlJVtsW3XGs6VY6OgN825AuyZ409+cQS7z2ezp6anHdrtPeTWb",
-	"YsvsL4v3Hz5+/vCHN/1Nv5bgm2Eoh/Jp+Znyhi1dy3vWlsxUDBZ/ytndlKbpzIZyGUn5Y3/T3+iT00AR",
-	"BzZz87Zd6syAsm6OmClB+mM1Guyc1r+R1BwLoPeNSVjmFBpDZVuEwki1/q+FMqyVZGupFJD0JX7EAIUc",
-	"2BQdB4pSA1CRHn5EshSxgFAYUoaCKxbhAgUHpthBJAt5naKtBQqFkwUsgIGkh3cUCSOgwCrjhh0C1lWl",
-	"DtACo62eW2gP72vGB5aaITlO4FOm0EHKETMBrUiAPE3oItkObM2lFi0IT1Zq6eG2coHAIDUPXDoYqt9w",
-	"xKx7UU6adAfC0bKrUWCDmWuBX2uR1MMiwhotrBUElkIweBRCcGylBqVjMZaU5oKOBy6W4wowimZzzN3z",
-	"qno8ZD6sMZNk3JOo6yEkT0WYgMNA2bEy9XfeYBgTQs+PFQM4RmUmY4FHzW1DngViiiApS8pKCS8pusPu",
-	"PdxlpEJRFCZFDkcANUeETfJVBhTYUKSICngkVz8C1qzPWMTjk5eUJ9aXaNlzOduk7aAf3VFfCyU59KTC",
-	"uk55tJRRNDH97uFzLQNFx8qyRzWPSz7lTh1YyIq6uWXZrKJZd7ChNdvqEbSxZVcDeH6gnHr4MeUHBqpc",
-	"QnKnMujtZmyPliNj/yV+iZ/JNSVqgSWp+Xx6SLkFUDo6JlfJNfSgtRGwPXAin4vvgOpZtYySg6/qQ3Vn",
-	"D3drLOT9WBgD5Sm80dzkJYElVssPdSQc9/voutP4DflJOt5Qztidb611Auy6QyFGflj38LPAQN5TFCp6",
-	"bgypVNJK2hdRD0oF7qtAi27P5f5J+7Qak10DcrBFrNGCZC7SjqUNC1IPP9RiCUhaN3CVD1WgnaJY8pS5",
-	"wRn9uw8I6paKzTy2hoIRAq40ZfKTWj38tY6hIXnVbVSP6uidI5Tu0HwAq9UiGVdO9hzTnswxNZlDNapZ",
-	"VGDg2B2hTIUbufAecFEMlqU6VqilIFTZ+2wSctzpjLS2Xw93p8I05iaMQybhGk4612ia2p34W1tv/0WP",
-	"OB0Z2nG3cGZufuDo9Hxpx0ZWAiiXNoOcHxaCK+37sGQvlOFha3QUMHPzWClvj+e8rjPdNDK2qUQotDPo",
-	"coYaL2DOuNX/Rbbt2NPhpI035wgCfuWgbbyGB8o6z2Qq1UuDldtZ9g1MngPLGajfHEZ39zoAlUFbS0P/",
-	"5uZmP/VQHKe1YfDT4DD7tSjE52tpvzbKjXPcCyJ2F/PPQAJ7MON0tMTq5XfheQ3GONRf2bhG+jpoa9Ue",
-	"PK7pTKkhYN5eGSAU25DKlVHjfSaUNrJFetK1+1mszTV6Bo/YdYmOc96nJ3IXZn3n1KtmnE2pyPfJbf9l",
-	"LOzn6ksa7kjUY+icfh1gm9MZWXKl3T/pmd+0yn+PNS4Eb/fbPDp7ZrcbLeJJrrx+jdc1tnBc+fbOAg+o",
-	"bTaNrlncQqma0xWP3Lbo0SavdrTFrfaQYdR2wjL1Dx2gj+2D3YXS3+ol19+lLnvJd5dZK5ARhftPEvL2",
-	"IEZTYQuLW4X3+gvFuWIHHRe33zp+vt8u3O/Sa0li1/82uf5ny/iFoqP6bQnlzV6ms/f4/St5f/Jiq2+n",
-	"u/vdPwIAAP//v4qmX1cSAAA=",
+	"H4sIAAAAAAAC/+RXW28bydH9K4X+vsfJULGNfeBTtJYXIJC1lWg3L2s9lHqKZC36pu5qyoTB/x5Uz/Am",
+	"ytosEgQJ8sLLTNf0qXNOVdd8NTb6FAMFKWb+1RS7Jo/t54ecY9YfKcdEWZjaZRsH0u+Bis2chGMw83Ex",
+	"tHudWcbsUczccJC3b0xnZJto/EsrymbXGU+l4OqbD9rfPoQWyRxWZrfrTKbHypkGM//FTBvul9/vOvOR",
+	"nm5JLnEH9C9s9xE9QVyCrAkSyeWGnRFcXcb9tE2vxz0D2nZXeBM2dO7T0sx/+Wr+P9PSzM3/zY5CzCYV",
+	"ZlMuu+55MjxcQvo58GMl4OEc16kY3717QYxnSHkw97v7nV7msIyj5EHQNtzkkZ2ZG0wshP5P5QlXK8o9",
+	"R9NNFJu78Rpc3y7gJ0JvOlOzBq1F0nw2O4nZdc+SuIaCPjlqwbJGgVqoAGoyRWImwAIYgL6MyyTCQD6G",
+	"IhmFYEkoNVMBDo2CT4mCPultfwUlkeUlW2xbdcaxpVDo6A1zndCuCd70V2eQy3w2e3p66rHd7mNezabY",
+	"Mvvz4v2Hj3cf/vCmv+rX4l0zDGVfPi3vKG/Y0kt5z9qSmYrB4k45u53SNJ3ZUC4jKX/sr/orfXJMFDCx",
+	"mZu37VJnEsq6OWKmBOmP1Wiwc1r/SlJzKIDONSZhmaNvDJVtEfIj1fq/FsqwVpKtpVJA4ufwET0UGsDG",
+	"MLCnINUDFenhRyRLAQsI+RQzFFyxCBcomJhCB4Es5HUMthYo5E8WsAB6kh6uKRAGQIFVxg0PCFhXlTpA",
+	"C4y2Om6hPbyvGR9YaoY4cAQXM/kOYg6YCWhFAuRoQhfIdmBrLrVoQTiyUksPN5ULeAapOXHpIFW34YBZ",
+	"96IcNekOhIPloQaBDWauBX6tRWIPiwBrtLBWEFgKQXIohDCwleqVjsVYUpoLDpy4WA4rwCCazTF3x6vq",
+	"8JB5WmMmybgnUdeDj46KMAH7RHlgZepvvEE/JoSOHyt6GBiVmYwFHjW3DTkWCDGAxCwxKyW8pDAcdu/h",
+	"NiMVCqIwKbA/Aqg5IGyiq5JQYEOBAirgkVz98FizPmMRjk9eUp5YX6Jlx+Vsk7aDfnRHfS2UOKAjFXbo",
+	"lEdLGUUT0+8e7mpJFAZWlh2qeYboYu7UgYWsqJtbls0qmnUHG1qzrQ6Bg1AeqgfHD5RjDz/G/MBAlYuP",
+	"w6kMersZ26HlwNh/Dp/DHQ1NiVpgSWo+Fx9ibgEUj47JVXL1PWhteBQ5ks/FdUD1rFpGycFV9aG6s4fb",
+	"NRZybiyMRHkKbzQ3eUlgidXyQx0Jx/0+uu40fkNuko43lDN251trnQAP3aEQAz+se/hZIJFzFISKnhsp",
+	"lkqZjkXUg1KB+yrQottzuX/SPq3GZNeAHGwRarAgmYu0Y2nDgtTDD7VYApLWDYbKhyrQTlEsOcrc4Iz+",
+	"3Qd4dUvFZh5bfcEAHleaMrlJrR7+UsdQH53jvXpUR+8coXSH5gNYrRbJuHKy55j2ZI6pyRyqUc2iAgOH",
+	"7ghlKtzAhfeAi2KwLHVghVoKQpW9zyYhx53OSGv79XB7KkxjbsKYMglXf9K5RtPU7sTf2nr7z3rExaT1",
+	"xDEsBjM3P3AY9Hxpx0ZWAiiXNoOcHxaCK+37sGQnlOFha3QUMHPzWClvj+e8rjPdNDK2qUTItzPocoYa",
+	"L2DOuNX/Rbbt2NPhpI035wg8fmGvbbz6B8o6z2Qq1UmDldtZ9g1Mjj3LGajfHEZ3953JVJK2lob+zdXV",
+	"fuqhME5rKblpcJj9WmI4Tspnab82yo1z3DMidhfzTyKBPZhxOlpidfK78LwGYxzqX9i4BvqSyAppDx7X",
+	"dKZU7zFvXxggFFuK5YVR430mlDayBXrStftZrM01egaP2HVJJn1gfKLhwqzXg3rVjLMpFfk+Dtt/GQv7",
+	"ufqShlsS9RgOg34dYJvTGVlypd0/6ZnftMp/jzUuBG/32zw6+8rDbrSII3nh9Wu8rrGFw8q1dxZ4QG2z",
+	"cXTN4gZK1Zxe8MhNix5t8mpHW9xoD0mjthOWqX/oAH1sHzxcKP2tXvLdu3+sl7y7zFqBjCiG/yQhbw5i",
+	"NBW2sLhReK+/UJwrdtBxcfOt4+f77WL4XXotSez63ybX/2wZP1N0VL8tobzZy3T2Hr9/Je9PXmwxsdnd",
+	"7/4+AL+Kpl9XEgAA",
+}
+
+var (
+	rawSpecOnce sync.Once
+	rawSpecData []byte
+	rawSpecErr  error
+
+	cachedSwaggerOnce sync.Once
+	cachedSwagger     *openapi3.Swagger
+	cachedSwaggerErr  error
+)
+
+// rawSpec returns the JSON of the Swagger specification, which is decoded the
+// first time it's needed.
+func rawSpec() ([]byte, error) {
+	rawSpecOnce.Do(func() {
+		zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+		if err != nil {
+			rawSpecErr = fmt.Errorf("error base64 decoding spec: %s", err)
+			return
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(zipped))
+		if err != nil {
+			rawSpecErr = fmt.Errorf("error decompressing spec: %s", err)
+			return
+		}
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(zr)
+		if err != nil {
+			rawSpecErr = fmt.Errorf("error decompressing spec: %s", err)
+			return
+		}
+		rawSpecData = buf.Bytes()
+	})
+	return rawSpecData, rawSpecErr
 }
 
 // GetSwagger returns the Swagger specification corresponding to the generated code
-// in this file.
+// in this file. Each call returns a copy of its own, which may be modified.
 func GetSwagger() (*openapi3.Swagger, error) {
-	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	data, err := rawSpec()
 	if err != nil {
-		return nil, fmt.Errorf("error base64 decoding spec: %s", err)
+		return nil, err
 	}
-	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(data)
 	if err != nil {
-		return nil, fmt.Errorf("error decompressing spec: %s", err)
+		return nil, fmt.Errorf("error loading Swagger: %s", err)
 	}
-	var buf bytes.Buffer
-	_, err = buf.ReadFrom(zr)
+	return swagger, nil
+}
+
+// GetCachedSwagger returns the Swagger specification corresponding to the
+// generated code in this file, which is loaded the first time it's needed,
+// and shared by all the callers, so it mustn't be modified.
+func GetCachedSwagger() (*openapi3.Swagger, error) {
+	cachedSwaggerOnce.Do(func() {
+		cachedSwagger, cachedSwaggerErr = GetSwagger()
+	})
+	return cachedSwagger, cachedSwaggerErr
+}
+
+var (
+	specRouterOnce sync.Once
+	specRouter     *middleware.Router
+	specRouterErr  error
+)
+
+// getSpecRouter returns the router of the spec embedded in this file, which
+// is compiled the first time it's needed, and shared by the validators. It
+// matches the paths under the ServerBasePath, where the handlers are
+// registered, rather than the servers of the spec.
+func getSpecRouter() (*middleware.Router, error) {
+	specRouterOnce.Do(func() {
+		swagger, err := GetSwagger()
+		if err != nil {
+			specRouterErr = err
+			return
+		}
+		swagger.Servers = nil
+		if ServerBasePath != "" {
+			swagger.Servers = openapi3.Servers{{URL: ServerBasePath}}
+		}
+		specRouter, specRouterErr = middleware.NewRouter(swagger)
+	})
+	return specRouter, specRouterErr
+}
+
+// ValidateRequest validates a request against the spec embedded in this
+// file, returning a *middleware.RequestValidationError if it's invalid.
+func ValidateRequest(r *http.Request) error {
+	return ValidateRequestWithOptions(r, nil)
+}
+
+// ValidateRequestWithOptions validates a request against the spec embedded
+// in this file, with validation options, which authenticate requests for
+// operations with security requirements.
+func ValidateRequestWithOptions(r *http.Request, options *middleware.Options) error {
+	router, err := getSpecRouter()
 	if err != nil {
-		return nil, fmt.Errorf("error decompressing spec: %s", err)
+		return err
 	}
+	_, _, err = middleware.ValidateRequest(r.Context(), r, router, options)
+	return err
+}
 
-	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(buf.Bytes())
+// Validator returns the middleware which validates requests against the
+// spec embedded in this file, before they reach the handlers. It returns an
+// error if the embedded spec can't be loaded.
+func Validator() (echo.MiddlewareFunc, error) {
+	return ValidatorWithOptions(nil)
+}
+
+// ValidatorWithOptions returns the middleware which validates requests
+// against the spec embedded in this file, with validation options. It
+// returns an error if the embedded spec can't be loaded.
+func ValidatorWithOptions(options *middleware.Options) (echo.MiddlewareFunc, error) {
+	router, err := getSpecRouter()
 	if err != nil {
-		return nil, fmt.Errorf("error loading Swagger: %s", err)
+		return nil, err
 	}
-	return swagger, nil
+	return middleware.OapiRequestValidatorWithRouter(router, options), nil
+}
+
+var (
+	specHandlerOnce sync.Once
+	specHandler     *middleware.SpecHandler
+	specHandlerErr  error
+)
+
+// SpecHandler returns the handler which serves the spec embedded in this
+// file, as JSON to requests for paths ending in .json, as YAML to those
+// ending in .yaml, and as an HTML reference page to the others. It returns
+// an error if the embedded spec can't be loaded.
+func SpecHandler() (http.Handler, error) {
+	specHandlerOnce.Do(func() {
+		data, err := rawSpec()
+		if err != nil {
+			specHandlerErr = err
+			return
+		}
+		specHandler, specHandlerErr = middleware.NewSpecHandler(data)
+	})
+	if specHandlerErr != nil {
+		return nil, specHandlerErr
+	}
+	return specHandler, nil
+}
+
+// RegisterSpecHandlers serves the spec embedded in this file at
+// prefix/openapi.json and prefix/openapi.yaml, and its reference page at
+// prefix/docs. It returns an error, without registering anything, if the
+// embedded spec can't be loaded.
+func RegisterSpecHandlers(router EchoRouter, prefix string) error {
+	specHandler, err := SpecHandler()
+	if err != nil {
+		return err
+	}
+	handler := echo.WrapHandler(specHandler)
+	for _, path := range []string{"/openapi.json", "/openapi.yaml", "/docs"} {
+		router.GET(prefix+path, handler)
+		router.HEAD(prefix+path, handler)
+	}
+	return nil
 }
@@ -1,6 +1,6 @@
 // Package api provides primitives to interact the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen DO NOT EDIT.
+// Code generated by github.com/leslie-wang/oapi-codegen DO NOT EDIT.
 package api
 
 // Error defines model for Error.
@@ -46,5 +46,8 @@ type FindPetsParams struct {
 // AddPetJSONBody defines parameters for AddPet.
 type AddPetJSONBody NewPet
 
-// AddPetRequestBody defines body for AddPet for application/json ContentType.
+// AddPetJSONRequestBody defines body for AddPet for application/json ContentType.
 type AddPetJSONRequestBody AddPetJSONBody
+
+// ServerURL1 is the URL of server 1.
+const ServerURL1 = "http://petstore.swagger.io/api"
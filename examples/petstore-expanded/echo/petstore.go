@@ -10,10 +10,10 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/deepmap/oapi-codegen/examples/petstore-expanded/echo/api"
-	"github.com/deepmap/oapi-codegen/pkg/middleware"
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/leslie-wang/oapi-codegen/examples/petstore-expanded/echo/api"
+	"github.com/leslie-wang/oapi-codegen/pkg/middleware"
 )
 
 func main() {
@@ -41,8 +41,10 @@ func main() {
 	// OpenAPI schema.
 	e.Use(middleware.OapiRequestValidator(swagger))
 
-	// We now register our petStore above as the handler for the interface
-	api.RegisterHandlers(e, petStore)
+	// We now register our petStore above as the handler for the interface, at
+	// the root, where the validator matches the paths once the servers are
+	// cleared.
+	api.RegisterHandlersWithBaseURL(e, petStore, "")
 
 	// And we serve HTTP until the world ends.
 	e.Logger.Fatal(e.Start(fmt.Sprintf("0.0.0.0:%d", *port)))
@@ -24,9 +24,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/deepmap/oapi-codegen/examples/petstore-expanded/echo/api"
-	"github.com/deepmap/oapi-codegen/pkg/middleware"
-	"github.com/deepmap/oapi-codegen/pkg/testutil"
+	"github.com/leslie-wang/oapi-codegen/examples/petstore-expanded/echo/api"
+	"github.com/leslie-wang/oapi-codegen/pkg/middleware"
+	"github.com/leslie-wang/oapi-codegen/pkg/testutil"
 )
 
 func TestPetStore(t *testing.T) {
@@ -52,8 +52,10 @@ func TestPetStore(t *testing.T) {
 	e.Use(echo_middleware.Logger())
 
 	// We register the autogenerated boilerplate and bind our PetStore to this
-	// echo router.
-	api.RegisterHandlers(e, store)
+	// echo router. The paths are served at the root rather than under the
+	// ServerBasePath of the spec, since the validator matches them there
+	// once the servers are cleared.
+	api.RegisterHandlersWithBaseURL(e, store, "")
 
 	// At this point, we can start sending simulated Http requests, and record
 	// the HTTP responses to check for validity. This exercises every part of
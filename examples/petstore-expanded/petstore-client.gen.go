@@ -1,6 +1,6 @@
 // Package petstore provides primitives to interact the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen DO NOT EDIT.
+// Code generated by github.com/leslie-wang/oapi-codegen DO NOT EDIT.
 package petstore
 
 import (
@@ -14,7 +14,7 @@ import (
 	"net/url"
 	"strings"
 
-	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	"github.com/leslie-wang/oapi-codegen/pkg/runtime"
 )
 
 // Error defines model for Error.
@@ -60,12 +60,19 @@ type FindPetsParams struct {
 // AddPetJSONBody defines parameters for AddPet.
 type AddPetJSONBody NewPet
 
-// AddPetRequestBody defines body for AddPet for application/json ContentType.
+// AddPetJSONRequestBody defines body for AddPet for application/json ContentType.
 type AddPetJSONRequestBody AddPetJSONBody
 
+// ServerURL1 is the URL of server 1.
+const ServerURL1 = "http://petstore.swagger.io/api"
+
 // RequestEditorFn  is the function signature for the RequestEditor callback function
 type RequestEditorFn func(ctx context.Context, req *http.Request) error
 
+// DeprecatedCallFn is the function signature for the OnDeprecatedCall callback
+// function, which gets the ID of the operation called and its response.
+type DeprecatedCallFn func(ctx context.Context, operationID string, rsp *http.Response)
+
 // Doer performs HTTP requests.
 //
 // The standard http.Client implements this interface.
@@ -88,6 +95,10 @@ type Client struct {
 	// A callback for modifying requests which are generated before sending over
 	// the network.
 	RequestEditor RequestEditorFn
+
+	// A callback for responses which have a Deprecation header, which is
+	// useful to find the callers of deprecated operations.
+	OnDeprecatedCall DeprecatedCallFn
 }
 
 // ClientOption allows setting custom parameters during construction
@@ -134,6 +145,24 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 	}
 }
 
+// WithOnDeprecatedCall allows setting up a callback function, which will be
+// called with each response which has a Deprecation header, meaning that the
+// operation called is deprecated.
+func WithOnDeprecatedCall(fn DeprecatedCallFn) ClientOption {
+	return func(c *Client) error {
+		c.OnDeprecatedCall = fn
+		return nil
+	}
+}
+
+// notifyDeprecatedCall calls OnDeprecatedCall if the response has a
+// Deprecation header.
+func (c *Client) notifyDeprecatedCall(ctx context.Context, operationID string, rsp *http.Response) {
+	if c.OnDeprecatedCall != nil && rsp.Header.Get("Deprecation") != "" {
+		c.OnDeprecatedCall(ctx, operationID, rsp)
+	}
+}
+
 // The interface specification for the client above.
 type ClientInterface interface {
 	// FindPets request
@@ -163,7 +192,11 @@ func (c *Client) FindPets(ctx context.Context, params *FindPetsParams) (*http.Re
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "FindPets", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) AddPetWithBody(ctx context.Context, contentType string, body io.Reader) (*http.Response, error) {
@@ -178,7 +211,11 @@ func (c *Client) AddPetWithBody(ctx context.Context, contentType string, body io
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "AddPet", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) AddPet(ctx context.Context, body AddPetJSONRequestBody) (*http.Response, error) {
@@ -193,7 +230,11 @@ func (c *Client) AddPet(ctx context.Context, body AddPetJSONRequestBody) (*http.
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "AddPet", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) DeletePet(ctx context.Context, id int64) (*http.Response, error) {
@@ -208,7 +249,11 @@ func (c *Client) DeletePet(ctx context.Context, id int64) (*http.Response, error
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "DeletePet", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) FindPetById(ctx context.Context, id int64) (*http.Response, error) {
@@ -223,13 +268,23 @@ func (c *Client) FindPetById(ctx context.Context, id int64) (*http.Response, err
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "FindPetById", rsp)
+	}
+	return rsp, err
 }
 
 // NewFindPetsRequest generates requests for FindPets
 func NewFindPetsRequest(server string, params *FindPetsParams) (*http.Request, error) {
 	var err error
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -304,6 +359,12 @@ func NewAddPetRequest(server string, body AddPetJSONRequestBody) (*http.Request,
 func NewAddPetRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
 	var err error
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -339,6 +400,12 @@ func NewDeletePetRequest(server string, id int64) (*http.Request, error) {
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -373,6 +440,12 @@ func NewFindPetByIdRequest(server string, id int64) (*http.Request, error) {
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -662,6 +735,9 @@ func ParseDeletePetResponse(rsp *http.Response) (*DeletePetResponse, error) {
 		}
 		response.JSONDefault = &dest
 
+	case rsp.StatusCode == 204:
+		break // No content-type
+
 	}
 
 	return response, nil
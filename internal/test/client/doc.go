@@ -1,4 +1,4 @@
 package client
 
-//go:generate go run github.com/deepmap/oapi-codegen/cmd/oapi-codegen --package=client -o client.gen.go client.yaml
+//go:generate go run github.com/leslie-wang/oapi-codegen/cmd/oapi-codegen --package=client -o client.gen.go client.yaml
 
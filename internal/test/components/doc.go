@@ -1,3 +1,3 @@
 package components
 
-//go:generate go run github.com/deepmap/oapi-codegen/cmd/oapi-codegen  --package=components -o components.gen.go components.yaml
+//go:generate go run github.com/leslie-wang/oapi-codegen/cmd/oapi-codegen  --package=components -o components.gen.go components.yaml
@@ -3,8 +3,8 @@ package grab_import_names
 import (
 	"testing"
 
-	"github.com/deepmap/oapi-codegen/pkg/codegen"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/leslie-wang/oapi-codegen/pkg/codegen"
 	"github.com/stretchr/testify/require"
 )
 
@@ -6,8 +6,8 @@ import (
 	"go/token"
 	"testing"
 
-	"github.com/deepmap/oapi-codegen/pkg/codegen"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/leslie-wang/oapi-codegen/pkg/codegen"
 	"github.com/stretchr/testify/require"
 )
 
@@ -1,3 +1,3 @@
 package parameters
 
-//go:generate go run github.com/deepmap/oapi-codegen/cmd/oapi-codegen --package=parameters -o parameters.gen.go parameters.yaml
+//go:generate go run github.com/leslie-wang/oapi-codegen/cmd/oapi-codegen --package=parameters -o parameters.gen.go parameters.yaml
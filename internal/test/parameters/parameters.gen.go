@@ -1,6 +1,6 @@
 // Package parameters provides primitives to interact the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen DO NOT EDIT.
+// Code generated by github.com/leslie-wang/oapi-codegen DO NOT EDIT.
 package parameters
 
 import (
@@ -14,10 +14,12 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
-	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
+	"github.com/leslie-wang/oapi-codegen/pkg/middleware"
+	"github.com/leslie-wang/oapi-codegen/pkg/runtime"
 )
 
 // ComplexObject defines model for ComplexObject.
@@ -115,9 +117,16 @@ type GetQueryFormParams struct {
 	Co *ComplexObject `json:"co,omitempty"`
 }
 
+// ServerURL1 is the URL of server 1.
+const ServerURL1 = "http://openapitest.deepmap.ai"
+
 // RequestEditorFn  is the function signature for the RequestEditor callback function
 type RequestEditorFn func(ctx context.Context, req *http.Request) error
 
+// DeprecatedCallFn is the function signature for the OnDeprecatedCall callback
+// function, which gets the ID of the operation called and its response.
+type DeprecatedCallFn func(ctx context.Context, operationID string, rsp *http.Response)
+
 // Doer performs HTTP requests.
 //
 // The standard http.Client implements this interface.
@@ -140,6 +149,10 @@ type Client struct {
 	// A callback for modifying requests which are generated before sending over
 	// the network.
 	RequestEditor RequestEditorFn
+
+	// A callback for responses which have a Deprecation header, which is
+	// useful to find the callers of deprecated operations.
+	OnDeprecatedCall DeprecatedCallFn
 }
 
 // ClientOption allows setting custom parameters during construction
@@ -186,6 +199,24 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 	}
 }
 
+// WithOnDeprecatedCall allows setting up a callback function, which will be
+// called with each response which has a Deprecation header, meaning that the
+// operation called is deprecated.
+func WithOnDeprecatedCall(fn DeprecatedCallFn) ClientOption {
+	return func(c *Client) error {
+		c.OnDeprecatedCall = fn
+		return nil
+	}
+}
+
+// notifyDeprecatedCall calls OnDeprecatedCall if the response has a
+// Deprecation header.
+func (c *Client) notifyDeprecatedCall(ctx context.Context, operationID string, rsp *http.Response) {
+	if c.OnDeprecatedCall != nil && rsp.Header.Get("Deprecation") != "" {
+		c.OnDeprecatedCall(ctx, operationID, rsp)
+	}
+}
+
 // The interface specification for the client above.
 type ClientInterface interface {
 	// GetContentObject request
@@ -258,7 +289,11 @@ func (c *Client) GetContentObject(ctx context.Context, param ComplexObject) (*ht
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetContentObject", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetCookie(ctx context.Context, params *GetCookieParams) (*http.Response, error) {
@@ -273,7 +308,11 @@ func (c *Client) GetCookie(ctx context.Context, params *GetCookieParams) (*http.
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetCookie", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetHeader(ctx context.Context, params *GetHeaderParams) (*http.Response, error) {
@@ -288,7 +327,11 @@ func (c *Client) GetHeader(ctx context.Context, params *GetHeaderParams) (*http.
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetHeader", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetLabelExplodeArray(ctx context.Context, param []int32) (*http.Response, error) {
@@ -303,7 +346,11 @@ func (c *Client) GetLabelExplodeArray(ctx context.Context, param []int32) (*http
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetLabelExplodeArray", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetLabelExplodeObject(ctx context.Context, param Object) (*http.Response, error) {
@@ -318,7 +365,11 @@ func (c *Client) GetLabelExplodeObject(ctx context.Context, param Object) (*http
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetLabelExplodeObject", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetLabelNoExplodeArray(ctx context.Context, param []int32) (*http.Response, error) {
@@ -333,7 +384,11 @@ func (c *Client) GetLabelNoExplodeArray(ctx context.Context, param []int32) (*ht
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetLabelNoExplodeArray", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetLabelNoExplodeObject(ctx context.Context, param Object) (*http.Response, error) {
@@ -348,7 +403,11 @@ func (c *Client) GetLabelNoExplodeObject(ctx context.Context, param Object) (*ht
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetLabelNoExplodeObject", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetMatrixExplodeArray(ctx context.Context, id []int32) (*http.Response, error) {
@@ -363,7 +422,11 @@ func (c *Client) GetMatrixExplodeArray(ctx context.Context, id []int32) (*http.R
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetMatrixExplodeArray", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetMatrixExplodeObject(ctx context.Context, id Object) (*http.Response, error) {
@@ -378,7 +441,11 @@ func (c *Client) GetMatrixExplodeObject(ctx context.Context, id Object) (*http.R
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetMatrixExplodeObject", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetMatrixNoExplodeArray(ctx context.Context, id []int32) (*http.Response, error) {
@@ -393,7 +460,11 @@ func (c *Client) GetMatrixNoExplodeArray(ctx context.Context, id []int32) (*http
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetMatrixNoExplodeArray", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetMatrixNoExplodeObject(ctx context.Context, id Object) (*http.Response, error) {
@@ -408,7 +479,11 @@ func (c *Client) GetMatrixNoExplodeObject(ctx context.Context, id Object) (*http
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetMatrixNoExplodeObject", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetPassThrough(ctx context.Context, param string) (*http.Response, error) {
@@ -423,7 +498,11 @@ func (c *Client) GetPassThrough(ctx context.Context, param string) (*http.Respon
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetPassThrough", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetDeepObject(ctx context.Context, params *GetDeepObjectParams) (*http.Response, error) {
@@ -438,7 +517,11 @@ func (c *Client) GetDeepObject(ctx context.Context, params *GetDeepObjectParams)
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetDeepObject", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetQueryForm(ctx context.Context, params *GetQueryFormParams) (*http.Response, error) {
@@ -453,7 +536,11 @@ func (c *Client) GetQueryForm(ctx context.Context, params *GetQueryFormParams) (
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetQueryForm", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetSimpleExplodeArray(ctx context.Context, param []int32) (*http.Response, error) {
@@ -468,7 +555,11 @@ func (c *Client) GetSimpleExplodeArray(ctx context.Context, param []int32) (*htt
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetSimpleExplodeArray", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetSimpleExplodeObject(ctx context.Context, param Object) (*http.Response, error) {
@@ -483,7 +574,11 @@ func (c *Client) GetSimpleExplodeObject(ctx context.Context, param Object) (*htt
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetSimpleExplodeObject", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetSimpleNoExplodeArray(ctx context.Context, param []int32) (*http.Response, error) {
@@ -498,7 +593,11 @@ func (c *Client) GetSimpleNoExplodeArray(ctx context.Context, param []int32) (*h
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetSimpleNoExplodeArray", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetSimpleNoExplodeObject(ctx context.Context, param Object) (*http.Response, error) {
@@ -513,7 +612,11 @@ func (c *Client) GetSimpleNoExplodeObject(ctx context.Context, param Object) (*h
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetSimpleNoExplodeObject", rsp)
+	}
+	return rsp, err
 }
 
 func (c *Client) GetSimplePrimitive(ctx context.Context, param int32) (*http.Response, error) {
@@ -528,7 +631,11 @@ func (c *Client) GetSimplePrimitive(ctx context.Context, param int32) (*http.Res
 			return nil, err
 		}
 	}
-	return c.Client.Do(req)
+	rsp, err := c.Client.Do(req)
+	if err == nil {
+		c.notifyDeprecatedCall(ctx, "GetSimplePrimitive", rsp)
+	}
+	return rsp, err
 }
 
 // NewGetContentObjectRequest generates requests for GetContentObject
@@ -544,6 +651,12 @@ func NewGetContentObjectRequest(server string, param ComplexObject) (*http.Reque
 	}
 	pathParam0 = string(pathParamBuf0)
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -571,6 +684,12 @@ func NewGetContentObjectRequest(server string, param ComplexObject) (*http.Reque
 func NewGetCookieRequest(server string, params *GetCookieParams) (*http.Request, error) {
 	var err error
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -705,6 +824,12 @@ func NewGetCookieRequest(server string, params *GetCookieParams) (*http.Request,
 func NewGetHeaderRequest(server string, params *GetHeaderParams) (*http.Request, error) {
 	var err error
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -818,6 +943,12 @@ func NewGetLabelExplodeArrayRequest(server string, param []int32) (*http.Request
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -852,6 +983,12 @@ func NewGetLabelExplodeObjectRequest(server string, param Object) (*http.Request
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -886,6 +1023,12 @@ func NewGetLabelNoExplodeArrayRequest(server string, param []int32) (*http.Reque
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -920,6 +1063,12 @@ func NewGetLabelNoExplodeObjectRequest(server string, param Object) (*http.Reque
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -954,6 +1103,12 @@ func NewGetMatrixExplodeArrayRequest(server string, id []int32) (*http.Request,
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -988,6 +1143,12 @@ func NewGetMatrixExplodeObjectRequest(server string, id Object) (*http.Request,
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -1022,6 +1183,12 @@ func NewGetMatrixNoExplodeArrayRequest(server string, id []int32) (*http.Request
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -1056,6 +1223,12 @@ func NewGetMatrixNoExplodeObjectRequest(server string, id Object) (*http.Request
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -1087,6 +1260,12 @@ func NewGetPassThroughRequest(server string, param string) (*http.Request, error
 
 	pathParam0 = param
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -1114,6 +1293,12 @@ func NewGetPassThroughRequest(server string, param string) (*http.Request, error
 func NewGetDeepObjectRequest(server string, params *GetDeepObjectParams) (*http.Request, error) {
 	var err error
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -1157,6 +1342,12 @@ func NewGetDeepObjectRequest(server string, params *GetDeepObjectParams) (*http.
 func NewGetQueryFormRequest(server string, params *GetQueryFormParams) (*http.Request, error) {
 	var err error
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -1301,6 +1492,12 @@ func NewGetSimpleExplodeArrayRequest(server string, param []int32) (*http.Reques
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -1335,6 +1532,12 @@ func NewGetSimpleExplodeObjectRequest(server string, param Object) (*http.Reques
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -1369,6 +1572,12 @@ func NewGetSimpleNoExplodeArrayRequest(server string, param []int32) (*http.Requ
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -1403,6 +1612,12 @@ func NewGetSimpleNoExplodeObjectRequest(server string, param Object) (*http.Requ
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -1437,6 +1652,12 @@ func NewGetSimplePrimitiveRequest(server string, param int32) (*http.Request, er
 		return nil, err
 	}
 
+	// ensure the server URL has a trailing slash, so that the path is
+	// appended to it
+	if !strings.HasSuffix(server, "/") {
+		server += "/"
+	}
+
 	queryUrl, err := url.Parse(server)
 	if err != nil {
 		return nil, err
@@ -2547,12 +2768,13 @@ type ServerInterfaceWrapper struct {
 // GetContentObject converts echo context to params.
 func (w *ServerInterfaceWrapper) GetContentObject(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param ComplexObject
 
 	err = json.Unmarshal([]byte(ctx.Param("param")), &param)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Error unmarshaling parameter 'param' as JSON")
+		return badRequest(ctx, "path", "param", "Error unmarshaling parameter 'param' as JSON")
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -2572,7 +2794,7 @@ func (w *ServerInterfaceWrapper) GetCookie(ctx echo.Context) error {
 		var value int32
 		err = runtime.BindStyledParameter("simple", false, "p", cookie.Value, &value)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter p: %s", err))
+			return badRequest(ctx, "cookie", "p", fmt.Sprintf("Invalid format for parameter p: %s", err))
 		}
 		params.P = &value
 
@@ -2583,7 +2805,7 @@ func (w *ServerInterfaceWrapper) GetCookie(ctx echo.Context) error {
 		var value int32
 		err = runtime.BindStyledParameter("simple", true, "ep", cookie.Value, &value)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter ep: %s", err))
+			return badRequest(ctx, "cookie", "ep", fmt.Sprintf("Invalid format for parameter ep: %s", err))
 		}
 		params.Ep = &value
 
@@ -2594,7 +2816,7 @@ func (w *ServerInterfaceWrapper) GetCookie(ctx echo.Context) error {
 		var value []int32
 		err = runtime.BindStyledParameter("simple", true, "ea", cookie.Value, &value)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter ea: %s", err))
+			return badRequest(ctx, "cookie", "ea", fmt.Sprintf("Invalid format for parameter ea: %s", err))
 		}
 		params.Ea = &value
 
@@ -2605,7 +2827,7 @@ func (w *ServerInterfaceWrapper) GetCookie(ctx echo.Context) error {
 		var value []int32
 		err = runtime.BindStyledParameter("simple", false, "a", cookie.Value, &value)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter a: %s", err))
+			return badRequest(ctx, "cookie", "a", fmt.Sprintf("Invalid format for parameter a: %s", err))
 		}
 		params.A = &value
 
@@ -2616,7 +2838,7 @@ func (w *ServerInterfaceWrapper) GetCookie(ctx echo.Context) error {
 		var value Object
 		err = runtime.BindStyledParameter("simple", true, "eo", cookie.Value, &value)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter eo: %s", err))
+			return badRequest(ctx, "cookie", "eo", fmt.Sprintf("Invalid format for parameter eo: %s", err))
 		}
 		params.Eo = &value
 
@@ -2627,7 +2849,7 @@ func (w *ServerInterfaceWrapper) GetCookie(ctx echo.Context) error {
 		var value Object
 		err = runtime.BindStyledParameter("simple", false, "o", cookie.Value, &value)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter o: %s", err))
+			return badRequest(ctx, "cookie", "o", fmt.Sprintf("Invalid format for parameter o: %s", err))
 		}
 		params.O = &value
 
@@ -2639,11 +2861,11 @@ func (w *ServerInterfaceWrapper) GetCookie(ctx echo.Context) error {
 		var decoded string
 		decoded, err := url.QueryUnescape(cookie.Value)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, "Error unescaping cookie parameter 'co'")
+			return badRequest(ctx, "cookie", "co", "Error unescaping cookie parameter 'co'")
 		}
 		err = json.Unmarshal([]byte(decoded), &value)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, "Error unmarshaling parameter 'co' as JSON")
+			return badRequest(ctx, "cookie", "co", "Error unmarshaling parameter 'co' as JSON")
 		}
 		params.Co = &value
 
@@ -2667,12 +2889,12 @@ func (w *ServerInterfaceWrapper) GetHeader(ctx echo.Context) error {
 		var XPrimitive int32
 		n := len(valueList)
 		if n != 1 {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Primitive, got %d", n))
+			return badRequest(ctx, "header", "X-Primitive", fmt.Sprintf("Expected one value for X-Primitive, got %d", n))
 		}
 
 		err = runtime.BindStyledParameter("simple", false, "X-Primitive", valueList[0], &XPrimitive)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Primitive: %s", err))
+			return badRequest(ctx, "header", "X-Primitive", fmt.Sprintf("Invalid format for parameter X-Primitive: %s", err))
 		}
 
 		params.XPrimitive = &XPrimitive
@@ -2682,12 +2904,12 @@ func (w *ServerInterfaceWrapper) GetHeader(ctx echo.Context) error {
 		var XPrimitiveExploded int32
 		n := len(valueList)
 		if n != 1 {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Primitive-Exploded, got %d", n))
+			return badRequest(ctx, "header", "X-Primitive-Exploded", fmt.Sprintf("Expected one value for X-Primitive-Exploded, got %d", n))
 		}
 
 		err = runtime.BindStyledParameter("simple", true, "X-Primitive-Exploded", valueList[0], &XPrimitiveExploded)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Primitive-Exploded: %s", err))
+			return badRequest(ctx, "header", "X-Primitive-Exploded", fmt.Sprintf("Invalid format for parameter X-Primitive-Exploded: %s", err))
 		}
 
 		params.XPrimitiveExploded = &XPrimitiveExploded
@@ -2697,12 +2919,12 @@ func (w *ServerInterfaceWrapper) GetHeader(ctx echo.Context) error {
 		var XArrayExploded []int32
 		n := len(valueList)
 		if n != 1 {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Array-Exploded, got %d", n))
+			return badRequest(ctx, "header", "X-Array-Exploded", fmt.Sprintf("Expected one value for X-Array-Exploded, got %d", n))
 		}
 
 		err = runtime.BindStyledParameter("simple", true, "X-Array-Exploded", valueList[0], &XArrayExploded)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Array-Exploded: %s", err))
+			return badRequest(ctx, "header", "X-Array-Exploded", fmt.Sprintf("Invalid format for parameter X-Array-Exploded: %s", err))
 		}
 
 		params.XArrayExploded = &XArrayExploded
@@ -2712,12 +2934,12 @@ func (w *ServerInterfaceWrapper) GetHeader(ctx echo.Context) error {
 		var XArray []int32
 		n := len(valueList)
 		if n != 1 {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Array, got %d", n))
+			return badRequest(ctx, "header", "X-Array", fmt.Sprintf("Expected one value for X-Array, got %d", n))
 		}
 
 		err = runtime.BindStyledParameter("simple", false, "X-Array", valueList[0], &XArray)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Array: %s", err))
+			return badRequest(ctx, "header", "X-Array", fmt.Sprintf("Invalid format for parameter X-Array: %s", err))
 		}
 
 		params.XArray = &XArray
@@ -2727,12 +2949,12 @@ func (w *ServerInterfaceWrapper) GetHeader(ctx echo.Context) error {
 		var XObjectExploded Object
 		n := len(valueList)
 		if n != 1 {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Object-Exploded, got %d", n))
+			return badRequest(ctx, "header", "X-Object-Exploded", fmt.Sprintf("Expected one value for X-Object-Exploded, got %d", n))
 		}
 
 		err = runtime.BindStyledParameter("simple", true, "X-Object-Exploded", valueList[0], &XObjectExploded)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Object-Exploded: %s", err))
+			return badRequest(ctx, "header", "X-Object-Exploded", fmt.Sprintf("Invalid format for parameter X-Object-Exploded: %s", err))
 		}
 
 		params.XObjectExploded = &XObjectExploded
@@ -2742,12 +2964,12 @@ func (w *ServerInterfaceWrapper) GetHeader(ctx echo.Context) error {
 		var XObject Object
 		n := len(valueList)
 		if n != 1 {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Object, got %d", n))
+			return badRequest(ctx, "header", "X-Object", fmt.Sprintf("Expected one value for X-Object, got %d", n))
 		}
 
 		err = runtime.BindStyledParameter("simple", false, "X-Object", valueList[0], &XObject)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Object: %s", err))
+			return badRequest(ctx, "header", "X-Object", fmt.Sprintf("Invalid format for parameter X-Object: %s", err))
 		}
 
 		params.XObject = &XObject
@@ -2757,12 +2979,12 @@ func (w *ServerInterfaceWrapper) GetHeader(ctx echo.Context) error {
 		var XComplexObject ComplexObject
 		n := len(valueList)
 		if n != 1 {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Complex-Object, got %d", n))
+			return badRequest(ctx, "header", "X-Complex-Object", fmt.Sprintf("Expected one value for X-Complex-Object, got %d", n))
 		}
 
 		err = json.Unmarshal([]byte(valueList[0]), &XComplexObject)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, "Error unmarshaling parameter 'X-Complex-Object' as JSON")
+			return badRequest(ctx, "header", "X-Complex-Object", "Error unmarshaling parameter 'X-Complex-Object' as JSON")
 		}
 
 		params.XComplexObject = &XComplexObject
@@ -2776,12 +2998,13 @@ func (w *ServerInterfaceWrapper) GetHeader(ctx echo.Context) error {
 // GetLabelExplodeArray converts echo context to params.
 func (w *ServerInterfaceWrapper) GetLabelExplodeArray(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param []int32
 
 	err = runtime.BindStyledParameter("label", true, "param", ctx.Param("param"), &param)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter param: %s", err))
+		return badRequest(ctx, "path", "param", fmt.Sprintf("Invalid format for parameter param: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -2792,12 +3015,13 @@ func (w *ServerInterfaceWrapper) GetLabelExplodeArray(ctx echo.Context) error {
 // GetLabelExplodeObject converts echo context to params.
 func (w *ServerInterfaceWrapper) GetLabelExplodeObject(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param Object
 
 	err = runtime.BindStyledParameter("label", true, "param", ctx.Param("param"), &param)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter param: %s", err))
+		return badRequest(ctx, "path", "param", fmt.Sprintf("Invalid format for parameter param: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -2808,12 +3032,13 @@ func (w *ServerInterfaceWrapper) GetLabelExplodeObject(ctx echo.Context) error {
 // GetLabelNoExplodeArray converts echo context to params.
 func (w *ServerInterfaceWrapper) GetLabelNoExplodeArray(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param []int32
 
 	err = runtime.BindStyledParameter("label", false, "param", ctx.Param("param"), &param)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter param: %s", err))
+		return badRequest(ctx, "path", "param", fmt.Sprintf("Invalid format for parameter param: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -2824,12 +3049,13 @@ func (w *ServerInterfaceWrapper) GetLabelNoExplodeArray(ctx echo.Context) error
 // GetLabelNoExplodeObject converts echo context to params.
 func (w *ServerInterfaceWrapper) GetLabelNoExplodeObject(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param Object
 
 	err = runtime.BindStyledParameter("label", false, "param", ctx.Param("param"), &param)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter param: %s", err))
+		return badRequest(ctx, "path", "param", fmt.Sprintf("Invalid format for parameter param: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -2840,12 +3066,13 @@ func (w *ServerInterfaceWrapper) GetLabelNoExplodeObject(ctx echo.Context) error
 // GetMatrixExplodeArray converts echo context to params.
 func (w *ServerInterfaceWrapper) GetMatrixExplodeArray(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "id" -------------
 	var id []int32
 
 	err = runtime.BindStyledParameter("matrix", true, "id", ctx.Param("id"), &id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return badRequest(ctx, "path", "id", fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -2856,12 +3083,13 @@ func (w *ServerInterfaceWrapper) GetMatrixExplodeArray(ctx echo.Context) error {
 // GetMatrixExplodeObject converts echo context to params.
 func (w *ServerInterfaceWrapper) GetMatrixExplodeObject(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "id" -------------
 	var id Object
 
 	err = runtime.BindStyledParameter("matrix", true, "id", ctx.Param("id"), &id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return badRequest(ctx, "path", "id", fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -2872,12 +3100,13 @@ func (w *ServerInterfaceWrapper) GetMatrixExplodeObject(ctx echo.Context) error
 // GetMatrixNoExplodeArray converts echo context to params.
 func (w *ServerInterfaceWrapper) GetMatrixNoExplodeArray(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "id" -------------
 	var id []int32
 
 	err = runtime.BindStyledParameter("matrix", false, "id", ctx.Param("id"), &id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return badRequest(ctx, "path", "id", fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -2888,12 +3117,13 @@ func (w *ServerInterfaceWrapper) GetMatrixNoExplodeArray(ctx echo.Context) error
 // GetMatrixNoExplodeObject converts echo context to params.
 func (w *ServerInterfaceWrapper) GetMatrixNoExplodeObject(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "id" -------------
 	var id Object
 
 	err = runtime.BindStyledParameter("matrix", false, "id", ctx.Param("id"), &id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return badRequest(ctx, "path", "id", fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -2904,6 +3134,7 @@ func (w *ServerInterfaceWrapper) GetMatrixNoExplodeObject(ctx echo.Context) erro
 // GetPassThrough converts echo context to params.
 func (w *ServerInterfaceWrapper) GetPassThrough(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param string
 
@@ -2924,7 +3155,7 @@ func (w *ServerInterfaceWrapper) GetDeepObject(ctx echo.Context) error {
 
 	err = runtime.BindQueryParameter("deepObject", true, true, "deepObj", ctx.QueryParams(), &params.DeepObj)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter deepObj: %s", err))
+		return badRequest(ctx, "query", "deepObj", fmt.Sprintf("Invalid format for parameter deepObj: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -2942,42 +3173,42 @@ func (w *ServerInterfaceWrapper) GetQueryForm(ctx echo.Context) error {
 
 	err = runtime.BindQueryParameter("form", true, false, "ea", ctx.QueryParams(), &params.Ea)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter ea: %s", err))
+		return badRequest(ctx, "query", "ea", fmt.Sprintf("Invalid format for parameter ea: %s", err))
 	}
 
 	// ------------- Optional query parameter "a" -------------
 
 	err = runtime.BindQueryParameter("form", false, false, "a", ctx.QueryParams(), &params.A)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter a: %s", err))
+		return badRequest(ctx, "query", "a", fmt.Sprintf("Invalid format for parameter a: %s", err))
 	}
 
 	// ------------- Optional query parameter "eo" -------------
 
 	err = runtime.BindQueryParameter("form", true, false, "eo", ctx.QueryParams(), &params.Eo)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter eo: %s", err))
+		return badRequest(ctx, "query", "eo", fmt.Sprintf("Invalid format for parameter eo: %s", err))
 	}
 
 	// ------------- Optional query parameter "o" -------------
 
 	err = runtime.BindQueryParameter("form", false, false, "o", ctx.QueryParams(), &params.O)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter o: %s", err))
+		return badRequest(ctx, "query", "o", fmt.Sprintf("Invalid format for parameter o: %s", err))
 	}
 
 	// ------------- Optional query parameter "ep" -------------
 
 	err = runtime.BindQueryParameter("form", true, false, "ep", ctx.QueryParams(), &params.Ep)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter ep: %s", err))
+		return badRequest(ctx, "query", "ep", fmt.Sprintf("Invalid format for parameter ep: %s", err))
 	}
 
 	// ------------- Optional query parameter "p" -------------
 
 	err = runtime.BindQueryParameter("form", false, false, "p", ctx.QueryParams(), &params.P)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter p: %s", err))
+		return badRequest(ctx, "query", "p", fmt.Sprintf("Invalid format for parameter p: %s", err))
 	}
 
 	// ------------- Optional query parameter "co" -------------
@@ -2987,7 +3218,7 @@ func (w *ServerInterfaceWrapper) GetQueryForm(ctx echo.Context) error {
 		var value ComplexObject
 		err = json.Unmarshal([]byte(paramValue), &value)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, "Error unmarshaling parameter 'co' as JSON")
+			return badRequest(ctx, "query", "co", "Error unmarshaling parameter 'co' as JSON")
 		}
 		params.Co = &value
 
@@ -3001,12 +3232,13 @@ func (w *ServerInterfaceWrapper) GetQueryForm(ctx echo.Context) error {
 // GetSimpleExplodeArray converts echo context to params.
 func (w *ServerInterfaceWrapper) GetSimpleExplodeArray(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param []int32
 
 	err = runtime.BindStyledParameter("simple", true, "param", ctx.Param("param"), &param)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter param: %s", err))
+		return badRequest(ctx, "path", "param", fmt.Sprintf("Invalid format for parameter param: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -3017,12 +3249,13 @@ func (w *ServerInterfaceWrapper) GetSimpleExplodeArray(ctx echo.Context) error {
 // GetSimpleExplodeObject converts echo context to params.
 func (w *ServerInterfaceWrapper) GetSimpleExplodeObject(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param Object
 
 	err = runtime.BindStyledParameter("simple", true, "param", ctx.Param("param"), &param)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter param: %s", err))
+		return badRequest(ctx, "path", "param", fmt.Sprintf("Invalid format for parameter param: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -3033,12 +3266,13 @@ func (w *ServerInterfaceWrapper) GetSimpleExplodeObject(ctx echo.Context) error
 // GetSimpleNoExplodeArray converts echo context to params.
 func (w *ServerInterfaceWrapper) GetSimpleNoExplodeArray(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param []int32
 
 	err = runtime.BindStyledParameter("simple", false, "param", ctx.Param("param"), &param)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter param: %s", err))
+		return badRequest(ctx, "path", "param", fmt.Sprintf("Invalid format for parameter param: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -3049,12 +3283,13 @@ func (w *ServerInterfaceWrapper) GetSimpleNoExplodeArray(ctx echo.Context) error
 // GetSimpleNoExplodeObject converts echo context to params.
 func (w *ServerInterfaceWrapper) GetSimpleNoExplodeObject(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param Object
 
 	err = runtime.BindStyledParameter("simple", false, "param", ctx.Param("param"), &param)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter param: %s", err))
+		return badRequest(ctx, "path", "param", fmt.Sprintf("Invalid format for parameter param: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -3065,12 +3300,13 @@ func (w *ServerInterfaceWrapper) GetSimpleNoExplodeObject(ctx echo.Context) erro
 // GetSimplePrimitive converts echo context to params.
 func (w *ServerInterfaceWrapper) GetSimplePrimitive(ctx echo.Context) error {
 	var err error
+
 	// ------------- Path parameter "param" -------------
 	var param int32
 
 	err = runtime.BindStyledParameter("simple", false, "param", ctx.Param("param"), &param)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter param: %s", err))
+		return badRequest(ctx, "path", "param", fmt.Sprintf("Invalid format for parameter param: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
@@ -3093,9 +3329,10 @@ type EchoRouter interface {
 	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
 }
 
-// RegisterHandlers adds each server route to the EchoRouter.
+// RegisterHandlers adds each server route to the EchoRouter, under the
+// ServerBasePath of the spec.
 func RegisterHandlers(router EchoRouter, si ServerInterface) {
-	RegisterHandlersWithBaseURL(router, si, "")
+	RegisterHandlersWithBaseURL(router, si, ServerBasePath)
 }
 
 // Registers handlers, and prepends BaseURL to the paths, so that the paths
@@ -3128,48 +3365,198 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 
 }
 
+// ServerBasePath is the path of the URL of the first server of the spec,
+// which the handlers are registered under unless another base URL is given.
+const ServerBasePath = ""
+
+// badRequest is returned by the wrappers when a parameter or body of the
+// request can't be bound.
+func badRequest(ctx echo.Context, location, name, reason string) error {
+	return echo.NewHTTPError(http.StatusBadRequest, reason)
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9xZS2/jNhD+K8a0p0JreXdvugXbV4Duo3UOBRY5MNI45lYSuSQdJDD03wuSelIPS7aV",
-	"OHuzpeF8832cGQypPYQs4SzFVEkI9iBQcpZKNH/WNOEx/pM/0k9ClipMlf6p8FH5PCY01f9kuMWEmOdP",
-	"HCEAqQRN7yHLMg8ilKGgXFGWQgBXC2n8LgqsBbv7hqECbWr9GPQPTFs9frYvgz1wwTgKRW1w11ENjaYK",
-	"71FA5sG1vIoSG1T+8o6xGEmqX1bOfha4gQB+8iv+fg7uf67iEfh9RwVGEHwtFnsausK5bbhtxrihQqpP",
-	"JMEOYTwQLO564aAaK6/m6tZoStMN04tjGmK+OakBgo/XN9q7okq7hxuUarFG8YACPHhAIe02vF2ulitt",
-	"yDimhFMI4P1ytXwLHnCitiZ+P99vy8/fcyJIkuk392joarJE76veDfgD1Yf6AuNKkAQVCgnB10b+EM5j",
-	"GprF/jfJnCwa2p5mYuRqQGDCBq+QwSBDXUsldpjdes0cf7da9eGVdr5TCJnB9EPG/qM4rIaxaMnQLAgu",
-	"aEIVfdCG+MhjFiEEGxJLzImFhZuCGng1qTZMJETZInj/DrxWTWTeKEQtTw8gnoyYo0QLIgR5GgtLGrBU",
-	"YSJH4ZdPLFpHPK0whvSeL4xSFlYUzChdWCOgca3MhW4jDklwHOJc5d5kElqDSsNOBiEDp/jTXRybQt4i",
-	"iVAMFfKf1uLUQt4WbvKY/n3zpbZk1pIegH7zW56Fz1Lk7UCutHV3EM9W8j1RvXDht6OyVdAt1hx9oC+C",
-	"V9cO2kRyRwWhvuYQkzuMc71NTvj7pekCvwwOQn+5y9rNo2vHx8ww58lJD6R6MhOiYQjnnIzqmhWz41TR",
-	"+kbIc6g2JmFn1+cT68qqw/o01w0IVK/jHyivSv7NzJog3MHUOkW5l86thChBH53UotFw4X1sLTqm8Gg0",
-	"e05ZdvMJVubUJMWO71UHJJuWTLOJ02pVNBohzhka1WvOqHafmqbaCV3q0rOKEylvtoLt7rdjLpW+VOaD",
-	"V0oTriRf5MLo+w7F06+IvLov7KNcszpw6IwQ+fApwsBWPCPr+ugMcQbwKlGiKua+YdqE8jsTyRD3v0uj",
-	"A9RHnTcd9me7U6p466Uw8bzpRPVsQY07d7qazX/f5CCeA7CkeuhqxGU7z/XqANsjAF/yKO1E375YO6lJ",
-	"2o9DzXljxNFx3Vp2uQduS3E+1RqfaybIdjlH7tkUcifZw8PHumPdBR+651du/MfAddfCizh2z6ZSeak+",
-	"Xp/6JwBHmaOUGJE8c8mgXZsvzTb8nYghgK1SPPD9/DOzQqmWelJMCF8SCtlt9n8AAAD//xeCHE2EIAAA",
+	"H4sIAAAAAAAC/9xZS4/bNhD+K8a0p0KxnOSm2yJ9LdA8Wu+hQLAHrjS2mUoiQ9ILG4b+e0HqTT0s2dba",
+	"m5stzcw338eZAUkdwGcRZzHGSoJ3AIGSs1ii+bOkEQ/xn+yRfuKzWGGs9E+FO+XykNBY/5P+BiNinu85",
+	"ggdSCRqvIUkSBwKUvqBcURaDB3czaeLOcqwZe/qGvgJtmsYx6B+Yttp9Tl96B+CCcRSKpsndBxU0Gitc",
+	"o4DEgXt5F0Q0rrx8YixEEuuXZbCfBa7Ag5/ckr+bgbufy3wEft9SgQF4X3NnR0OXOI+1sPUcV1RI9YlE",
+	"2CKMA4KFbS8sVGPlVEI9Gk1pvGLaOaQ+ZosTGyD4eP+goyuqdHh4QKlmSxTPKMCBZxQyXYa388V8oQ0Z",
+	"x5hwCh68ny/mb8EBTtTG5O9m653ycw+cCBIl+s0aDV1Nluh11asBf6D6UHUwoQSJUKGQ4H2t1Q/hPKS+",
+	"cXa/SWZVUd/y1AsjUwM8kzY4uQwGGapaKrHF5NGp1/i7xaILr7BzrUZIDKbrM/YfxX41jEVDhnpDcEEj",
+	"quizNsQdD1mA4K1IKDEj5udhcmrgVKRaMRERlTbB+3fgNHoicQYhank6APFsxAwlmBEhyH4oLKnBUoWR",
+	"HIRfPEnRWvJppNGn93RpFLKwvGEG6cJqCQ0bZTZ0E7FPgtMQp2r3OhM/NSg1bGXgM7CaP96GoWnkDZIA",
+	"RV8j/5lanNvImzxMltO/b75UXCZt6R7oN79lVfgiTd5M5E5btyfxYi3fkdWVG7+ZVdoF7WJNMQe6Mnh1",
+	"46BJJAuUE+oaDiF5wjDT29SEe5ibKfBL70boL9utOTzaVnzIHuYyNemAVHuzQzQM4ZI7o6pm+d5xrGhd",
+	"W8hLqDakYCfX5xNrq6rj+tT9egSq9vEPVFcF/3pljRDuaGmdo9y1aysiStCdVVo06G+8jw2nUxqPBpPX",
+	"VMpuOsGKmhql2Omz6ohk44ppMnEao4oGA8S5wKB6zRXVnFPjVDtjSt16VXEi5cNGsO16M+RS6Utp3nul",
+	"NOJK8ioXRt+3KPa/IvLyvrCLcsXqyKEzQOT9pwgDW/IM0tAnV4i1AS8LJShz7tpMm1R+ZyLq4/53YXSE",
+	"+qDzpsX+YndKJW/tCiPPm1ZWL5bUsHOnrdn0900W4iUAC6rHrkZsttNcr/awPQHwmkdpK/vmxdpZQzL9",
+	"OFTfbww4Oi4bbrd74E4pTqda7XPNCNlu58g9mUL2Tvb45mPZ4nfDh+7plRv+MXDZ5ngTx+7JVCou1Yfr",
+	"U/0EYClzkhIDimcqGXRo86U5TX8rQvBgoxT3XDf7zKxQqnmAyCPC54RC8pj8PwAXghxNhCAAAA==",
+}
+
+var (
+	rawSpecOnce sync.Once
+	rawSpecData []byte
+	rawSpecErr  error
+
+	cachedSwaggerOnce sync.Once
+	cachedSwagger     *openapi3.Swagger
+	cachedSwaggerErr  error
+)
+
+// rawSpec returns the JSON of the Swagger specification, which is decoded the
+// first time it's needed.
+func rawSpec() ([]byte, error) {
+	rawSpecOnce.Do(func() {
+		zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+		if err != nil {
+			rawSpecErr = fmt.Errorf("error base64 decoding spec: %s", err)
+			return
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(zipped))
+		if err != nil {
+			rawSpecErr = fmt.Errorf("error decompressing spec: %s", err)
+			return
+		}
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(zr)
+		if err != nil {
+			rawSpecErr = fmt.Errorf("error decompressing spec: %s", err)
+			return
+		}
+		rawSpecData = buf.Bytes()
+	})
+	return rawSpecData, rawSpecErr
 }
 
 // GetSwagger returns the Swagger specification corresponding to the generated code
-// in this file.
+// in this file. Each call returns a copy of its own, which may be modified.
 func GetSwagger() (*openapi3.Swagger, error) {
-	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	data, err := rawSpec()
 	if err != nil {
-		return nil, fmt.Errorf("error base64 decoding spec: %s", err)
+		return nil, err
 	}
-	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(data)
 	if err != nil {
-		return nil, fmt.Errorf("error decompressing spec: %s", err)
+		return nil, fmt.Errorf("error loading Swagger: %s", err)
 	}
-	var buf bytes.Buffer
-	_, err = buf.ReadFrom(zr)
+	return swagger, nil
+}
+
+// GetCachedSwagger returns the Swagger specification corresponding to the
+// generated code in this file, which is loaded the first time it's needed,
+// and shared by all the callers, so it mustn't be modified.
+func GetCachedSwagger() (*openapi3.Swagger, error) {
+	cachedSwaggerOnce.Do(func() {
+		cachedSwagger, cachedSwaggerErr = GetSwagger()
+	})
+	return cachedSwagger, cachedSwaggerErr
+}
+
+var (
+	specRouterOnce sync.Once
+	specRouter     *middleware.Router
+	specRouterErr  error
+)
+
+// getSpecRouter returns the router of the spec embedded in this file, which
+// is compiled the first time it's needed, and shared by the validators. It
+// matches the paths under the ServerBasePath, where the handlers are
+// registered, rather than the servers of the spec.
+func getSpecRouter() (*middleware.Router, error) {
+	specRouterOnce.Do(func() {
+		swagger, err := GetSwagger()
+		if err != nil {
+			specRouterErr = err
+			return
+		}
+		swagger.Servers = nil
+		if ServerBasePath != "" {
+			swagger.Servers = openapi3.Servers{{URL: ServerBasePath}}
+		}
+		specRouter, specRouterErr = middleware.NewRouter(swagger)
+	})
+	return specRouter, specRouterErr
+}
+
+// ValidateRequest validates a request against the spec embedded in this
+// file, returning a *middleware.RequestValidationError if it's invalid.
+func ValidateRequest(r *http.Request) error {
+	return ValidateRequestWithOptions(r, nil)
+}
+
+// ValidateRequestWithOptions validates a request against the spec embedded
+// in this file, with validation options, which authenticate requests for
+// operations with security requirements.
+func ValidateRequestWithOptions(r *http.Request, options *middleware.Options) error {
+	router, err := getSpecRouter()
+	if err != nil {
+		return err
+	}
+	_, _, err = middleware.ValidateRequest(r.Context(), r, router, options)
+	return err
+}
+
+// Validator returns the middleware which validates requests against the
+// spec embedded in this file, before they reach the handlers. It returns an
+// error if the embedded spec can't be loaded.
+func Validator() (echo.MiddlewareFunc, error) {
+	return ValidatorWithOptions(nil)
+}
+
+// ValidatorWithOptions returns the middleware which validates requests
+// against the spec embedded in this file, with validation options. It
+// returns an error if the embedded spec can't be loaded.
+func ValidatorWithOptions(options *middleware.Options) (echo.MiddlewareFunc, error) {
+	router, err := getSpecRouter()
 	if err != nil {
-		return nil, fmt.Errorf("error decompressing spec: %s", err)
+		return nil, err
+	}
+	return middleware.OapiRequestValidatorWithRouter(router, options), nil
+}
+
+var (
+	specHandlerOnce sync.Once
+	specHandler     *middleware.SpecHandler
+	specHandlerErr  error
+)
+
+// SpecHandler returns the handler which serves the spec embedded in this
+// file, as JSON to requests for paths ending in .json, as YAML to those
+// ending in .yaml, and as an HTML reference page to the others. It returns
+// an error if the embedded spec can't be loaded.
+func SpecHandler() (http.Handler, error) {
+	specHandlerOnce.Do(func() {
+		data, err := rawSpec()
+		if err != nil {
+			specHandlerErr = err
+			return
+		}
+		specHandler, specHandlerErr = middleware.NewSpecHandler(data)
+	})
+	if specHandlerErr != nil {
+		return nil, specHandlerErr
 	}
+	return specHandler, nil
+}
 
-	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(buf.Bytes())
+// RegisterSpecHandlers serves the spec embedded in this file at
+// prefix/openapi.json and prefix/openapi.yaml, and its reference page at
+// prefix/docs. It returns an error, without registering anything, if the
+// embedded spec can't be loaded.
+func RegisterSpecHandlers(router EchoRouter, prefix string) error {
+	specHandler, err := SpecHandler()
 	if err != nil {
-		return nil, fmt.Errorf("error loading Swagger: %s", err)
+		return err
 	}
-	return swagger, nil
+	handler := echo.WrapHandler(specHandler)
+	for _, path := range []string{"/openapi.json", "/openapi.yaml", "/docs"} {
+		router.GET(prefix+path, handler)
+		router.HEAD(prefix+path, handler)
+	}
+	return nil
 }
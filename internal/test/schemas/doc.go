@@ -1,3 +1,3 @@
 package schemas
 
-//go:generate go run github.com/deepmap/oapi-codegen/cmd/oapi-codegen --package=schemas -o schemas.gen.go schemas.yaml
+//go:generate go run github.com/leslie-wang/oapi-codegen/cmd/oapi-codegen --package=schemas -o schemas.gen.go schemas.yaml
@@ -1,4 +1,3 @@
 package server
 
-//go:generate go run github.com/deepmap/oapi-codegen/cmd/oapi-codegen --generate=types,chi-server --package=server -o server.gen.go ../test-schema.yaml
-//go:generate go run github.com/matryer/moq -out server_moq.gen.go . ServerInterface
+//go:generate go run github.com/leslie-wang/oapi-codegen/cmd/oapi-codegen --generate=types,chi-server,mocks --package=server -o server.gen.go ../test-schema.yaml
@@ -1,6 +1,6 @@
 // Package server provides primitives to interact the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen DO NOT EDIT.
+// Code generated by github.com/leslie-wang/oapi-codegen DO NOT EDIT.
 package server
 
 import (
@@ -8,9 +8,9 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/deepmap/oapi-codegen/pkg/runtime"
-	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
 	"github.com/go-chi/chi"
+	"github.com/leslie-wang/oapi-codegen/pkg/runtime"
+	openapi_types "github.com/leslie-wang/oapi-codegen/pkg/types"
 )
 
 // EveryTypeOptional defines model for EveryTypeOptional.
@@ -117,15 +117,18 @@ type UpdateResource3JSONBody struct {
 	Name *string `json:"name,omitempty"`
 }
 
-// CreateResourceRequestBody defines body for CreateResource for application/json ContentType.
+// CreateResourceJSONRequestBody defines body for CreateResource for application/json ContentType.
 type CreateResourceJSONRequestBody CreateResourceJSONBody
 
-// CreateResource2RequestBody defines body for CreateResource2 for application/json ContentType.
+// CreateResource2JSONRequestBody defines body for CreateResource2 for application/json ContentType.
 type CreateResource2JSONRequestBody CreateResource2JSONBody
 
-// UpdateResource3RequestBody defines body for UpdateResource3 for application/json ContentType.
+// UpdateResource3JSONRequestBody defines body for UpdateResource3 for application/json ContentType.
 type UpdateResource3JSONRequestBody UpdateResource3JSONBody
 
+// ServerURL1 is the URL of server 1.
+const ServerURL1 = "http://openapitest.deepmap.ai"
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// get every type optional
@@ -196,7 +199,7 @@ func (siw *ServerInterfaceWrapper) GetWithArgs(w http.ResponseWriter, r *http.Re
 
 	err = runtime.BindQueryParameter("form", true, false, "optional_argument", r.URL.Query(), &params.OptionalArgument)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter optional_argument: %s", err), http.StatusBadRequest)
+		badRequest(w, r, "query", "optional_argument", fmt.Sprintf("Invalid format for parameter optional_argument: %s", err))
 		return
 	}
 
@@ -204,13 +207,13 @@ func (siw *ServerInterfaceWrapper) GetWithArgs(w http.ResponseWriter, r *http.Re
 	if paramValue := r.URL.Query().Get("required_argument"); paramValue != "" {
 
 	} else {
-		http.Error(w, "Query argument required_argument is required, but not found", http.StatusBadRequest)
+		badRequest(w, r, "query", "required_argument", "Query argument required_argument is required, but not found")
 		return
 	}
 
 	err = runtime.BindQueryParameter("form", true, true, "required_argument", r.URL.Query(), &params.RequiredArgument)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter required_argument: %s", err), http.StatusBadRequest)
+		badRequest(w, r, "query", "required_argument", fmt.Sprintf("Invalid format for parameter required_argument: %s", err))
 		return
 	}
 
@@ -221,13 +224,13 @@ func (siw *ServerInterfaceWrapper) GetWithArgs(w http.ResponseWriter, r *http.Re
 		var HeaderArgument int32
 		n := len(valueList)
 		if n != 1 {
-			http.Error(w, fmt.Sprintf("Expected one value for header_argument, got %d", n), http.StatusBadRequest)
+			badRequest(w, r, "header", "header_argument", fmt.Sprintf("Expected one value for header_argument, got %d", n))
 			return
 		}
 
 		err = runtime.BindStyledParameter("simple", false, "header_argument", valueList[0], &HeaderArgument)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("Invalid format for parameter header_argument: %s", err), http.StatusBadRequest)
+			badRequest(w, r, "header", "header_argument", fmt.Sprintf("Invalid format for parameter header_argument: %s", err))
 			return
 		}
 
@@ -247,18 +250,18 @@ func (siw *ServerInterfaceWrapper) GetWithReferences(w http.ResponseWriter, r *h
 	// ------------- Path parameter "global_argument" -------------
 	var globalArgument int64
 
-	err = runtime.BindStyledParameter("simple", false, "global_argument", chi.URLParam(r, "global_argument"), &globalArgument)
+	err = runtime.BindStyledParameter("simple", false, "global_argument", pathParam(r, "global_argument"), &globalArgument)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter global_argument: %s", err), http.StatusBadRequest)
+		badRequest(w, r, "path", "global_argument", fmt.Sprintf("Invalid format for parameter global_argument: %s", err))
 		return
 	}
 
 	// ------------- Path parameter "argument" -------------
 	var argument Argument
 
-	err = runtime.BindStyledParameter("simple", false, "argument", chi.URLParam(r, "argument"), &argument)
+	err = runtime.BindStyledParameter("simple", false, "argument", pathParam(r, "argument"), &argument)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter argument: %s", err), http.StatusBadRequest)
+		badRequest(w, r, "path", "argument", fmt.Sprintf("Invalid format for parameter argument: %s", err))
 		return
 	}
 
@@ -274,9 +277,9 @@ func (siw *ServerInterfaceWrapper) GetWithContentType(w http.ResponseWriter, r *
 	// ------------- Path parameter "content_type" -------------
 	var contentType string
 
-	err = runtime.BindStyledParameter("simple", false, "content_type", chi.URLParam(r, "content_type"), &contentType)
+	err = runtime.BindStyledParameter("simple", false, "content_type", pathParam(r, "content_type"), &contentType)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter content_type: %s", err), http.StatusBadRequest)
+		badRequest(w, r, "path", "content_type", fmt.Sprintf("Invalid format for parameter content_type: %s", err))
 		return
 	}
 
@@ -299,9 +302,9 @@ func (siw *ServerInterfaceWrapper) CreateResource(w http.ResponseWriter, r *http
 	// ------------- Path parameter "argument" -------------
 	var argument Argument
 
-	err = runtime.BindStyledParameter("simple", false, "argument", chi.URLParam(r, "argument"), &argument)
+	err = runtime.BindStyledParameter("simple", false, "argument", pathParam(r, "argument"), &argument)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter argument: %s", err), http.StatusBadRequest)
+		badRequest(w, r, "path", "argument", fmt.Sprintf("Invalid format for parameter argument: %s", err))
 		return
 	}
 
@@ -317,9 +320,9 @@ func (siw *ServerInterfaceWrapper) CreateResource2(w http.ResponseWriter, r *htt
 	// ------------- Path parameter "inline_argument" -------------
 	var inlineArgument int
 
-	err = runtime.BindStyledParameter("simple", false, "inline_argument", chi.URLParam(r, "inline_argument"), &inlineArgument)
+	err = runtime.BindStyledParameter("simple", false, "inline_argument", pathParam(r, "inline_argument"), &inlineArgument)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter inline_argument: %s", err), http.StatusBadRequest)
+		badRequest(w, r, "path", "inline_argument", fmt.Sprintf("Invalid format for parameter inline_argument: %s", err))
 		return
 	}
 
@@ -333,7 +336,7 @@ func (siw *ServerInterfaceWrapper) CreateResource2(w http.ResponseWriter, r *htt
 
 	err = runtime.BindQueryParameter("form", true, false, "inline_query_argument", r.URL.Query(), &params.InlineQueryArgument)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter inline_query_argument: %s", err), http.StatusBadRequest)
+		badRequest(w, r, "query", "inline_query_argument", fmt.Sprintf("Invalid format for parameter inline_query_argument: %s", err))
 		return
 	}
 
@@ -349,9 +352,9 @@ func (siw *ServerInterfaceWrapper) UpdateResource3(w http.ResponseWriter, r *htt
 	// ------------- Path parameter "fallthrough" -------------
 	var pFallthrough int
 
-	err = runtime.BindStyledParameter("simple", false, "fallthrough", chi.URLParam(r, "fallthrough"), &pFallthrough)
+	err = runtime.BindStyledParameter("simple", false, "fallthrough", pathParam(r, "fallthrough"), &pFallthrough)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter fallthrough: %s", err), http.StatusBadRequest)
+		badRequest(w, r, "path", "fallthrough", fmt.Sprintf("Invalid format for parameter fallthrough: %s", err))
 		return
 	}
 
@@ -370,9 +373,10 @@ func Handler(si ServerInterface) http.Handler {
 	return HandlerFromMux(si, chi.NewRouter())
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux,
+// serving the paths under the ServerBasePath of the spec.
 func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
-	return HandlerFromMuxWithBaseURL(si, r, "")
+	return HandlerFromMuxWithBaseURL(si, r, ServerBasePath)
 }
 
 func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
@@ -413,3 +417,428 @@ func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string)
 
 	return r
 }
+
+// pathParam returns the value of a path parameter, as matched by chi.
+func pathParam(r *http.Request, name string) string {
+	return chi.URLParam(r, name)
+}
+
+// ServerBasePath is the path of the URL of the first server of the spec,
+// which the handlers are registered under unless another base URL is given.
+const ServerBasePath = ""
+
+// badRequest is called by the wrappers when a parameter or body of the
+// request can't be bound.
+func badRequest(w http.ResponseWriter, r *http.Request, location, name, reason string) {
+	http.Error(w, reason, http.StatusBadRequest)
+}
+
+// Ensure that ServerInterfaceMock implements ServerInterface.
+var _ ServerInterface = &ServerInterfaceMock{}
+
+// ServerInterfaceMock is a mock implementation of ServerInterface. Each method records
+// its call, and then calls the function set in the field of the same name
+// with a Func suffix, panicking when it isn't set. The calls are returned by
+// the methods with a Calls suffix, and checked by AssertCalled.
+type ServerInterfaceMock struct {
+	// GetEveryTypeOptionalFunc mocks the GetEveryTypeOptional method.
+	GetEveryTypeOptionalFunc func(w http.ResponseWriter, r *http.Request)
+
+	// GetSimpleFunc mocks the GetSimple method.
+	GetSimpleFunc func(w http.ResponseWriter, r *http.Request)
+
+	// GetWithArgsFunc mocks the GetWithArgs method.
+	GetWithArgsFunc func(w http.ResponseWriter, r *http.Request, params GetWithArgsParams)
+
+	// GetWithReferencesFunc mocks the GetWithReferences method.
+	GetWithReferencesFunc func(w http.ResponseWriter, r *http.Request, globalArgument int64, argument Argument)
+
+	// GetWithContentTypeFunc mocks the GetWithContentType method.
+	GetWithContentTypeFunc func(w http.ResponseWriter, r *http.Request, contentType string)
+
+	// GetReservedKeywordFunc mocks the GetReservedKeyword method.
+	GetReservedKeywordFunc func(w http.ResponseWriter, r *http.Request)
+
+	// CreateResourceFunc mocks the CreateResource method.
+	CreateResourceFunc func(w http.ResponseWriter, r *http.Request, argument Argument)
+
+	// CreateResource2Func mocks the CreateResource2 method.
+	CreateResource2Func func(w http.ResponseWriter, r *http.Request, inlineArgument int, params CreateResource2Params)
+
+	// UpdateResource3Func mocks the UpdateResource3 method.
+	UpdateResource3Func func(w http.ResponseWriter, r *http.Request, pFallthrough int)
+
+	// GetResponseWithReferenceFunc mocks the GetResponseWithReference method.
+	GetResponseWithReferenceFunc func(w http.ResponseWriter, r *http.Request)
+
+	recorder runtime.MockRecorder
+}
+
+var serverInterfaceMockMethods = []string{
+	"GetEveryTypeOptional",
+	"GetSimple",
+	"GetWithArgs",
+	"GetWithReferences",
+	"GetWithContentType",
+	"GetReservedKeyword",
+	"CreateResource",
+	"CreateResource2",
+	"UpdateResource3",
+	"GetResponseWithReference",
+}
+
+// GetEveryTypeOptional records the call, and calls GetEveryTypeOptionalFunc.
+func (mock *ServerInterfaceMock) GetEveryTypeOptional(w http.ResponseWriter, r *http.Request) {
+	mock.recorder.Record("GetEveryTypeOptional", struct {
+		W http.ResponseWriter
+		R *http.Request
+	}{w, r})
+	if mock.GetEveryTypeOptionalFunc == nil {
+		panic("ServerInterfaceMock.GetEveryTypeOptionalFunc: method is nil but ServerInterface.GetEveryTypeOptional was just called")
+	}
+	mock.GetEveryTypeOptionalFunc(w, r)
+}
+
+// GetEveryTypeOptionalCalls returns the calls made to GetEveryTypeOptional, in order.
+func (mock *ServerInterfaceMock) GetEveryTypeOptionalCalls() []struct {
+	W http.ResponseWriter
+	R *http.Request
+} {
+	var calls []struct {
+		W http.ResponseWriter
+		R *http.Request
+	}
+	for _, call := range mock.recorder.Calls("GetEveryTypeOptional") {
+		calls = append(calls, call.Args.(struct {
+			W http.ResponseWriter
+			R *http.Request
+		}))
+	}
+	return calls
+}
+
+// GetSimple records the call, and calls GetSimpleFunc.
+func (mock *ServerInterfaceMock) GetSimple(w http.ResponseWriter, r *http.Request) {
+	mock.recorder.Record("GetSimple", struct {
+		W http.ResponseWriter
+		R *http.Request
+	}{w, r})
+	if mock.GetSimpleFunc == nil {
+		panic("ServerInterfaceMock.GetSimpleFunc: method is nil but ServerInterface.GetSimple was just called")
+	}
+	mock.GetSimpleFunc(w, r)
+}
+
+// GetSimpleCalls returns the calls made to GetSimple, in order.
+func (mock *ServerInterfaceMock) GetSimpleCalls() []struct {
+	W http.ResponseWriter
+	R *http.Request
+} {
+	var calls []struct {
+		W http.ResponseWriter
+		R *http.Request
+	}
+	for _, call := range mock.recorder.Calls("GetSimple") {
+		calls = append(calls, call.Args.(struct {
+			W http.ResponseWriter
+			R *http.Request
+		}))
+	}
+	return calls
+}
+
+// GetWithArgs records the call, and calls GetWithArgsFunc.
+func (mock *ServerInterfaceMock) GetWithArgs(w http.ResponseWriter, r *http.Request, params GetWithArgsParams) {
+	mock.recorder.Record("GetWithArgs", struct {
+		W      http.ResponseWriter
+		R      *http.Request
+		Params GetWithArgsParams
+	}{w, r, params})
+	if mock.GetWithArgsFunc == nil {
+		panic("ServerInterfaceMock.GetWithArgsFunc: method is nil but ServerInterface.GetWithArgs was just called")
+	}
+	mock.GetWithArgsFunc(w, r, params)
+}
+
+// GetWithArgsCalls returns the calls made to GetWithArgs, in order.
+func (mock *ServerInterfaceMock) GetWithArgsCalls() []struct {
+	W      http.ResponseWriter
+	R      *http.Request
+	Params GetWithArgsParams
+} {
+	var calls []struct {
+		W      http.ResponseWriter
+		R      *http.Request
+		Params GetWithArgsParams
+	}
+	for _, call := range mock.recorder.Calls("GetWithArgs") {
+		calls = append(calls, call.Args.(struct {
+			W      http.ResponseWriter
+			R      *http.Request
+			Params GetWithArgsParams
+		}))
+	}
+	return calls
+}
+
+// GetWithReferences records the call, and calls GetWithReferencesFunc.
+func (mock *ServerInterfaceMock) GetWithReferences(w http.ResponseWriter, r *http.Request, globalArgument int64, argument Argument) {
+	mock.recorder.Record("GetWithReferences", struct {
+		W              http.ResponseWriter
+		R              *http.Request
+		GlobalArgument int64
+		Argument       Argument
+	}{w, r, globalArgument, argument})
+	if mock.GetWithReferencesFunc == nil {
+		panic("ServerInterfaceMock.GetWithReferencesFunc: method is nil but ServerInterface.GetWithReferences was just called")
+	}
+	mock.GetWithReferencesFunc(w, r, globalArgument, argument)
+}
+
+// GetWithReferencesCalls returns the calls made to GetWithReferences, in order.
+func (mock *ServerInterfaceMock) GetWithReferencesCalls() []struct {
+	W              http.ResponseWriter
+	R              *http.Request
+	GlobalArgument int64
+	Argument       Argument
+} {
+	var calls []struct {
+		W              http.ResponseWriter
+		R              *http.Request
+		GlobalArgument int64
+		Argument       Argument
+	}
+	for _, call := range mock.recorder.Calls("GetWithReferences") {
+		calls = append(calls, call.Args.(struct {
+			W              http.ResponseWriter
+			R              *http.Request
+			GlobalArgument int64
+			Argument       Argument
+		}))
+	}
+	return calls
+}
+
+// GetWithContentType records the call, and calls GetWithContentTypeFunc.
+func (mock *ServerInterfaceMock) GetWithContentType(w http.ResponseWriter, r *http.Request, contentType string) {
+	mock.recorder.Record("GetWithContentType", struct {
+		W           http.ResponseWriter
+		R           *http.Request
+		ContentType string
+	}{w, r, contentType})
+	if mock.GetWithContentTypeFunc == nil {
+		panic("ServerInterfaceMock.GetWithContentTypeFunc: method is nil but ServerInterface.GetWithContentType was just called")
+	}
+	mock.GetWithContentTypeFunc(w, r, contentType)
+}
+
+// GetWithContentTypeCalls returns the calls made to GetWithContentType, in order.
+func (mock *ServerInterfaceMock) GetWithContentTypeCalls() []struct {
+	W           http.ResponseWriter
+	R           *http.Request
+	ContentType string
+} {
+	var calls []struct {
+		W           http.ResponseWriter
+		R           *http.Request
+		ContentType string
+	}
+	for _, call := range mock.recorder.Calls("GetWithContentType") {
+		calls = append(calls, call.Args.(struct {
+			W           http.ResponseWriter
+			R           *http.Request
+			ContentType string
+		}))
+	}
+	return calls
+}
+
+// GetReservedKeyword records the call, and calls GetReservedKeywordFunc.
+func (mock *ServerInterfaceMock) GetReservedKeyword(w http.ResponseWriter, r *http.Request) {
+	mock.recorder.Record("GetReservedKeyword", struct {
+		W http.ResponseWriter
+		R *http.Request
+	}{w, r})
+	if mock.GetReservedKeywordFunc == nil {
+		panic("ServerInterfaceMock.GetReservedKeywordFunc: method is nil but ServerInterface.GetReservedKeyword was just called")
+	}
+	mock.GetReservedKeywordFunc(w, r)
+}
+
+// GetReservedKeywordCalls returns the calls made to GetReservedKeyword, in order.
+func (mock *ServerInterfaceMock) GetReservedKeywordCalls() []struct {
+	W http.ResponseWriter
+	R *http.Request
+} {
+	var calls []struct {
+		W http.ResponseWriter
+		R *http.Request
+	}
+	for _, call := range mock.recorder.Calls("GetReservedKeyword") {
+		calls = append(calls, call.Args.(struct {
+			W http.ResponseWriter
+			R *http.Request
+		}))
+	}
+	return calls
+}
+
+// CreateResource records the call, and calls CreateResourceFunc.
+func (mock *ServerInterfaceMock) CreateResource(w http.ResponseWriter, r *http.Request, argument Argument) {
+	mock.recorder.Record("CreateResource", struct {
+		W        http.ResponseWriter
+		R        *http.Request
+		Argument Argument
+	}{w, r, argument})
+	if mock.CreateResourceFunc == nil {
+		panic("ServerInterfaceMock.CreateResourceFunc: method is nil but ServerInterface.CreateResource was just called")
+	}
+	mock.CreateResourceFunc(w, r, argument)
+}
+
+// CreateResourceCalls returns the calls made to CreateResource, in order.
+func (mock *ServerInterfaceMock) CreateResourceCalls() []struct {
+	W        http.ResponseWriter
+	R        *http.Request
+	Argument Argument
+} {
+	var calls []struct {
+		W        http.ResponseWriter
+		R        *http.Request
+		Argument Argument
+	}
+	for _, call := range mock.recorder.Calls("CreateResource") {
+		calls = append(calls, call.Args.(struct {
+			W        http.ResponseWriter
+			R        *http.Request
+			Argument Argument
+		}))
+	}
+	return calls
+}
+
+// CreateResource2 records the call, and calls CreateResource2Func.
+func (mock *ServerInterfaceMock) CreateResource2(w http.ResponseWriter, r *http.Request, inlineArgument int, params CreateResource2Params) {
+	mock.recorder.Record("CreateResource2", struct {
+		W              http.ResponseWriter
+		R              *http.Request
+		InlineArgument int
+		Params         CreateResource2Params
+	}{w, r, inlineArgument, params})
+	if mock.CreateResource2Func == nil {
+		panic("ServerInterfaceMock.CreateResource2Func: method is nil but ServerInterface.CreateResource2 was just called")
+	}
+	mock.CreateResource2Func(w, r, inlineArgument, params)
+}
+
+// CreateResource2Calls returns the calls made to CreateResource2, in order.
+func (mock *ServerInterfaceMock) CreateResource2Calls() []struct {
+	W              http.ResponseWriter
+	R              *http.Request
+	InlineArgument int
+	Params         CreateResource2Params
+} {
+	var calls []struct {
+		W              http.ResponseWriter
+		R              *http.Request
+		InlineArgument int
+		Params         CreateResource2Params
+	}
+	for _, call := range mock.recorder.Calls("CreateResource2") {
+		calls = append(calls, call.Args.(struct {
+			W              http.ResponseWriter
+			R              *http.Request
+			InlineArgument int
+			Params         CreateResource2Params
+		}))
+	}
+	return calls
+}
+
+// UpdateResource3 records the call, and calls UpdateResource3Func.
+func (mock *ServerInterfaceMock) UpdateResource3(w http.ResponseWriter, r *http.Request, pFallthrough int) {
+	mock.recorder.Record("UpdateResource3", struct {
+		W            http.ResponseWriter
+		R            *http.Request
+		PFallthrough int
+	}{w, r, pFallthrough})
+	if mock.UpdateResource3Func == nil {
+		panic("ServerInterfaceMock.UpdateResource3Func: method is nil but ServerInterface.UpdateResource3 was just called")
+	}
+	mock.UpdateResource3Func(w, r, pFallthrough)
+}
+
+// UpdateResource3Calls returns the calls made to UpdateResource3, in order.
+func (mock *ServerInterfaceMock) UpdateResource3Calls() []struct {
+	W            http.ResponseWriter
+	R            *http.Request
+	PFallthrough int
+} {
+	var calls []struct {
+		W            http.ResponseWriter
+		R            *http.Request
+		PFallthrough int
+	}
+	for _, call := range mock.recorder.Calls("UpdateResource3") {
+		calls = append(calls, call.Args.(struct {
+			W            http.ResponseWriter
+			R            *http.Request
+			PFallthrough int
+		}))
+	}
+	return calls
+}
+
+// GetResponseWithReference records the call, and calls GetResponseWithReferenceFunc.
+func (mock *ServerInterfaceMock) GetResponseWithReference(w http.ResponseWriter, r *http.Request) {
+	mock.recorder.Record("GetResponseWithReference", struct {
+		W http.ResponseWriter
+		R *http.Request
+	}{w, r})
+	if mock.GetResponseWithReferenceFunc == nil {
+		panic("ServerInterfaceMock.GetResponseWithReferenceFunc: method is nil but ServerInterface.GetResponseWithReference was just called")
+	}
+	mock.GetResponseWithReferenceFunc(w, r)
+}
+
+// GetResponseWithReferenceCalls returns the calls made to GetResponseWithReference, in order.
+func (mock *ServerInterfaceMock) GetResponseWithReferenceCalls() []struct {
+	W http.ResponseWriter
+	R *http.Request
+} {
+	var calls []struct {
+		W http.ResponseWriter
+		R *http.Request
+	}
+	for _, call := range mock.recorder.Calls("GetResponseWithReference") {
+		calls = append(calls, call.Args.(struct {
+			W http.ResponseWriter
+			R *http.Request
+		}))
+	}
+	return calls
+}
+
+// AllCalls returns the calls made to all the methods of the mock, in order.
+func (mock *ServerInterfaceMock) AllCalls() []runtime.MockCall {
+	return mock.recorder.Calls("")
+}
+
+// ResetCalls forgets the calls made to the mock.
+func (mock *ServerInterfaceMock) ResetCalls() {
+	mock.recorder.Reset()
+}
+
+// AssertCalled reports an error to t unless method was called the given
+// number of times, and returns whether it was.
+func (mock *ServerInterfaceMock) AssertCalled(t runtime.TestReporter, method string, times int) bool {
+	t.Helper()
+	return mock.recorder.AssertCalled(t, "ServerInterfaceMock", serverInterfaceMockMethods, method, times)
+}
+
+// AssertNotCalled reports an error to t if method was called, and returns
+// whether it wasn't.
+func (mock *ServerInterfaceMock) AssertNotCalled(t runtime.TestReporter, method string) bool {
+	t.Helper()
+	return mock.recorder.AssertCalled(t, "ServerInterfaceMock", serverInterfaceMockMethods, method, 0)
+}
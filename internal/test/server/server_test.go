@@ -23,4 +23,7 @@ func TestParameters(t *testing.T) {
 	h.ServeHTTP(rr, req)
 
 	assert.Equal(t, 1, len(m.CreateResource2Calls()))
+	assert.Equal(t, 1, m.CreateResource2Calls()[0].InlineArgument)
+	m.AssertCalled(t, "CreateResource2", 1)
+	m.AssertNotCalled(t, "CreateResource")
 }
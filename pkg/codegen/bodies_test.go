@@ -0,0 +1,321 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const requestBodiesOpenAPIDefinition = `
+openapi: 3.0.1
+info:
+  title: Request bodies
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+          application/x-www-form-urlencoded:
+            schema:
+              $ref: '#/components/schemas/Pet'
+          application/xml:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        204:
+          description: Success
+  /pets/{id}/notes:
+    put:
+      operationId: setNotes
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      requestBody:
+        content:
+          text/plain:
+            schema:
+              type: string
+      responses:
+        204:
+          description: Success
+  /pets/{id}/photo:
+    put:
+      operationId: setPhoto
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      requestBody:
+        content:
+          application/octet-stream:
+            schema:
+              type: string
+              format: binary
+      responses:
+        204:
+          description: Success
+  /pets/{id}/files:
+    post:
+      operationId: uploadFiles
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              required:
+              - photo
+              properties:
+                description:
+                  type: string
+                photo:
+                  type: string
+                  format: binary
+                attachments:
+                  type: array
+                  items:
+                    type: string
+                    format: binary
+            encoding:
+              photo:
+                contentType: image/png, image/jpeg
+                headers:
+                  X-Checksum:
+                    required: true
+                    schema:
+                      type: string
+              description:
+                contentType: text/plain
+      responses:
+        204:
+          description: Success
+components:
+  schemas:
+    Pet:
+      required:
+      - name
+      properties:
+        name:
+          type: string
+        tag:
+          type: string
+`
+
+func TestGenerateBodyDefinitions(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(requestBodiesOpenAPIDefinition))
+	require.NoError(t, err)
+
+	bodies, typeDefs, err := GenerateBodyDefinitions("AddPet", swagger.Paths["/pets"].Post.RequestBody)
+	require.NoError(t, err)
+
+	// Bodies come out sorted by content type, and XML is left to the user.
+	require.Len(t, bodies, 2)
+	assert.Equal(t, "application/json", bodies[0].ContentType)
+	assert.Equal(t, "JSON", bodies[0].NameTag)
+	assert.True(t, bodies[0].Default)
+	assert.Equal(t, "", bodies[0].Suffix())
+	assert.Equal(t, "application/x-www-form-urlencoded", bodies[1].ContentType)
+	assert.Equal(t, "Formdata", bodies[1].NameTag)
+	assert.False(t, bodies[1].Default)
+	assert.Equal(t, "WithFormdataBody", bodies[1].Suffix())
+	assert.True(t, bodies[1].IsFormEncoded())
+
+	require.Len(t, typeDefs, 2)
+	assert.Equal(t, "AddPetJSONBody", typeDefs[0].TypeName)
+	assert.Equal(t, "AddPetFormdataBody", typeDefs[1].TypeName)
+	assert.Equal(t, "Pet", typeDefs[1].Schema.TypeDecl())
+
+	bodies, typeDefs, err = GenerateBodyDefinitions("SetPhoto", swagger.Paths["/pets/{id}/photo"].Put.RequestBody)
+	require.NoError(t, err)
+	require.Len(t, bodies, 1)
+	assert.True(t, bodies[0].IsBinary())
+	require.Len(t, typeDefs, 1)
+	assert.Equal(t, "io.Reader", typeDefs[0].Schema.TypeDecl())
+}
+
+func TestGenerateWithNonJSONBodies(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(requestBodiesOpenAPIDefinition))
+	require.NoError(t, err)
+
+	for _, opts := range []Options{
+		{GenerateTypes: true, GenerateClient: true, GenerateEchoServer: true},
+		{GenerateTypes: true, GenerateClient: true, GenerateChiServer: true},
+	} {
+		code, err := Generate(swagger, "api", opts)
+		require.NoError(t, err)
+
+		_, err = format.Source([]byte(code))
+		assert.NoError(t, err)
+
+		assert.Contains(t, code, "type AddPetFormdataRequestBody AddPetFormdataBody")
+		assert.Contains(t, code, "type SetNotesTextBody string")
+		assert.Contains(t, code, "type SetPhotoOctetStreamBody io.Reader")
+
+		// Typed client helpers encode the body for its content type.
+		assert.Contains(t, code, "func (c *Client) AddPetWithFormdataBody(ctx context.Context, body AddPetFormdataRequestBody) (*http.Response, error) {")
+		assert.Contains(t, code, "form, err := runtime.MarshalForm(body)")
+		assert.Contains(t, code, "bodyReader = strings.NewReader(string(body))")
+		assert.Contains(t, code, `return NewSetPhotoRequestWithBody(server, id, "application/octet-stream", bodyReader)`)
+
+		// The server wrappers decode the body, and pass it to the handler.
+		assert.Contains(t, code, "runtime.BindForm(")
+		assert.Contains(t, code, "var formdataBody *AddPetFormdataRequestBody")
+		assert.Contains(t, code, "formdataBody = &body")
+		assert.Contains(t, code, "textBody = &body")
+		assert.Contains(t, code, "octetStreamBody = body")
+		assert.Contains(t, code, ", formdataBody *AddPetFormdataRequestBody)")
+		assert.Contains(t, code, ", id int, textBody *SetNotesTextRequestBody)")
+		assert.Contains(t, code, ", id int, octetStreamBody SetPhotoOctetStreamRequestBody)")
+		assert.Contains(t, code, ", formdataBody)")
+		assert.Contains(t, code, ", id, octetStreamBody)")
+
+		requireCompiles(t, code)
+	}
+}
+
+func TestGenerateMultipartBody(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(requestBodiesOpenAPIDefinition))
+	require.NoError(t, err)
+
+	bodies, _, err := GenerateBodyDefinitions("UploadFiles", swagger.Paths["/pets/{id}/files"].Post.RequestBody)
+	require.NoError(t, err)
+	require.Len(t, bodies, 1)
+	assert.True(t, bodies[0].IsMultipart())
+	assert.Equal(t, []RequestBodyEncoding{
+		{PropertyName: "description", ContentType: "text/plain", Headers: map[string]bool{}},
+		{PropertyName: "photo", ContentType: "image/png, image/jpeg", Headers: map[string]bool{"X-Checksum": true}},
+	}, bodies[0].Encodings)
+	assert.Equal(t, `map[string]runtime.MultipartEncoding{"description": {ContentType: "text/plain"}, "photo": {ContentType: "image/png, image/jpeg", Headers: map[string]bool{"X-Checksum": true}}}`,
+		genMultipartEncodings(bodies[0].Encodings))
+	assert.Equal(t, "nil", genMultipartEncodings(nil))
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true, GenerateEchoServer: true})
+	require.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Binary properties are files, which are streamed by the client, and
+	// streamed to the handler by the server, rather than parsed first.
+	assert.True(t, bodies[0].HasFileParts())
+	assert.Contains(t, code, "Photo       openapi_types.File ")
+	assert.Contains(t, code, "Attachments *[]openapi_types.File ")
+	assert.Contains(t, code, "contentType, bodyReader := runtime.NewMultipartBody(body, ")
+	assert.Contains(t, code, "return NewUploadFilesRequestWithBody(server, id, contentType, bodyReader)")
+	assert.Contains(t, code, "mr, err := runtime.NewMultipartReader(ctx.Request(), ")
+	assert.NotContains(t, code, "ParseMultipartForm")
+	assert.Contains(t, code, "UploadFiles(ctx echo.Context, id int, multipartBody *runtime.MultipartReader) error")
+	assert.Contains(t, code, "err = w.Handler.UploadFiles(ctx, id, multipartBody)")
+	requireCompiles(t, code)
+}
+
+func TestGenerateMultipartBodyWithoutFiles(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: Multipart body without files
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              properties:
+                name:
+                  type: string
+      responses:
+        204:
+          description: Success
+`))
+	require.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateChiServer: true, GenerateStrictServer: true})
+	require.NoError(t, err)
+
+	// Without files to stream, the body is parsed and bound for the handler.
+	assert.Contains(t, code, "r.ParseMultipartForm(runtime.MaxMultipartMemory)")
+	assert.Contains(t, code, "runtime.BindMultipart(r.MultipartForm, &body, nil)")
+	assert.Contains(t, code, "AddPet(w http.ResponseWriter, r *http.Request, multipartBody *AddPetMultipartRequestBody)")
+	assert.NotContains(t, code, "NewMultipartReader")
+	requireCompiles(t, code)
+}
+
+const binaryPropertiesOpenAPIDefinition = `
+openapi: 3.0.1
+info:
+  title: Binary properties
+  version: 1.0.0
+paths:
+  /avatars:
+    put:
+      operationId: setAvatar
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Avatar'
+          multipart/form-data:
+            schema:
+              $ref: '#/components/schemas/Avatar'
+      responses:
+        204:
+          description: Success
+components:
+  schemas:
+    Avatar:
+      required:
+      - image
+      properties:
+        image:
+          type: string
+          format: binary
+        owner:
+          $ref: '#/components/schemas/Owner'
+    Owner:
+      properties:
+        name:
+          type: string
+        signature:
+          type: string
+          format: binary
+`
+
+func TestGenerateBinaryProperties(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(binaryPropertiesOpenAPIDefinition))
+	require.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true, GenerateEchoServer: true})
+	require.NoError(t, err)
+
+	// Binary strings are strings in JSON, and in the component types.
+	assert.Contains(t, code, "type Avatar struct {\n\tImage string `json:\"image\"`")
+	assert.Contains(t, code, "Signature *string `json:\"signature,omitempty\"`")
+	assert.Contains(t, code, "type SetAvatarJSONBody Avatar")
+
+	// The multipart body gets a type of its own, in which they're files.
+	assert.Contains(t, code, "type SetAvatarMultipartBody struct {\n\tImage openapi_types.File `json:\"image\"`")
+	assert.Contains(t, code, "type SetAvatarMultipartRequestBody SetAvatarMultipartBody")
+
+	requireCompiles(t, code)
+}
@@ -0,0 +1,119 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateBuilders(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testOpenAPIDefinition))
+	require.NoError(t, err)
+
+	opts := Options{
+		GenerateTypes:    true,
+		GenerateBuilders: true,
+	}
+	code, err := Generate(swagger, "testswagger", opts)
+	require.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Setters for optional fields of component schemas
+	assert.Contains(t, code, "func (t *Test) WithName(value string) *Test {")
+	assert.Contains(t, code, "func (t *Test) WithCases(value []TestCase) *Test {")
+	assert.Contains(t, code, "func (t *CatDead) WithDeadSince(value time.Time) *CatDead {")
+
+	// Setters and functional options for parameters objects
+	assert.Contains(t, code, "func (t *GetTestByNameParams) WithTop(value int) *GetTestByNameParams {")
+	assert.Contains(t, code, "type GetTestByNameParamsOption func(*GetTestByNameParams)")
+	assert.Contains(t, code, "func NewGetTestByNameParams(opts ...GetTestByNameParamsOption) *GetTestByNameParams {")
+	assert.Contains(t, code, "func GetTestByNameParamsWithTop(value int) GetTestByNameParamsOption {")
+	requireCompiles(t, code)
+}
+
+func TestGenerateBuildersRequiredParams(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: Required parameters
+  version: 1.0.0
+paths:
+  /search:
+    get:
+      operationId: search
+      parameters:
+      - name: type
+        in: query
+        required: true
+        schema:
+          type: string
+      - name: X-Request-Id
+        in: header
+        required: true
+        schema:
+          type: string
+      - name: page
+        in: query
+        schema:
+          type: integer
+      responses:
+        200:
+          description: Success
+`))
+	require.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateBuilders: true})
+	require.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "func NewSearchParams(pType string, xRequestId string, opts ...SearchParamsOption) *SearchParams {")
+	assert.Contains(t, code, "func SearchParamsWithPage(value int) SearchParamsOption {")
+	assert.NotContains(t, code, "func (t *SearchParams) WithType(")
+	requireCompiles(t, code)
+}
+
+func TestGenerateBuildersReservedArgNames(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: Parameters named like the locals of constructors
+  version: 1.0.0
+paths:
+  /search:
+    get:
+      operationId: search
+      parameters:
+      - name: params
+        in: query
+        required: true
+        schema:
+          type: string
+      - name: opts
+        in: query
+        required: true
+        schema:
+          type: string
+      - name: o
+        in: query
+        required: true
+        schema:
+          type: string
+      responses:
+        200:
+          description: Success
+`))
+	require.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateBuilders: true})
+	require.NoError(t, err)
+
+	assert.Contains(t, code, "func NewSearchParams(pParams string, pOpts string, o string, opts ...SearchParamsOption) *SearchParams {")
+	requireCompiles(t, code)
+}
@@ -0,0 +1,213 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package codegen
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pkg/errors"
+)
+
+// This describes the callback which an operation definition belongs to.
+type CallbackDefinition struct {
+	// The name of the callback in the spec, eg, onData
+	Name string
+
+	// The ID of the operation which declares the callback
+	ParentOperationId string
+
+	// The URL the callback is sent to, which is the key of the callback in
+	// the spec, without the path of the operation, eg,
+	// {$request.body#/callbackUrl}
+	URL string
+}
+
+// callbackOperation is an operation of a callback, along with where it's
+// declared in the spec.
+type callbackOperation struct {
+	Callback CallbackDefinition
+	Path     string // The path of the operation, following the callback URL
+	Method   string
+	PathItem *openapi3.PathItem
+	Op       *openapi3.Operation
+	Location string // A reference to the operation in the spec
+}
+
+// SortedCallbackKeys returns the callback URL expressions of a callback, sorted.
+func SortedCallbackKeys(dict openapi3.Callback) []string {
+	keys := make([]string, len(dict))
+	i := 0
+	for key := range dict {
+		keys[i] = key
+		i++
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SortedCallbackRefKeys returns the names of the callbacks of an operation,
+// sorted.
+func SortedCallbackRefKeys(dict map[string]*openapi3.CallbackRef) []string {
+	keys := make([]string, len(dict))
+	i := 0
+	for key := range dict {
+		keys[i] = key
+		i++
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// splitCallbackURL splits the key of a callback, which is the URL to send it
+// to, into the part which the server URL of the callback client is resolved
+// from, and a path which follows the last runtime expression. That path is
+// what subscribers serve the callback at, relative to the URL they
+// subscribed with. The key {$request.body#/callbackUrl}/data is split into
+// {$request.body#/callbackUrl} and /data, while keys which end with a
+// runtime expression, or a query, have no path.
+func splitCallbackURL(key string) (string, string) {
+	start := strings.LastIndex(key, "{$")
+	if start == -1 {
+		return key, ""
+	}
+	end := strings.Index(key[start:], "}")
+	if end == -1 {
+		return key, ""
+	}
+	end += start + 1
+	path := key[end:]
+	if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, "?#") {
+		return key, ""
+	}
+	return key[:end], path
+}
+
+// callbackOperationIDFor returns the operation ID of an operation of a
+// callback, which defaults to the method followed by the IDs of the
+// operation declaring the callback and the callback name, eg,
+// PostSubscribeOnData.
+func callbackOperationIDFor(c callbackOperation) (string, error) {
+	if c.Op.OperationID == "" {
+		return generateDefaultOperationID(c.Method, "/"+c.Callback.ParentOperationId+"/"+c.Callback.Name)
+	}
+	return ToCamelCase(c.Op.OperationID), nil
+}
+
+// callbackOperations returns the operations of all the callbacks declared by
+// the operations of the spec, ordered by the operation declaring them, then
+// callback name, URL and method.
+func callbackOperations(swagger *openapi3.Swagger) ([]callbackOperation, error) {
+	var result []callbackOperation
+	for _, requestPath := range SortedPathsKeys(swagger.Paths) {
+		pathOps := swagger.Paths[requestPath].Operations()
+		for _, method := range SortedOperationsKeys(pathOps) {
+			op := pathOps[method]
+			parentID, err := operationIDFor(method, requestPath, op)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range SortedCallbackRefKeys(op.Callbacks) {
+				callback := op.Callbacks[name].Value
+				if callback == nil {
+					continue
+				}
+				for _, key := range SortedCallbackKeys(*callback) {
+					pathItem := (*callback)[key]
+					url, path := splitCallbackURL(key)
+					cbOps := pathItem.Operations()
+					for _, cbMethod := range SortedOperationsKeys(cbOps) {
+						result = append(result, callbackOperation{
+							Callback: CallbackDefinition{
+								Name:              name,
+								ParentOperationId: parentID,
+								URL:               url,
+							},
+							Path:     path,
+							Method:   cbMethod,
+							PathItem: pathItem,
+							Op:       cbOps[cbMethod],
+							Location: fmt.Sprintf("%s/callbacks/%s/%s/%s", operationRef(requestPath, method),
+								escapeJSONPointer(name), escapeJSONPointer(key), strings.ToLower(cbMethod)),
+						})
+					}
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// CallbackOperationDefinitions returns the operations of the callbacks
+// declared by the operations of a swagger definition. Their paths are
+// relative to the callback URL, so the client sends them to the URL which
+// it's created with, and the servers serve them under their base URL.
+// Callbacks don't inherit the global security requirements of the spec.
+func CallbackOperationDefinitions(swagger *openapi3.Swagger) ([]OperationDefinition, error) {
+	return callbackOperationDefinitions(swagger, nil)
+}
+
+func callbackOperationDefinitions(swagger *openapi3.Swagger, overrides typeNameOverrides) ([]OperationDefinition, error) {
+	cbOps, err := callbackOperations(swagger)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []OperationDefinition
+	for _, c := range cbOps {
+		c.Op.OperationID, err = callbackOperationIDFor(c)
+		if err != nil {
+			return nil, fmt.Errorf("error generating default OperationID for callback %s of %s: %s",
+				c.Callback.Name, c.Callback.ParentOperationId, err)
+		}
+
+		globalParams, err := describeParameters(c.PathItem.Parameters, nil, overrides)
+		if err != nil {
+			return nil, fmt.Errorf("error describing global parameters for callback %s of %s: %s",
+				c.Callback.Name, c.Callback.ParentOperationId, err)
+		}
+
+		opDef, err := describeOperation(c.Path, c.Method, c.Op, globalParams, nil, overrides)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error describing callback %s of %s",
+				c.Callback.Name, c.Callback.ParentOperationId)
+		}
+		callback := c.Callback
+		opDef.Callback = &callback
+		operations = append(operations, opDef)
+	}
+	return operations, nil
+}
+
+// GenerateCallbackURLs generates the functions which resolve the URLs to send
+// callbacks to from the requests which registered them.
+func GenerateCallbackURLs(t *template.Template, ops []OperationDefinition) (string, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	err := t.ExecuteTemplate(w, "callback-urls.tmpl", ops)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating callback URLs")
+	}
+	err = w.Flush()
+	if err != nil {
+		return "", errors.Wrap(err, "error flushing output buffer for callback URLs")
+	}
+	return buf.String(), nil
+}
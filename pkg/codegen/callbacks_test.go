@@ -0,0 +1,178 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const callbacksOpenAPIDefinition = `
+openapi: 3.0.1
+info:
+  title: Callbacks
+  version: 1.0.0
+security:
+- apiKey: []
+paths:
+  /subscriptions:
+    post:
+      operationId: subscribe
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Subscription'
+      responses:
+        201:
+          description: Subscribed
+      callbacks:
+        onEvent:
+          '{$request.body#/callbackUrl}':
+            post:
+              requestBody:
+                required: true
+                content:
+                  application/json:
+                    schema:
+                      $ref: '#/components/schemas/Event'
+              responses:
+                204:
+                  description: Received
+          '{$request.body#/callbackUrl}/errors/{code}':
+            post:
+              operationId: reportError
+              parameters:
+              - name: code
+                in: path
+                required: true
+                schema:
+                  type: integer
+              - name: X-Attempt
+                in: header
+                schema:
+                  type: integer
+              responses:
+                200:
+                  description: Received
+                  content:
+                    application/json:
+                      schema:
+                        $ref: '#/components/schemas/Ack'
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+  schemas:
+    Subscription:
+      required: [callbackUrl]
+      properties:
+        callbackUrl:
+          type: string
+    Event:
+      required: [name]
+      properties:
+        name:
+          type: string
+    Ack:
+      properties:
+        ok:
+          type: boolean
+`
+
+func TestSplitCallbackURL(t *testing.T) {
+	tests := []struct {
+		key  string
+		url  string
+		path string
+	}{
+		{"{$request.body#/callbackUrl}", "{$request.body#/callbackUrl}", ""},
+		{"{$request.body#/callbackUrl}/data/{id}", "{$request.body#/callbackUrl}", "/data/{id}"},
+		{"http://notify.com/{$request.query.topic}/data", "http://notify.com/{$request.query.topic}", "/data"},
+		{"http://notify.com?id={$request.body#/id}", "http://notify.com?id={$request.body#/id}", ""},
+		{"{$request.body#/callbackUrl}/data?full=true", "{$request.body#/callbackUrl}/data?full=true", ""},
+		{"http://notify.com/data", "http://notify.com/data", ""},
+	}
+	for _, tt := range tests {
+		url, path := splitCallbackURL(tt.key)
+		assert.Equal(t, tt.url, url, tt.key)
+		assert.Equal(t, tt.path, path, tt.key)
+	}
+}
+
+func TestCallbackOperationDefinitions(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(callbacksOpenAPIDefinition))
+	require.NoError(t, err)
+
+	ops, err := CallbackOperationDefinitions(swagger)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+
+	assert.Equal(t, "PostSubscribeOnEvent", ops[0].OperationId)
+	assert.Equal(t, "", ops[0].Path)
+	assert.Equal(t, &CallbackDefinition{
+		Name:              "onEvent",
+		ParentOperationId: "Subscribe",
+		URL:               "{$request.body#/callbackUrl}",
+	}, ops[0].Callback)
+	// Callbacks don't inherit the global security requirements.
+	assert.Empty(t, ops[0].SecurityDefinitions)
+
+	assert.Equal(t, "ReportError", ops[1].OperationId)
+	assert.Equal(t, "/errors/{code}", ops[1].Path)
+	require.Len(t, ops[1].PathParams, 1)
+	assert.Equal(t, "code", ops[1].PathParams[0].ParamName)
+	require.Len(t, ops[1].HeaderParams, 1)
+
+	ops, err = OperationDefinitions(swagger)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	assert.Nil(t, ops[0].Callback)
+}
+
+func TestGenerateCallbacks(t *testing.T) {
+	for _, opts := range []Options{
+		{GenerateTypes: true, GenerateClient: true, GenerateEchoServer: true, GenerateCallbacks: true},
+		{GenerateTypes: true, GenerateClient: true, GenerateChiServer: true, GenerateCallbacks: true},
+		{GenerateTypes: true, GenerateClient: true, GenerateStdHTTPServer: true, GenerateCallbacks: true},
+	} {
+		swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(callbacksOpenAPIDefinition))
+		require.NoError(t, err)
+
+		code, err := Generate(swagger, "callbacks", opts)
+		require.NoError(t, err)
+
+		_, err = format.Source([]byte(code))
+		assert.NoError(t, err)
+
+		// The client sends the callbacks to the URL it's created with.
+		assert.Contains(t, code, "func NewPostSubscribeOnEventRequest(server string, body PostSubscribeOnEventJSONRequestBody) (*http.Request, error) {")
+		assert.Contains(t, code, "func (c *Client) ReportError(ctx context.Context, code int, params *ReportErrorParams) (*http.Response, error) {")
+		assert.Contains(t, code, "func PostSubscribeOnEventCallbackURL(source runtime.ExpressionSource) (string, error) {")
+		assert.Contains(t, code, `return runtime.ExpandExpressions("{$request.body#/callbackUrl}", source)`)
+		assert.NotContains(t, code, `client.Server += "/"`)
+
+		// Subscribers implement the server.
+		assert.Contains(t, code, "type ServerInterface interface {")
+		assert.Contains(t, code, "ReportError(")
+
+		// The operations of the API itself are left out.
+		assert.NotContains(t, code, "Subscribe(")
+	}
+
+	// Without callbacks mode, the callbacks are left out.
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(callbacksOpenAPIDefinition))
+	require.NoError(t, err)
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true, GenerateEchoServer: true})
+	require.NoError(t, err)
+	assert.Contains(t, code, "Subscribe(ctx echo.Context) error")
+	// The server URL of API clients is a base URL, which gets a trailing slash.
+	assert.Contains(t, code, `client.Server += "/"`)
+	assert.NotContains(t, code, "ReportError")
+	assert.NotContains(t, code, "CallbackURL")
+}
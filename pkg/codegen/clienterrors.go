@@ -0,0 +1,157 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package codegen
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// This describes a response declared by an operation for statuses other
+// than 2XX, which the client returns a typed error for.
+type ErrorResponseDefinition struct {
+	// The name of the error type, eg, FindPets404Error
+	TypeName string
+
+	// The name of the response in the spec, eg, 404, 4XX or default
+	ResponseName string
+
+	// The decoded payloads of the response, which are the fields of the same
+	// name in the response type of the client, eg, JSON404
+	Payloads []TypeDefinition
+
+	// The headers of the response, if it declares any
+	Headers *ResponseHeadersDefinition
+}
+
+// Returns whether this is the default response, which any status not
+// matched by another response falls back to.
+func (e ErrorResponseDefinition) IsDefault() bool {
+	return e.ResponseName == "default"
+}
+
+// The condition on the status code under which the response is returned
+func (e ErrorResponseDefinition) StatusCondition() string {
+	return getConditionOfResponseName("code", e.ResponseName)
+}
+
+// isSuccessResponseName returns whether a response name denotes a 2XX
+// status.
+func isSuccessResponseName(responseName string) bool {
+	return len(responseName) == 3 && responseName[0] == '2'
+}
+
+// ErrorResponses returns the responses declared for statuses other than 2XX,
+// ordered so that specific status codes come before the range they're in,
+// and default comes last.
+func (o *OperationDefinition) ErrorResponses() ([]ErrorResponseDefinition, error) {
+	typeDefinitions, err := o.GetResponseTypeDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ErrorResponseDefinition
+	for _, responseName := range SortedResponsesKeys(o.Spec.Responses) {
+		if isSuccessResponseName(responseName) || o.Spec.Responses[responseName].Value == nil {
+			continue
+		}
+		e := ErrorResponseDefinition{
+			TypeName:     o.OperationId + ToCamelCase(responseName) + "Error",
+			ResponseName: responseName,
+		}
+		for _, td := range typeDefinitions {
+			if td.ResponseName == responseName && td.Schema.TypeDecl() != "" {
+				e.Payloads = append(e.Payloads, td)
+			}
+		}
+		for i, rh := range o.ResponseHeaders {
+			if rh.ResponseName == responseName {
+				e.Headers = &o.ResponseHeaders[i]
+			}
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// ErrTypesDescription describes the errors which the Err method of the
+// response of the operation returns for statuses other than 2XX, eg, "a
+// *FindPets404Error or a *runtime.UnexpectedResponseError for statuses which
+// FindPets doesn't declare a response for".
+func (o *OperationDefinition) ErrTypesDescription() (string, error) {
+	errorResponses, err := o.ErrorResponses()
+	if err != nil {
+		return "", err
+	}
+
+	var types []string
+	hasDefault := false
+	for _, e := range errorResponses {
+		types = append(types, "a *"+e.TypeName)
+		hasDefault = hasDefault || e.IsDefault()
+	}
+	if !hasDefault {
+		types = append(types, "a *runtime.UnexpectedResponseError for statuses which "+
+			o.OperationId+" doesn't declare a response for")
+	}
+	if len(types) == 1 {
+		return types[0], nil
+	}
+	return strings.Join(types[:len(types)-1], ", ") + " or " + types[len(types)-1], nil
+}
+
+// SuccessPayloads returns the JSON payloads of the 2XX responses of the
+// operation, which the WithResult methods of the client return. Those
+// methods only return a payload when every 2XX response with a JSON payload
+// has the same type, so nothing is returned when the types differ.
+func (o *OperationDefinition) SuccessPayloads() ([]TypeDefinition, error) {
+	typeDefinitions, err := o.GetResponseTypeDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []TypeDefinition
+	for _, td := range typeDefinitions {
+		if !isSuccessResponseName(td.ResponseName) || td.TypeName != "JSON"+ToCamelCase(td.ResponseName) {
+			continue
+		}
+		if len(result) > 0 && result[0].Schema.TypeDecl() != td.Schema.TypeDecl() {
+			return nil, nil
+		}
+		result = append(result, td)
+	}
+	return result, nil
+}
+
+// GenerateClientErrors generates the error types returned for the error
+// responses of operations, the Err methods of the response types of the
+// client, and the WithResult methods of ClientWithResponses.
+func GenerateClientErrors(t *template.Template, ops []OperationDefinition) (string, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	err := t.ExecuteTemplate(w, "client-errors.tmpl", ops)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating client errors")
+	}
+	err = w.Flush()
+	if err != nil {
+		return "", errors.Wrap(err, "error flushing output buffer for client errors")
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,172 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const clientErrorsOpenAPIDefinition = `
+openapi: 3.0.1
+info:
+  title: Client errors
+  version: 1.0.0
+paths:
+  /things/{id}:
+    get:
+      operationId: getThing
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+      responses:
+        200:
+          description: Found
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Thing'
+        404:
+          description: Not found
+          headers:
+            X-Reason:
+              schema:
+                type: string
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Problem'
+        4XX:
+          description: Client error
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Problem'
+    delete:
+      operationId: deleteThing
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+      responses:
+        204:
+          description: Deleted
+        default:
+          description: Error
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Problem'
+components:
+  schemas:
+    Thing:
+      type: object
+      properties:
+        name:
+          type: string
+    Problem:
+      type: object
+      properties:
+        title:
+          type: string
+`
+
+func TestClientErrors(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(clientErrorsOpenAPIDefinition))
+	require.NoError(t, err)
+
+	ops, err := OperationDefinitions(swagger)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+
+	errorResponses, err := ops[1].ErrorResponses()
+	require.NoError(t, err)
+	require.Len(t, errorResponses, 2)
+	assert.Equal(t, "GetThing404Error", errorResponses[0].TypeName)
+	assert.Equal(t, "code == 404", errorResponses[0].StatusCondition())
+	require.NotNil(t, errorResponses[0].Headers)
+	assert.Equal(t, "Headers404", errorResponses[0].Headers.FieldName())
+	assert.Equal(t, "GetThing4XXError", errorResponses[1].TypeName)
+
+	payloads, err := ops[1].SuccessPayloads()
+	require.NoError(t, err)
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "JSON200", payloads[0].TypeName)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true, GenerateClientErrors: true})
+	require.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// Declared error responses get error types holding their payloads.
+	assert.Contains(t, code, "type GetThing404Error struct {")
+	assert.Contains(t, code, "JSON404      *Problem")
+	assert.Contains(t, code, "Headers404   *GetThing404ResponseHeaders")
+	assert.Contains(t, code, "func (e *GetThing4XXError) Error() string {")
+	assert.Contains(t, code, "func (e *DeleteThingDefaultError) Response() *http.Response {")
+
+	// Undeclared statuses fall back to runtime.UnexpectedResponseError,
+	// unless there's a default response.
+	assert.Contains(t, code, "func (r GetThingResponse) Err() error {")
+	assert.Contains(t, code, "case code == 404:\n\t\treturn &GetThing404Error{")
+	assert.Contains(t, code, "case code/100 == 4:\n\t\treturn &GetThing4XXError{")
+	assert.Contains(t, code, "default:\n\t\treturn &runtime.UnexpectedResponseError{")
+	assert.Contains(t, code, "default:\n\t\treturn &DeleteThingDefaultError{")
+	assert.Contains(t, code, "// which is a *GetThing404Error, a *GetThing4XXError or a *runtime.UnexpectedResponseError for statuses which GetThing doesn't declare a response for.\nfunc (r GetThingResponse) Err() error {")
+	assert.Contains(t, code, "// which is a *DeleteThingDefaultError.\nfunc (r DeleteThingResponse) Err() error {")
+
+	// The WithResult methods return the success payload, if any.
+	assert.Contains(t, code, "func (c *ClientWithResponses) GetThingWithResult(ctx context.Context, id string) (*Thing, error) {")
+	assert.Contains(t, code, "func (c *ClientWithResponses) DeleteThingWithResult(ctx context.Context, id string) error {")
+
+	// Nothing is generated without the option.
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true})
+	require.NoError(t, err)
+	assert.NotContains(t, code, "GetThing404Error")
+	assert.NotContains(t, code, "WithResult")
+}
+
+func TestClientErrorsCollision(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(clientErrorsOpenAPIDefinition))
+	require.NoError(t, err)
+	swagger.Components.Schemas["GetThing404Error"] = swagger.Components.Schemas["Problem"]
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true, GenerateClientErrors: true, SkipPrune: true})
+	require.NoError(t, err)
+	assert.Contains(t, code, "type GetThing404Error struct {")
+	assert.Contains(t, code, "type GetThing404ErrorSchema ")
+}
+
+func TestClientErrorsWithoutErrorResponses(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: Client errors
+  version: 1.0.0
+paths:
+  /things:
+    get:
+      operationId: listThings
+      responses:
+        200:
+          description: Found
+`))
+	require.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true, GenerateClientErrors: true})
+	require.NoError(t, err)
+
+	// Only undeclared statuses are errors, which the comment of Err says
+	// without a dangling conjunction.
+	assert.Contains(t, code, "// which is a *runtime.UnexpectedResponseError for statuses which ListThings doesn't declare a response for.\n")
+	assert.NotContains(t, code, "which is or")
+	requireCompiles(t, code)
+}
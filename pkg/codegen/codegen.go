@@ -26,23 +26,30 @@ import (
 	"github.com/pkg/errors"
 	"golang.org/x/tools/imports"
 
-	"github.com/deepmap/oapi-codegen/pkg/codegen/templates"
+	"github.com/leslie-wang/oapi-codegen/pkg/codegen/templates"
 )
 
 // Options defines the optional code to generate.
 type Options struct {
-	GenerateChiServer  bool              // GenerateChiServer specifies whether to generate chi server boilerplate
-	GenerateEchoServer bool              // GenerateEchoServer specifies whether to generate echo server boilerplate
-	GenerateClient     bool              // GenerateClient specifies whether to generate client boilerplate
-	GenerateTypes      bool              // GenerateTypes specifies whether to generate type definitions
-	EmbedSpec          bool              // Whether to embed the swagger spec in the generated code
-	SkipFmt            bool              // Whether to skip go imports on the generated code
-	SkipPrune          bool              // Whether to skip pruning unused components on the generated code
-	IncludeTags        []string          // Only include operations that have one of these tags. Ignored when empty.
-	ExcludeTags        []string          // Exclude operations that have one of these tags. Ignored when empty.
-	UserTemplates      map[string]string // Override built-in templates from user-provided files
-	ImportMapping      map[string]string // ImportMapping specifies the golang package path for each external reference
-	ExcludeSchemas     []string          // Exclude from generation schemas with given names. Ignored when empty.
+	GenerateChiServer     bool              // GenerateChiServer specifies whether to generate chi server boilerplate
+	GenerateEchoServer    bool              // GenerateEchoServer specifies whether to generate echo server boilerplate
+	GenerateStdHTTPServer bool              // GenerateStdHTTPServer specifies whether to generate net/http server boilerplate, which does its own routing
+	GenerateStrictServer  bool              // GenerateStrictServer specifies whether to generate a strict server adapter for the echo, chi or net/http server
+	GenerateClient        bool              // GenerateClient specifies whether to generate client boilerplate
+	GenerateClientErrors  bool              // GenerateClientErrors specifies whether to generate typed errors for the non-success responses of the client
+	GenerateTypes         bool              // GenerateTypes specifies whether to generate type definitions
+	GenerateBuilders      bool              // GenerateBuilders specifies whether to generate setters for optional fields and params constructors
+	GenerateMocks         bool              // GenerateMocks specifies whether to generate recording mocks of the generated server and client interfaces
+	ProblemDetails        bool              // ProblemDetails specifies whether the server wrappers respond to parameters which can't be bound with RFC 7807 problem details
+	GenerateCallbacks     bool              // GenerateCallbacks specifies whether to generate the client and servers for the callbacks of operations, instead of the operations
+	EmbedSpec             bool              // Whether to embed the swagger spec in the generated code
+	SkipFmt               bool              // Whether to skip go imports on the generated code
+	SkipPrune             bool              // Whether to skip pruning unused components on the generated code
+	IncludeTags           []string          // Only include operations that have one of these tags. Ignored when empty.
+	ExcludeTags           []string          // Exclude operations that have one of these tags. Ignored when empty.
+	UserTemplates         map[string]string // Override built-in templates from user-provided files
+	ImportMapping         map[string]string // ImportMapping specifies the golang package path for each external reference
+	ExcludeSchemas        []string          // Exclude from generation schemas with given names. Ignored when empty.
 }
 
 // goImport represents a go package to be imported in the generated code
@@ -62,12 +69,18 @@ func (gi goImport) String() string {
 // importMap maps external OpenAPI specifications files/urls to external go packages
 type importMap map[string]goImport
 
-// GoImports returns a slice of go import statements
+// GoImports returns a sorted slice of go import statements, without
+// duplicates, since several specs may map to the same package
 func (im importMap) GoImports() []string {
+	seen := map[string]bool{}
 	goImports := make([]string, 0, len(im))
 	for _, v := range im {
-		goImports = append(goImports, v.String())
+		if statement := v.String(); !seen[statement] {
+			seen[statement] = true
+			goImports = append(goImports, statement)
+		}
 	}
+	sort.Strings(goImports)
 	return goImports
 }
 
@@ -109,11 +122,18 @@ func Generate(swagger *openapi3.Swagger, packageName string, opts Options) (stri
 		pruneUnusedComponents(swagger)
 	}
 
+	// Component types are renamed before anything is generated, so that
+	// references to them resolve to the new names.
+	overrides, err := resolveTypeNameCollisions(swagger, opts)
+	if err != nil {
+		return "", errors.Wrap(err, "error resolving type names")
+	}
+
 	// This creates the golang templates text package
 	t := template.New("oapi-codegen").Funcs(TemplateFunctions)
 	// This parses all of our own template files into the template object
 	// above
-	t, err := templates.Parse(t)
+	t, err = templates.Parse(t)
 	if err != nil {
 		return "", errors.Wrap(err, "error parsing oapi-codegen templates")
 	}
@@ -128,19 +148,50 @@ func Generate(swagger *openapi3.Swagger, packageName string, opts Options) (stri
 		}
 	}
 
-	ops, err := OperationDefinitions(swagger)
+	// In callbacks mode, the API provider is the client, which sends
+	// callbacks to the subscribers implementing the server.
+	var ops []OperationDefinition
+	if opts.GenerateCallbacks {
+		ops, err = callbackOperationDefinitions(swagger, overrides)
+	} else {
+		ops, err = operationDefinitions(swagger, overrides)
+	}
 	if err != nil {
 		return "", errors.Wrap(err, "error creating operation definitions")
 	}
 
 	var typeDefinitions string
 	if opts.GenerateTypes {
-		typeDefinitions, err = GenerateTypeDefinitions(t, swagger, ops, opts.ExcludeSchemas)
+		typeDefinitions, err = generateTypeDefinitions(t, swagger, ops, opts.ExcludeSchemas, overrides)
 		if err != nil {
 			return "", errors.Wrap(err, "error generating type definitions")
 		}
 	}
 
+	// Servers are those of the API, so callbacks don't have any.
+	var servers []ServerDefinition
+	var basePath string
+	if !opts.GenerateCallbacks {
+		servers = DescribeServers(swagger.Servers)
+		basePath = serverBasePath(swagger)
+	}
+
+	if opts.GenerateTypes && len(servers) > 0 {
+		serverURLsOut, err := GenerateServerURLs(t, servers)
+		if err != nil {
+			return "", errors.Wrap(err, "error generating server URLs")
+		}
+		typeDefinitions += serverURLsOut
+	}
+
+	var buildersOut string
+	if opts.GenerateBuilders {
+		buildersOut, err = generateBuilders(t, swagger, ops, opts.ExcludeSchemas, overrides)
+		if err != nil {
+			return "", errors.Wrap(err, "error generating builders")
+		}
+	}
+
 	var echoServerOut string
 	if opts.GenerateEchoServer {
 		echoServerOut, err = GenerateEchoServer(t, ops)
@@ -157,6 +208,41 @@ func Generate(swagger *openapi3.Swagger, packageName string, opts Options) (stri
 		}
 	}
 
+	var stdHTTPServerOut string
+	if opts.GenerateStdHTTPServer {
+		stdHTTPServerOut, err = GenerateStdHTTPServer(t, ops)
+		if err != nil {
+			return "", errors.Wrap(err, "error generating Go handlers for Paths")
+		}
+	}
+
+	// At most one of the servers is generated, and declares the base path.
+	if opts.GenerateEchoServer || opts.GenerateChiServer || opts.GenerateStdHTTPServer {
+		basePathOut, err := GenerateServerBasePath(t, basePath)
+		if err != nil {
+			return "", errors.Wrap(err, "error generating server base path")
+		}
+		echoServerOut += basePathOut
+		chiServerOut += basePathOut
+		stdHTTPServerOut += basePathOut
+
+		badRequestOut, err := GenerateBadRequest(t, opts.GenerateEchoServer, opts.ProblemDetails)
+		if err != nil {
+			return "", err
+		}
+		echoServerOut += badRequestOut
+		chiServerOut += badRequestOut
+		stdHTTPServerOut += badRequestOut
+	}
+
+	var strictServerOut string
+	if opts.GenerateStrictServer {
+		strictServerOut, err = GenerateStrictServer(t, ops, opts)
+		if err != nil {
+			return "", errors.Wrap(err, "error generating strict server")
+		}
+	}
+
 	var clientOut string
 	if opts.GenerateClient {
 		clientOut, err = GenerateClient(t, ops)
@@ -165,6 +251,14 @@ func Generate(swagger *openapi3.Swagger, packageName string, opts Options) (stri
 		}
 	}
 
+	if opts.GenerateClient && opts.GenerateCallbacks {
+		callbackURLsOut, err := GenerateCallbackURLs(t, ops)
+		if err != nil {
+			return "", errors.Wrap(err, "error generating callback URLs")
+		}
+		clientOut += callbackURLsOut
+	}
+
 	var clientWithResponsesOut string
 	if opts.GenerateClient {
 		clientWithResponsesOut, err = GenerateClientWithResponses(t, ops)
@@ -173,12 +267,42 @@ func Generate(swagger *openapi3.Swagger, packageName string, opts Options) (stri
 		}
 	}
 
+	if opts.GenerateClient && opts.GenerateClientErrors {
+		clientErrorsOut, err := GenerateClientErrors(t, ops)
+		if err != nil {
+			return "", errors.Wrap(err, "error generating client errors")
+		}
+		clientWithResponsesOut += clientErrorsOut
+	}
+
+	var mocksOut string
+	if opts.GenerateMocks {
+		mocksOut, err = GenerateMocks(t, DescribeMocks(ops, opts))
+		if err != nil {
+			return "", errors.Wrap(err, "error generating mocks")
+		}
+	}
+
 	var inlinedSpec string
 	if opts.EmbedSpec {
 		inlinedSpec, err = GenerateInlinedSpec(t, swagger)
 		if err != nil {
 			return "", errors.Wrap(err, "error generating Go handlers for Paths")
 		}
+
+		if opts.GenerateEchoServer || opts.GenerateChiServer || opts.GenerateStdHTTPServer {
+			validatorOut, err := GenerateValidator(t, opts.GenerateEchoServer)
+			if err != nil {
+				return "", err
+			}
+			inlinedSpec += validatorOut
+
+			specHandlerOut, err := GenerateSpecHandler(t, opts.GenerateEchoServer)
+			if err != nil {
+				return "", err
+			}
+			inlinedSpec += specHandlerOut
+		}
 	}
 
 	var buf bytes.Buffer
@@ -201,6 +325,13 @@ func Generate(swagger *openapi3.Swagger, packageName string, opts Options) (stri
 
 	}
 
+	if opts.GenerateBuilders {
+		_, err = w.WriteString(buildersOut)
+		if err != nil {
+			return "", errors.Wrap(err, "error writing builders")
+		}
+	}
+
 	if opts.GenerateClient {
 		_, err = w.WriteString(clientOut)
 		if err != nil {
@@ -226,6 +357,27 @@ func Generate(swagger *openapi3.Swagger, packageName string, opts Options) (stri
 		}
 	}
 
+	if opts.GenerateStdHTTPServer {
+		_, err = w.WriteString(stdHTTPServerOut)
+		if err != nil {
+			return "", errors.Wrap(err, "error writing server path handlers")
+		}
+	}
+
+	if opts.GenerateStrictServer {
+		_, err = w.WriteString(strictServerOut)
+		if err != nil {
+			return "", errors.Wrap(err, "error writing strict server")
+		}
+	}
+
+	if opts.GenerateMocks {
+		_, err = w.WriteString(mocksOut)
+		if err != nil {
+			return "", errors.Wrap(err, "error writing mocks")
+		}
+	}
+
 	if opts.EmbedSpec {
 		_, err = w.WriteString(inlinedSpec)
 		if err != nil {
@@ -256,29 +408,44 @@ func Generate(swagger *openapi3.Swagger, packageName string, opts Options) (stri
 }
 
 func GenerateTypeDefinitions(t *template.Template, swagger *openapi3.Swagger, ops []OperationDefinition, excludeSchemas []string) (string, error) {
-	schemaTypes, err := GenerateTypesForSchemas(t, swagger.Components.Schemas, excludeSchemas)
+	return generateTypeDefinitions(t, swagger, ops, excludeSchemas, nil)
+}
+
+func generateTypeDefinitions(t *template.Template, swagger *openapi3.Swagger, ops []OperationDefinition, excludeSchemas []string,
+	overrides typeNameOverrides) (string, error) {
+	schemaTypes, err := generateTypesForSchemas(swagger.Components.Schemas, excludeSchemas, overrides)
 	if err != nil {
 		return "", errors.Wrap(err, "error generating Go types for component schemas")
 	}
 
-	paramTypes, err := GenerateTypesForParameters(t, swagger.Components.Parameters)
+	paramTypes, err := generateTypesForParameters(swagger.Components.Parameters, overrides)
 	if err != nil {
 		return "", errors.Wrap(err, "error generating Go types for component parameters")
 	}
 	allTypes := append(schemaTypes, paramTypes...)
 
-	responseTypes, err := GenerateTypesForResponses(t, swagger.Components.Responses)
+	responseTypes, err := generateTypesForResponses(swagger.Components.Responses, overrides)
 	if err != nil {
 		return "", errors.Wrap(err, "error generating Go types for component responses")
 	}
 	allTypes = append(allTypes, responseTypes...)
 
-	bodyTypes, err := GenerateTypesForRequestBodies(t, swagger.Components.RequestBodies)
+	bodyTypes, err := generateTypesForRequestBodies(swagger.Components.RequestBodies, overrides)
 	if err != nil {
 		return "", errors.Wrap(err, "error generating Go types for component request bodies")
 	}
 	allTypes = append(allTypes, bodyTypes...)
 
+	err = checkTypeNameCollisions(map[string][]TypeDefinition{
+		"schemas":       schemaTypes,
+		"parameters":    paramTypes,
+		"responses":     responseTypes,
+		"requestBodies": bodyTypes,
+	}, ops)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating type definitions")
+	}
+
 	paramTypesOut, err := GenerateTypesForOperations(t, ops)
 	if err != nil {
 		return "", errors.Wrap(err, "error generating Go types for operation parameters")
@@ -301,6 +468,10 @@ func GenerateTypeDefinitions(t *template.Template, swagger *openapi3.Swagger, op
 // Generates type definitions for any custom types defined in the
 // components/schemas section of the Swagger spec.
 func GenerateTypesForSchemas(t *template.Template, schemas map[string]*openapi3.SchemaRef, excludeSchemas []string) ([]TypeDefinition, error) {
+	return generateTypesForSchemas(schemas, excludeSchemas, nil)
+}
+
+func generateTypesForSchemas(schemas map[string]*openapi3.SchemaRef, excludeSchemas []string, overrides typeNameOverrides) ([]TypeDefinition, error) {
 	var excludeSchemasMap = make(map[string]bool)
 	for _, schema := range excludeSchemas {
 		excludeSchemasMap[schema] = true
@@ -313,14 +484,14 @@ func GenerateTypesForSchemas(t *template.Template, schemas map[string]*openapi3.
 		}
 		schemaRef := schemas[schemaName]
 
-		goSchema, err := GenerateGoSchema(schemaRef, []string{schemaName})
+		goSchema, err := generateGoSchema(schemaRef, overrides.componentTypePath("schemas", schemaName), overrides)
 		if err != nil {
 			return nil, errors.Wrap(err, fmt.Sprintf("error converting Schema %s to Go type", schemaName))
 		}
 
 		types = append(types, TypeDefinition{
 			JsonName: schemaName,
-			TypeName: SchemaNameToTypeName(schemaName),
+			TypeName: overrides.componentTypeName("schemas", schemaName),
 			Schema:   goSchema,
 		})
 
@@ -332,11 +503,15 @@ func GenerateTypesForSchemas(t *template.Template, schemas map[string]*openapi3.
 // Generates type definitions for any custom types defined in the
 // components/parameters section of the Swagger spec.
 func GenerateTypesForParameters(t *template.Template, params map[string]*openapi3.ParameterRef) ([]TypeDefinition, error) {
+	return generateTypesForParameters(params, nil)
+}
+
+func generateTypesForParameters(params map[string]*openapi3.ParameterRef, overrides typeNameOverrides) ([]TypeDefinition, error) {
 	var types []TypeDefinition
 	for _, paramName := range SortedParameterKeys(params) {
 		paramOrRef := params[paramName]
 
-		goType, err := paramToGoType(paramOrRef.Value, nil)
+		goType, err := paramToGoType(paramOrRef.Value, nil, overrides)
 		if err != nil {
 			return nil, errors.Wrap(err, fmt.Sprintf("error generating Go type for schema in parameter %s", paramName))
 		}
@@ -344,12 +519,12 @@ func GenerateTypesForParameters(t *template.Template, params map[string]*openapi
 		typeDef := TypeDefinition{
 			JsonName: paramName,
 			Schema:   goType,
-			TypeName: SchemaNameToTypeName(paramName),
+			TypeName: overrides.componentTypeName("parameters", paramName),
 		}
 
 		if paramOrRef.Ref != "" {
 			// Generate a reference type for referenced parameters
-			refType, err := RefPathToGoType(paramOrRef.Ref)
+			refType, err := overrides.refPathToGoType(paramOrRef.Ref)
 			if err != nil {
 				return nil, errors.Wrap(err, fmt.Sprintf("error generating Go type for (%s) in parameter %s", paramOrRef.Ref, paramName))
 			}
@@ -364,6 +539,10 @@ func GenerateTypesForParameters(t *template.Template, params map[string]*openapi
 // Generates type definitions for any custom types defined in the
 // components/responses section of the Swagger spec.
 func GenerateTypesForResponses(t *template.Template, responses openapi3.Responses) ([]TypeDefinition, error) {
+	return generateTypesForResponses(responses, nil)
+}
+
+func generateTypesForResponses(responses openapi3.Responses, overrides typeNameOverrides) ([]TypeDefinition, error) {
 	var types []TypeDefinition
 
 	for _, responseName := range SortedResponsesKeys(responses) {
@@ -375,7 +554,7 @@ func GenerateTypesForResponses(t *template.Template, responses openapi3.Response
 		response := responseOrRef.Value
 		jsonResponse, found := response.Content["application/json"]
 		if found {
-			goType, err := GenerateGoSchema(jsonResponse.Schema, []string{responseName})
+			goType, err := generateGoSchema(jsonResponse.Schema, overrides.componentTypePath("responses", responseName), overrides)
 			if err != nil {
 				return nil, errors.Wrap(err, fmt.Sprintf("error generating Go type for schema in response %s", responseName))
 			}
@@ -383,12 +562,12 @@ func GenerateTypesForResponses(t *template.Template, responses openapi3.Response
 			typeDef := TypeDefinition{
 				JsonName: responseName,
 				Schema:   goType,
-				TypeName: SchemaNameToTypeName(responseName),
+				TypeName: overrides.componentTypeName("responses", responseName),
 			}
 
 			if responseOrRef.Ref != "" {
 				// Generate a reference type for referenced parameters
-				refType, err := RefPathToGoType(responseOrRef.Ref)
+				refType, err := overrides.refPathToGoType(responseOrRef.Ref)
 				if err != nil {
 					return nil, errors.Wrap(err, fmt.Sprintf("error generating Go type for (%s) in parameter %s", responseOrRef.Ref, responseName))
 				}
@@ -403,6 +582,10 @@ func GenerateTypesForResponses(t *template.Template, responses openapi3.Response
 // Generates type definitions for any custom types defined in the
 // components/requestBodies section of the Swagger spec.
 func GenerateTypesForRequestBodies(t *template.Template, bodies map[string]*openapi3.RequestBodyRef) ([]TypeDefinition, error) {
+	return generateTypesForRequestBodies(bodies, nil)
+}
+
+func generateTypesForRequestBodies(bodies map[string]*openapi3.RequestBodyRef, overrides typeNameOverrides) ([]TypeDefinition, error) {
 	var types []TypeDefinition
 
 	for _, bodyName := range SortedRequestBodyKeys(bodies) {
@@ -413,7 +596,7 @@ func GenerateTypesForRequestBodies(t *template.Template, bodies map[string]*open
 		response := bodyOrRef.Value
 		jsonBody, found := response.Content["application/json"]
 		if found {
-			goType, err := GenerateGoSchema(jsonBody.Schema, []string{bodyName})
+			goType, err := generateGoSchema(jsonBody.Schema, overrides.componentTypePath("requestBodies", bodyName), overrides)
 			if err != nil {
 				return nil, errors.Wrap(err, fmt.Sprintf("error generating Go type for schema in body %s", bodyName))
 			}
@@ -421,12 +604,12 @@ func GenerateTypesForRequestBodies(t *template.Template, bodies map[string]*open
 			typeDef := TypeDefinition{
 				JsonName: bodyName,
 				Schema:   goType,
-				TypeName: SchemaNameToTypeName(bodyName),
+				TypeName: overrides.componentTypeName("requestBodies", bodyName),
 			}
 
 			if bodyOrRef.Ref != "" {
 				// Generate a reference type for referenced bodies
-				refType, err := RefPathToGoType(bodyOrRef.Ref)
+				refType, err := overrides.refPathToGoType(bodyOrRef.Ref)
 				if err != nil {
 					return nil, errors.Wrap(err, fmt.Sprintf("error generating Go type for (%s) in body %s", bodyOrRef.Ref, bodyName))
 				}
@@ -513,6 +696,74 @@ func GenerateAdditionalPropertyBoilerplate(t *template.Template, typeDefs []Type
 	return buf.String(), nil
 }
 
+// Generates fluent setters for the optional fields of all the struct types we
+// define, and functional-option constructors for operation parameter objects.
+func GenerateBuilders(t *template.Template, swagger *openapi3.Swagger, ops []OperationDefinition, excludeSchemas []string) (string, error) {
+	return generateBuilders(t, swagger, ops, excludeSchemas, nil)
+}
+
+func generateBuilders(t *template.Template, swagger *openapi3.Swagger, ops []OperationDefinition, excludeSchemas []string,
+	overrides typeNameOverrides) (string, error) {
+	schemaTypes, err := generateTypesForSchemas(swagger.Components.Schemas, excludeSchemas, overrides)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating Go types for component schemas")
+	}
+	paramTypes, err := generateTypesForParameters(swagger.Components.Parameters, overrides)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating Go types for component parameters")
+	}
+	responseTypes, err := generateTypesForResponses(swagger.Components.Responses, overrides)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating Go types for component responses")
+	}
+	bodyTypes, err := generateTypesForRequestBodies(swagger.Components.RequestBodies, overrides)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating Go types for component request bodies")
+	}
+	allTypes := append(schemaTypes, paramTypes...)
+	allTypes = append(allTypes, responseTypes...)
+	allTypes = append(allTypes, bodyTypes...)
+
+	var paramsTypes []TypeDefinition
+	for _, op := range ops {
+		for _, td := range op.TypeDefinitions {
+			allTypes = append(allTypes, td)
+			if td.TypeName == op.OperationId+"Params" {
+				paramsTypes = append(paramsTypes, td)
+			}
+		}
+	}
+
+	// Only types declared as structs of their own have fields to set.
+	var structTypes []TypeDefinition
+	for _, td := range allTypes {
+		if !td.Schema.IsRef() && strings.HasPrefix(td.Schema.GoType, "struct") {
+			structTypes = append(structTypes, td)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	context := struct {
+		Types  []TypeDefinition
+		Params []TypeDefinition
+	}{
+		Types:  structTypes,
+		Params: paramsTypes,
+	}
+
+	err = t.ExecuteTemplate(w, "builders.tmpl", context)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating builders")
+	}
+	err = w.Flush()
+	if err != nil {
+		return "", errors.Wrap(err, "error flushing output buffer for builders")
+	}
+	return buf.String(), nil
+}
+
 // SanitizeCode runs sanitizers across the generated Go code to ensure the
 // generated code will be able to compile.
 func SanitizeCode(goCode string) string {
@@ -7,11 +7,12 @@ import (
 	"net/http"
 	"testing"
 
-	examplePetstoreClient "github.com/deepmap/oapi-codegen/examples/petstore-expanded"
-	examplePetstore "github.com/deepmap/oapi-codegen/examples/petstore-expanded/echo/api"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/golangci/lint-1"
+	examplePetstoreClient "github.com/leslie-wang/oapi-codegen/examples/petstore-expanded"
+	examplePetstore "github.com/leslie-wang/oapi-codegen/examples/petstore-expanded/echo/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExamplePetStoreCodeGeneration(t *testing.T) {
@@ -90,6 +91,149 @@ func TestExamplePetStoreCodeGenerationWithUserTemplates(t *testing.T) {
 	assert.Contains(t, code, "//blah")
 }
 
+func TestGenerateResponsesWithoutContent(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: Responses without content
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    delete:
+      operationId: deletePet
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      responses:
+        204:
+          description: Deleted
+        default:
+          description: Error
+          content:
+            application/json:
+              schema:
+                type: string
+`))
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true})
+	assert.NoError(t, err)
+
+	// A 204 has nothing to unmarshal, so it has no field in the response.
+	assert.NotContains(t, code, "NO204")
+	assert.Contains(t, code, "JSONDefault  *string")
+	assert.Contains(t, code, "break // No content-type")
+}
+
+func TestExamplePetStoreStdHTTPServerGeneration(t *testing.T) {
+	swagger, err := examplePetstore.GetSwagger()
+	assert.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateStdHTTPServer: true})
+	assert.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	// The net/http server shares the interface and the wrappers of the chi
+	// server, but routes the requests itself.
+	assert.Contains(t, code, "FindPetById(w http.ResponseWriter, r *http.Request, id int64)")
+	assert.Contains(t, code, `runtime.BindStyledParameter("simple", false, "id", pathParam(r, "id"), &id)`)
+	assert.Contains(t, code, "func HandlerWithBaseURL(si ServerInterface, baseURL string) http.Handler {")
+	assert.Contains(t, code, `{Method: "GET", Path: "/pets/{id}", Handler: wrapper.FindPetById},`)
+	assert.Contains(t, code, "return runtime.PathParam(r, name)")
+	assert.NotContains(t, code, "chi.")
+}
+
+func TestParametersStdHTTPServerCompiles(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromFile("../../internal/test/parameters/parameters.yaml")
+	require.NoError(t, err)
+
+	// The wrappers of operations with only cookie parameters don't declare
+	// an err they don't use.
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateStdHTTPServer: true})
+	require.NoError(t, err)
+	requireCompiles(t, code)
+}
+
+func TestExamplePetStoreValidatorGeneration(t *testing.T) {
+	swagger, err := examplePetstore.GetSwagger()
+	assert.NoError(t, err)
+
+	for _, opts := range []Options{
+		{GenerateTypes: true, GenerateEchoServer: true, EmbedSpec: true},
+		{GenerateTypes: true, GenerateChiServer: true, EmbedSpec: true},
+	} {
+		code, err := Generate(swagger, "api", opts)
+		assert.NoError(t, err)
+
+		_, err = format.Source([]byte(code))
+		assert.NoError(t, err)
+
+		// The validators are bound to the embedded spec, and match the paths
+		// under the base path of the handlers.
+		assert.Contains(t, code, "func ValidateRequest(r *http.Request) error {")
+		assert.Contains(t, code, "swagger.Servers = openapi3.Servers{{URL: ServerBasePath}}")
+		assert.Contains(t, code, "specRouter, specRouterErr = middleware.NewRouter(swagger)")
+		if opts.GenerateEchoServer {
+			assert.Contains(t, code, "func Validator() (echo.MiddlewareFunc, error) {")
+			assert.Contains(t, code, "return middleware.OapiRequestValidatorWithRouter(router, options), nil")
+		} else {
+			assert.Contains(t, code, "func Validator() (func(http.Handler) http.Handler, error) {")
+			assert.Contains(t, code, "return middleware.OapiRequestValidatorHTTPWithRouter(router, options), nil")
+		}
+	}
+
+	// Without a server, or the spec, there's nothing to validate.
+	for _, opts := range []Options{
+		{GenerateTypes: true, EmbedSpec: true},
+		{GenerateTypes: true, GenerateEchoServer: true},
+	} {
+		code, err := Generate(swagger, "api", opts)
+		assert.NoError(t, err)
+		assert.NotContains(t, code, "func ValidateRequest(")
+	}
+}
+
+func TestExamplePetStoreSpecHandlerGeneration(t *testing.T) {
+	swagger, err := examplePetstore.GetSwagger()
+	assert.NoError(t, err)
+
+	for _, opts := range []Options{
+		{GenerateTypes: true, GenerateEchoServer: true, EmbedSpec: true},
+		{GenerateTypes: true, GenerateChiServer: true, EmbedSpec: true},
+		{GenerateTypes: true, GenerateStdHTTPServer: true, EmbedSpec: true},
+	} {
+		code, err := Generate(swagger, "api", opts)
+		assert.NoError(t, err)
+
+		_, err = format.Source([]byte(code))
+		assert.NoError(t, err)
+
+		// The spec is decoded once, and served by a handler built once.
+		assert.Contains(t, code, "func GetCachedSwagger() (*openapi3.Swagger, error) {")
+		assert.Contains(t, code, "rawSpecOnce.Do(func() {")
+		assert.Contains(t, code, "func SpecHandler() (http.Handler, error) {")
+		assert.Contains(t, code, "specHandler, specHandlerErr = middleware.NewSpecHandler(data)")
+		if opts.GenerateEchoServer {
+			assert.Contains(t, code, "func RegisterSpecHandlers(router EchoRouter, prefix string) error {")
+			assert.Contains(t, code, "router.GET(prefix+path, handler)")
+		} else {
+			assert.Contains(t, code, "func RegisterSpecHandlers(mux interface{ Handle(string, http.Handler) }, prefix string) error {")
+		}
+		requireCompiles(t, code)
+	}
+
+	// Clients only get the cached accessor.
+	code, err := Generate(swagger, "api", Options{GenerateClient: true, EmbedSpec: true})
+	assert.NoError(t, err)
+	assert.Contains(t, code, "func GetCachedSwagger() (*openapi3.Swagger, error) {")
+	assert.NotContains(t, code, "func SpecHandler()")
+}
+
 func TestExamplePetStoreParseFunction(t *testing.T) {
 
 	bodyBytes := []byte(`{"id": 5, "name": "testpet", "tag": "cat"}`)
@@ -308,3 +452,15 @@ components:
           type: string
           enum: [car, dog, oldage]
 `
+
+func TestImportMapGoImports(t *testing.T) {
+	im := importMap{
+		"b.yaml": {Name: "externalRef1", Path: "github.com/example/b"},
+		"a.yaml": {Name: "externalRef0", Path: "github.com/example/a"},
+		"c.yaml": {Name: "externalRef0", Path: "github.com/example/a"},
+	}
+	assert.Equal(t, []string{
+		`externalRef0 "github.com/example/a"`,
+		`externalRef1 "github.com/example/b"`,
+	}, im.GoImports())
+}
@@ -0,0 +1,385 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Go type names are derived independently for every part of the spec, so two
+// unrelated constructs can end up with the same name, eg, a schema named
+// FindPetsParams and the parameters object of operation findPets, or a
+// response and a schema with the same name. We resolve these before
+// generating anything, using the following scheme:
+//
+// 1. Names of types the generated code declares for operations (XParams,
+//    XJSONBody, XJSONRequestBody and their counterparts for other body
+//    types, XResponse, X200ResponseHeaders, XRequestObject,
+//    XResponseObject) and fixed identifiers such as Client or
+//    ServerInterface are never renamed, since the templates and callers
+//    depend on them.
+// 2. Component types claim their names in the order schemas, parameters,
+//    responses, requestBodies. A component whose name is already taken gets
+//    a suffix for its kind: Schema, Parameter, Response or RequestBody. If
+//    that name is taken too, a number is appended, starting at 2.
+//
+// Collisions which can't be resolved this way, such as two operations
+// producing the same Params type, are reported as errors naming both
+// locations in the spec.
+
+// typeNameOverrides maps local reference paths, such as
+// #/components/responses/Foo, to the disambiguated Go type name for that
+// component. Generate resolves them for the spec before generating anything,
+// and passes them along to everything which names types. A nil map renames
+// nothing.
+type typeNameOverrides map[string]string
+
+// The suffixes used to disambiguate colliding component types.
+var componentTypeSuffixes = map[string]string{
+	"schemas":       "Schema",
+	"parameters":    "Parameter",
+	"responses":     "Response",
+	"requestBodies": "RequestBody",
+}
+
+// typeNameRegistry keeps track of which spec location claimed each Go type
+// name.
+type typeNameRegistry map[string]string
+
+// claim registers name for location, returning an error which names both
+// locations when the name is already in use.
+func (r typeNameRegistry) claim(name, location string) error {
+	if existing, found := r[name]; found {
+		return fmt.Errorf("type name '%s' is generated for both %s and %s", name, existing, location)
+	}
+	r[name] = location
+	return nil
+}
+
+// claimWithSuffix registers the first free name out of name, name+suffix,
+// name+suffix+"2", and so on, and returns it.
+func (r typeNameRegistry) claimWithSuffix(name, suffix, location string) string {
+	candidate := name
+	for i := 1; ; i++ {
+		if _, found := r[candidate]; !found {
+			r[candidate] = location
+			return candidate
+		}
+		candidate = name + suffix
+		if i > 1 {
+			candidate += fmt.Sprint(i)
+		}
+	}
+}
+
+// componentRef returns the local reference path for a component, with its
+// name escaped as a JSON pointer token, as it is in $ref values.
+func componentRef(kind, name string) string {
+	return fmt.Sprintf("#/components/%s/%s", kind, escapeJSONPointer(name))
+}
+
+// operationRef returns a JSON pointer to an operation in the spec.
+func operationRef(path, method string) string {
+	return fmt.Sprintf("#/paths/%s/%s", escapeJSONPointer(path), strings.ToLower(method))
+}
+
+// escapeJSONPointer escapes a JSON pointer reference token, as described in
+// RFC 6901.
+func escapeJSONPointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// componentTypeName returns the Go type name for the component of the given
+// kind (schemas, parameters, responses, requestBodies) and name, taking any
+// disambiguation into account.
+func (o typeNameOverrides) componentTypeName(kind, name string) string {
+	if override, found := o[componentRef(kind, name)]; found {
+		return override
+	}
+	return SchemaNameToTypeName(name)
+}
+
+// componentTypePath returns the root of the path used to name types nested
+// in a component. This is the component name, unless the component type has
+// been renamed, in which case nested types follow the new name.
+func (o typeNameOverrides) componentTypePath(kind, name string) []string {
+	if override, found := o[componentRef(kind, name)]; found {
+		return []string{override}
+	}
+	return []string{name}
+}
+
+// operationIDFor returns the operation ID which OperationDefinitions will use
+// for the given operation.
+func operationIDFor(method, path string, op *openapi3.Operation) (string, error) {
+	if op.OperationID == "" {
+		return generateDefaultOperationID(method, path)
+	}
+	return ToCamelCase(op.OperationID), nil
+}
+
+// reservedTypeNames returns the identifiers which the generated code declares
+// for the given options, independently of the spec.
+func reservedTypeNames(opts Options) []string {
+	var names []string
+	if opts.GenerateClient {
+		names = append(names, "RequestEditorFn", "DeprecatedCallFn", "HttpRequestDoer", "Client", "ClientOption",
+			"ClientInterface", "ClientWithResponses", "ClientWithResponsesInterface")
+	}
+	if opts.GenerateEchoServer {
+		names = append(names, "ServerInterface", "ServerInterfaceWrapper", "EchoRouter")
+	}
+	if opts.GenerateChiServer || opts.GenerateStdHTTPServer {
+		names = append(names, "ServerInterface", "ServerInterfaceWrapper")
+	}
+	if opts.GenerateEchoServer || opts.GenerateChiServer || opts.GenerateStdHTTPServer {
+		names = append(names, "ServerBasePath")
+	}
+	if opts.EmbedSpec && (opts.GenerateEchoServer || opts.GenerateChiServer || opts.GenerateStdHTTPServer) {
+		names = append(names, "ValidateRequest", "ValidateRequestWithOptions", "Validator", "ValidatorWithOptions",
+			"SpecHandler", "RegisterSpecHandlers")
+	}
+	if opts.EmbedSpec {
+		names = append(names, "GetSwagger", "GetCachedSwagger")
+	}
+	if opts.GenerateStrictServer {
+		names = append(names, "StrictServerInterface", "strictHandler")
+		if !opts.GenerateEchoServer {
+			names = append(names, "StrictHTTPServerOptions")
+		}
+	}
+	if opts.GenerateMocks {
+		for _, mock := range DescribeMocks(nil, opts) {
+			names = append(names, mock.TypeName())
+		}
+	}
+	return names
+}
+
+// resolveTypeNameCollisions claims the names of all the types generated for
+// the given spec, and returns the overrides needed to give colliding
+// component types unique names. It returns an error for collisions which
+// can't be resolved by renaming a component.
+func resolveTypeNameCollisions(swagger *openapi3.Swagger, opts Options) (typeNameOverrides, error) {
+	registry := typeNameRegistry{}
+	for _, name := range reservedTypeNames(opts) {
+		registry[name] = "generated code"
+	}
+
+	// So do the identifiers generated for the servers of the spec.
+	if opts.GenerateTypes && !opts.GenerateCallbacks {
+		for i, server := range DescribeServers(swagger.Servers) {
+			location := fmt.Sprintf("#/servers/%d", i)
+			names := []string{"ServerURL" + server.Name}
+			if len(server.Variables) > 0 {
+				names = append(names, "NewServerURL"+server.Name)
+			}
+			for _, v := range server.Variables {
+				if v.IsEnum() {
+					names = append(names, v.TypeName)
+				}
+			}
+			for _, name := range names {
+				if err := registry.claim(name, location); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// Operation types have fixed names, so they go first. In callbacks mode,
+	// the operations are those of the callbacks.
+	type namedOperation struct {
+		id       string
+		location string
+		pathItem *openapi3.PathItem
+		op       *openapi3.Operation
+	}
+	var namedOps []namedOperation
+	if opts.GenerateCallbacks {
+		cbOps, err := callbackOperations(swagger)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cbOps {
+			opID, err := callbackOperationIDFor(c)
+			if err != nil {
+				return nil, err
+			}
+			namedOps = append(namedOps, namedOperation{opID, c.Location, c.PathItem, c.Op})
+		}
+	} else {
+		for _, requestPath := range SortedPathsKeys(swagger.Paths) {
+			pathItem := swagger.Paths[requestPath]
+			pathOps := pathItem.Operations()
+			for _, method := range SortedOperationsKeys(pathOps) {
+				op := pathOps[method]
+				opID, err := operationIDFor(method, requestPath, op)
+				if err != nil {
+					return nil, err
+				}
+				namedOps = append(namedOps, namedOperation{opID, operationRef(requestPath, method), pathItem, op})
+			}
+		}
+	}
+
+	for _, namedOp := range namedOps {
+		opID, location, pathItem, op := namedOp.id, namedOp.location, namedOp.pathItem, namedOp.op
+
+		hasParamObject := false
+		for _, params := range []openapi3.Parameters{pathItem.Parameters, op.Parameters} {
+			for _, param := range params {
+				if param.Value != nil && param.Value.In != openapi3.ParameterInPath {
+					hasParamObject = true
+				}
+			}
+		}
+		if hasParamObject {
+			if err := registry.claim(opID+"Params", location+"/parameters"); err != nil {
+				return nil, err
+			}
+		}
+
+		if op.RequestBody != nil && op.RequestBody.Value != nil {
+			for _, contentType := range SortedContentKeys(op.RequestBody.Value.Content) {
+				tag, found := requestBodyNameTags[contentType]
+				if !found {
+					continue
+				}
+				// Only JSON bodies re-use the type of a referenced component
+				if op.RequestBody.Ref == "" || contentType != "application/json" {
+					if err := registry.claim(opID+tag+"Body", location+"/requestBody"); err != nil {
+						return nil, err
+					}
+				}
+				if err := registry.claim(opID+tag+"RequestBody", location+"/requestBody"); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if opts.GenerateClient {
+			if err := registry.claim(genResponseTypeName(opID), location+"/responses"); err != nil {
+				return nil, err
+			}
+		}
+		if opts.GenerateClient && opts.GenerateClientErrors {
+			for _, responseName := range SortedResponsesKeys(op.Responses) {
+				if isSuccessResponseName(responseName) || op.Responses[responseName].Value == nil {
+					continue
+				}
+				typeName := opID + ToCamelCase(responseName) + "Error"
+				if err := registry.claim(typeName, location+"/responses/"+responseName); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if opts.GenerateClient && opts.GenerateCallbacks {
+			if err := registry.claim(opID+"CallbackURL", location); err != nil {
+				return nil, err
+			}
+		}
+		if opts.GenerateStrictServer {
+			if err := registry.claim(opID+"RequestObject", location); err != nil {
+				return nil, err
+			}
+			if err := registry.claim(opID+"ResponseObject", location+"/responses"); err != nil {
+				return nil, err
+			}
+		}
+		if opts.GenerateTypes {
+			for _, responseName := range SortedResponsesKeys(op.Responses) {
+				response := op.Responses[responseName].Value
+				if response == nil || len(response.Headers) == 0 {
+					continue
+				}
+				typeName := opID + ToCamelCase(responseName) + "ResponseHeaders"
+				if err := registry.claim(typeName, location+"/responses/"+responseName+"/headers"); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	overrides := typeNameOverrides{}
+	claimComponent := func(kind, name string) {
+		ref := componentRef(kind, name)
+		typeName := SchemaNameToTypeName(name)
+		claimed := registry.claimWithSuffix(typeName, componentTypeSuffixes[kind], ref)
+		if claimed != typeName {
+			overrides[ref] = claimed
+		}
+	}
+
+	excluded := make(map[string]bool)
+	for _, name := range opts.ExcludeSchemas {
+		excluded[name] = true
+	}
+	for _, name := range SortedSchemaKeys(swagger.Components.Schemas) {
+		if !excluded[name] {
+			claimComponent("schemas", name)
+		}
+	}
+	for _, name := range SortedParameterKeys(swagger.Components.Parameters) {
+		// Referenced components re-use the type of their target
+		if swagger.Components.Parameters[name].Ref == "" {
+			claimComponent("parameters", name)
+		}
+	}
+	for _, name := range SortedResponsesKeys(swagger.Components.Responses) {
+		response := swagger.Components.Responses[name]
+		if response.Ref != "" || response.Value == nil {
+			continue
+		}
+		// Only JSON responses get types, see GenerateTypesForResponses
+		if _, found := response.Value.Content["application/json"]; found {
+			claimComponent("responses", name)
+		}
+	}
+	for _, name := range SortedRequestBodyKeys(swagger.Components.RequestBodies) {
+		body := swagger.Components.RequestBodies[name]
+		if body.Ref != "" || body.Value == nil {
+			continue
+		}
+		if _, found := body.Value.Content["application/json"]; found {
+			claimComponent("requestBodies", name)
+		}
+	}
+	return overrides, nil
+}
+
+// checkTypeNameCollisions makes sure that the type definitions about to be
+// generated have unique names. This catches collisions between types which
+// are nested in schemas and operations, which resolveTypeNameCollisions
+// can't know about.
+func checkTypeNameCollisions(components map[string][]TypeDefinition, ops []OperationDefinition) error {
+	registry := typeNameRegistry{}
+	for _, kind := range []string{"schemas", "parameters", "responses", "requestBodies"} {
+		for _, td := range components[kind] {
+			if err := registry.claim(td.TypeName, typeDefinitionLocation(kind, td)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, op := range ops {
+		location := operationRef(op.Path, op.Method)
+		for _, td := range op.TypeDefinitions {
+			if err := registry.claim(td.TypeName, location); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// typeDefinitionLocation converts the JSON name of a component type, which is
+// the dotted path to nested types, into a reference to the spec.
+func typeDefinitionLocation(kind string, td TypeDefinition) string {
+	parts := strings.Split(td.JsonName, ".")
+	location := componentRef(kind, escapeJSONPointer(parts[0]))
+	for _, p := range parts[1:] {
+		location += "/properties/" + escapeJSONPointer(p)
+	}
+	return location
+}
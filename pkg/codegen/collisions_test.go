@@ -0,0 +1,209 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const collidingOpenAPIDefinition = `
+openapi: 3.0.1
+info:
+  title: Colliding type names
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: findPets
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+      responses:
+        200:
+          $ref: '#/components/responses/Pet'
+    post:
+      operationId: addPet
+      requestBody:
+        $ref: '#/components/requestBodies/Pet'
+      responses:
+        200:
+          description: Success
+components:
+  schemas:
+    Pet:
+      properties:
+        name:
+          type: string
+    FindPetsParams:
+      properties:
+        filter:
+          type: string
+  responses:
+    Pet:
+      description: A pet
+      content:
+        application/json:
+          schema:
+            properties:
+              pet:
+                $ref: '#/components/schemas/Pet'
+  requestBodies:
+    Pet:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'
+`
+
+func TestResolveTypeNameCollisions(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(collidingOpenAPIDefinition))
+	require.NoError(t, err)
+
+	overrides, err := resolveTypeNameCollisions(swagger, Options{GenerateTypes: true})
+	require.NoError(t, err)
+	assert.Equal(t, typeNameOverrides{
+		"#/components/schemas/FindPetsParams": "FindPetsParamsSchema",
+		"#/components/responses/Pet":          "PetResponse",
+		"#/components/requestBodies/Pet":      "PetRequestBody",
+	}, overrides)
+
+	// When the suffixed name is taken as well, a number is appended.
+	swagger.Components.Schemas["PetResponse"] = &openapi3.SchemaRef{Value: openapi3.NewStringSchema()}
+	overrides, err = resolveTypeNameCollisions(swagger, Options{GenerateTypes: true, GenerateClient: true})
+	require.NoError(t, err)
+	assert.Equal(t, "PetResponse2", overrides["#/components/responses/Pet"])
+}
+
+func TestResolveTypeNameCollisionsFailure(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(collidingOpenAPIDefinition))
+	require.NoError(t, err)
+
+	// Operation types can't be renamed, so two operations whose IDs map to
+	// the same Go name are an error.
+	swagger.Paths["/other"] = &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			OperationID: "find_pets",
+			Parameters: openapi3.Parameters{
+				{Value: openapi3.NewQueryParameter("q").WithSchema(openapi3.NewStringSchema())},
+			},
+		},
+	}
+
+	_, err = resolveTypeNameCollisions(swagger, Options{GenerateTypes: true})
+	require.Error(t, err)
+	assert.Equal(t, "type name 'FindPetsParams' is generated for both #/paths/~1other/get/parameters and #/paths/~1pets/get/parameters", err.Error())
+}
+
+func TestCheckTypeNameCollisions(t *testing.T) {
+	components := map[string][]TypeDefinition{
+		"schemas": {
+			{TypeName: "Pet_Owner", JsonName: "Pet.owner"},
+		},
+		"responses": {
+			{TypeName: "Pet_Owner", JsonName: "pet.owner"},
+		},
+	}
+	err := checkTypeNameCollisions(components, nil)
+	require.Error(t, err)
+	assert.Equal(t, "type name 'Pet_Owner' is generated for both #/components/schemas/Pet/properties/owner and #/components/responses/pet/properties/owner", err.Error())
+}
+
+func TestGenerateWithCollidingTypeNames(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(collidingOpenAPIDefinition))
+	require.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	require.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "type Pet struct {")
+	assert.Contains(t, code, "type PetResponse struct {")
+	assert.Contains(t, code, "type PetRequestBody Pet")
+	assert.Contains(t, code, "type FindPetsParamsSchema struct {")
+	assert.Contains(t, code, "type FindPetsParams struct {")
+	assert.Contains(t, code, "type AddPetJSONRequestBody PetRequestBody")
+}
+
+func TestGenerateDoesNotLeakTypeNameOverrides(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(collidingOpenAPIDefinition))
+	require.NoError(t, err)
+	_, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	require.NoError(t, err)
+
+	// The renames of one generation don't apply to the next, nor to the
+	// exported functions, which don't rename anything.
+	goType, err := RefPathToGoType("#/components/responses/Pet")
+	require.NoError(t, err)
+	assert.Equal(t, "Pet", goType)
+
+	swagger, err = openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(collidingOpenAPIDefinition))
+	require.NoError(t, err)
+	delete(swagger.Components.Schemas, "Pet")
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	require.NoError(t, err)
+	assert.Contains(t, code, "type Pet struct {")
+	assert.NotContains(t, code, "PetResponse")
+	assert.Contains(t, code, "type AddPetJSONRequestBody PetRequestBody")
+}
+
+func TestGenerateWithReferencesToRenamedTypes(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: References to renamed types
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: findPets
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+      responses:
+        200:
+          $ref: '#/components/responses/Filters'
+components:
+  schemas:
+    FindPetsParams:
+      properties:
+        filter:
+          type: string
+    SavedSearch:
+      properties:
+        params:
+          $ref: '#/components/schemas/FindPetsParams'
+  responses:
+    Filters:
+      description: The filters
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/FindPetsParams'
+`))
+	require.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	require.NoError(t, err)
+	requireCompiles(t, code)
+
+	// Components referring to the renamed schema use its new name, rather
+	// than the name of the params of findPets.
+	assert.Contains(t, code, "Params *FindPetsParamsSchema `json:\"params,omitempty\"`")
+	assert.Contains(t, code, "type Filters FindPetsParamsSchema")
+	assert.Contains(t, code, "type FindPetsParams struct {")
+}
+
+func TestComponentRef(t *testing.T) {
+	// Names are escaped as they are in $ref values.
+	assert.Equal(t, "#/components/schemas/Pet", componentRef("schemas", "Pet"))
+	assert.Equal(t, "#/components/schemas/a~1b~0c", componentRef("schemas", "a/b~c"))
+}
@@ -0,0 +1,30 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The tests type checking generated code share an importer, so that the
+// packages it imports are only loaded once.
+var (
+	compileFileSet  = token.NewFileSet()
+	compileImporter = importer.ForCompiler(compileFileSet, "source", nil)
+)
+
+// requireCompiles type checks generated code, which catches what gofmt
+// doesn't, such as undeclared or unused identifiers.
+func requireCompiles(t *testing.T, code string) {
+	f, err := parser.ParseFile(compileFileSet, "generated.go", code, 0)
+	require.NoError(t, err)
+
+	conf := types.Config{Importer: compileImporter}
+	_, err = conf.Check("generated", compileFileSet, []*ast.File{f}, nil)
+	require.NoError(t, err, code)
+}
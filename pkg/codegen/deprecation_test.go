@@ -0,0 +1,110 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deprecatedOpenAPIDefinition = `
+openapi: 3.0.1
+info:
+  title: Deprecated
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: findPets
+      deprecated: true
+      x-sunset: "2030-06-30"
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+      responses:
+        200:
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+    post:
+      operationId: addPet
+      deprecated: true
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        204:
+          description: Added
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      responses:
+        204:
+          description: Found
+`
+
+func TestDeprecatedOperations(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(deprecatedOpenAPIDefinition))
+	require.NoError(t, err)
+
+	ops, err := OperationDefinitions(swagger)
+	require.NoError(t, err)
+	require.Len(t, ops, 3)
+	assert.True(t, ops[0].Deprecated)
+	assert.Equal(t, "Sun, 30 Jun 2030 00:00:00 GMT", ops[0].Sunset)
+	assert.Equal(t, "Deprecated: FindPets is deprecated, and may stop working after Sun, 30 Jun 2030 00:00:00 GMT.",
+		ops[0].DeprecationNotice())
+	assert.True(t, ops[1].Deprecated)
+	assert.Equal(t, "", ops[1].Sunset)
+	assert.Equal(t, "Deprecated: AddPet is deprecated.", ops[1].DeprecationNotice())
+	assert.False(t, ops[2].Deprecated)
+
+	for _, opts := range []Options{
+		{GenerateTypes: true, GenerateClient: true, GenerateEchoServer: true},
+		{GenerateTypes: true, GenerateClient: true, GenerateChiServer: true},
+	} {
+		code, err := Generate(swagger, "api", opts)
+		require.NoError(t, err)
+
+		_, err = format.Source([]byte(code))
+		assert.NoError(t, err)
+
+		// Client functions of deprecated operations are marked as such.
+		assert.Contains(t, code, "// Deprecated: FindPets is deprecated, and may stop working after Sun, 30 Jun 2030 00:00:00 GMT.\nfunc (c *Client) FindPets(")
+		assert.Contains(t, code, "// Deprecated: AddPet is deprecated.\nfunc (c *Client) AddPet(")
+		assert.Contains(t, code, "//\n// Deprecated: AddPet is deprecated.\nfunc NewAddPetRequestWithBody(")
+		assert.Contains(t, code, "//\n// Deprecated: FindPets is deprecated, and may stop working after Sun, 30 Jun 2030 00:00:00 GMT.\nfunc (c *ClientWithResponses) FindPetsWithResponse(")
+		assert.NotContains(t, code, "Deprecated: GetPet")
+
+		// Clients report responses with a Deprecation header.
+		assert.Contains(t, code, "func WithOnDeprecatedCall(fn DeprecatedCallFn) ClientOption {")
+		assert.Contains(t, code, `c.notifyDeprecatedCall(ctx, "GetPet", rsp)`)
+
+		// Servers send the Deprecation and Sunset headers.
+		assert.Contains(t, code, `Header().Set("Deprecation", "true")`)
+		assert.Contains(t, code, `Header().Set("Sunset", "Sun, 30 Jun 2030 00:00:00 GMT")`)
+	}
+
+	swagger.Paths["/pets"].Get.Extensions[extPropSunset] = []byte(`"soon"`)
+	_, err = OperationDefinitions(swagger)
+	assert.Error(t, err)
+}
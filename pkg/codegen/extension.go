@@ -3,12 +3,15 @@ package codegen
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
 const (
 	extPropGoType = "x-go-type"
+	extPropSunset = "x-sunset"
 )
 
 func extTypeName(extPropValue interface{}) (string, error) {
@@ -23,3 +26,24 @@ func extTypeName(extPropValue interface{}) (string, error) {
 
 	return name, nil
 }
+
+// extSunset returns the date after which an operation may stop working, in
+// the HTTP-date format of the Sunset header. The extension holds either an
+// HTTP-date, or an RFC 3339 date or date-time.
+func extSunset(extPropValue interface{}) (string, error) {
+	raw, ok := extPropValue.(json.RawMessage)
+	if !ok {
+		return "", fmt.Errorf("failed to convert type: %T", extPropValue)
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal json")
+	}
+
+	for _, layout := range []string{http.TimeFormat, time.RFC3339, "2006-01-02"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.UTC().Format(http.TimeFormat), nil
+		}
+	}
+	return "", fmt.Errorf("'%s' is neither an HTTP-date nor an RFC 3339 date", value)
+}
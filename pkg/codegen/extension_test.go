@@ -48,3 +48,27 @@ func Test_extTypeName(t *testing.T) {
 		})
 	}
 }
+
+func Test_extSunset(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{json.RawMessage(`"Sat, 31 Dec 2022 23:59:59 GMT"`), "Sat, 31 Dec 2022 23:59:59 GMT", false},
+		{json.RawMessage(`"2022-12-31"`), "Sat, 31 Dec 2022 00:00:00 GMT", false},
+		{json.RawMessage(`"2022-12-31T12:00:00+02:00"`), "Sat, 31 Dec 2022 10:00:00 GMT", false},
+		{json.RawMessage(`"next year"`), "", true},
+		{json.RawMessage(`20221231`), "", true},
+		{nil, "", true},
+	}
+	for _, tt := range tests {
+		got, err := extSunset(tt.value)
+		if tt.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+}
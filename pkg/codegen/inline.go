@@ -75,3 +75,41 @@ func GenerateInlinedSpec(t *template.Template, swagger *openapi3.Swagger) (strin
 	}
 	return buf.String(), nil
 }
+
+// GenerateValidator generates the validators of requests to the server, for
+// echo when echo is true, and otherwise for chi and net/http, which are bound
+// to the spec inlined by GenerateInlinedSpec.
+func GenerateValidator(t *template.Template, echo bool) (string, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	err := t.ExecuteTemplate(w, "validator.tmpl", struct {
+		Echo bool
+	}{echo})
+	if err != nil {
+		return "", fmt.Errorf("error generating validator: %s", err)
+	}
+	err = w.Flush()
+	if err != nil {
+		return "", fmt.Errorf("error flushing output buffer for validator: %s", err)
+	}
+	return buf.String(), nil
+}
+
+// GenerateSpecHandler generates the handler serving the spec inlined by
+// GenerateInlinedSpec, and the function registering it with the echo router
+// when echo is true, and otherwise with chi or net/http.
+func GenerateSpecHandler(t *template.Template, echo bool) (string, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	err := t.ExecuteTemplate(w, "spec-handler.tmpl", struct {
+		Echo bool
+	}{echo})
+	if err != nil {
+		return "", fmt.Errorf("error generating spec handler: %s", err)
+	}
+	err = w.Flush()
+	if err != nil {
+		return "", fmt.Errorf("error flushing output buffer for spec handler: %s", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,303 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// LintSeverity is how badly a construct of a spec degrades the generated code.
+type LintSeverity int
+
+const (
+	// LintInfo is for constructs which generate a less specific type than the
+	// spec describes, such as a string for a string format we don't know.
+	LintInfo LintSeverity = iota
+	// LintWarning is for constructs which generate no typed API at all, such
+	// as oneOf schemas, or bodies and responses of unsupported content types.
+	LintWarning
+	// LintError is for constructs which code can't be generated for.
+	LintError
+)
+
+var lintSeverityNames = []string{"info", "warning", "error"}
+
+func (s LintSeverity) String() string {
+	if s < 0 || int(s) >= len(lintSeverityNames) {
+		return fmt.Sprintf("LintSeverity(%d)", int(s))
+	}
+	return lintSeverityNames[s]
+}
+
+// ParseLintSeverity returns the severity of the given name: info, warning or
+// error.
+func ParseLintSeverity(name string) (LintSeverity, error) {
+	for i, n := range lintSeverityNames {
+		if n == name {
+			return LintSeverity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown lint severity '%s', must be one of %s", name, strings.Join(lintSeverityNames, ", "))
+}
+
+// LintFinding is a construct of a spec which won't generate a typed API.
+type LintFinding struct {
+	// Pointer is the JSON pointer to the construct in the spec, eg,
+	// #/components/schemas/Pet/oneOf
+	Pointer  string
+	Severity LintSeverity
+	Message  string
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Pointer, f.Message)
+}
+
+// Lint walks the spec, and reports every construct which code generation
+// silently degrades, or fails on, in the order they appear in the sorted
+// spec. Referenced schemas, parameters, bodies and responses are reported
+// where they're defined under #/components, rather than where they're used.
+func Lint(swagger *openapi3.Swagger) []LintFinding {
+	l := &linter{}
+	for _, path := range SortedPathsKeys(swagger.Paths) {
+		l.pathItem(lintPointer("#", "paths", path), swagger.Paths[path])
+	}
+	l.components(lintPointer("#", "components"), &swagger.Components)
+	return l.findings
+}
+
+// linter collects the findings of Lint.
+type linter struct {
+	findings []LintFinding
+}
+
+func (l *linter) report(pointer string, severity LintSeverity, format string, args ...interface{}) {
+	l.findings = append(l.findings, LintFinding{
+		Pointer:  pointer,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// lintPointer appends the given reference tokens to a JSON pointer, escaping
+// them as RFC 6901 requires.
+func lintPointer(pointer string, tokens ...string) string {
+	for _, token := range tokens {
+		token = strings.Replace(token, "~", "~0", -1)
+		token = strings.Replace(token, "/", "~1", -1)
+		pointer += "/" + token
+	}
+	return pointer
+}
+
+func (l *linter) pathItem(pointer string, pathItem *openapi3.PathItem) {
+	if pathItem == nil {
+		return
+	}
+	for i, param := range pathItem.Parameters {
+		l.parameterRef(lintPointer(pointer, "parameters", strconv.Itoa(i)), param)
+	}
+	operations := pathItem.Operations()
+	for _, method := range SortedOperationsKeys(operations) {
+		l.operation(lintPointer(pointer, strings.ToLower(method)), operations[method])
+	}
+}
+
+func (l *linter) operation(pointer string, op *openapi3.Operation) {
+	for i, param := range op.Parameters {
+		l.parameterRef(lintPointer(pointer, "parameters", strconv.Itoa(i)), param)
+	}
+	if op.RequestBody != nil && op.RequestBody.Ref == "" {
+		l.requestBody(lintPointer(pointer, "requestBody"), op.RequestBody.Value)
+	}
+	for _, code := range SortedResponsesKeys(op.Responses) {
+		response := op.Responses[code]
+		if response.Ref == "" {
+			l.response(lintPointer(pointer, "responses", code), response.Value)
+		}
+	}
+	for _, name := range SortedCallbackRefKeys(op.Callbacks) {
+		callback := op.Callbacks[name]
+		if callback.Value == nil {
+			continue
+		}
+		for _, expression := range SortedCallbackKeys(*callback.Value) {
+			l.pathItem(lintPointer(pointer, "callbacks", name, expression), (*callback.Value)[expression])
+		}
+	}
+}
+
+func (l *linter) components(pointer string, components *openapi3.Components) {
+	for _, name := range SortedSchemaKeys(components.Schemas) {
+		l.schema(lintPointer(pointer, "schemas", name), components.Schemas[name])
+	}
+	for _, name := range SortedParameterKeys(components.Parameters) {
+		l.parameterRef(lintPointer(pointer, "parameters", name), components.Parameters[name])
+	}
+	for _, name := range SortedHeaderKeys(components.Headers) {
+		header := components.Headers[name]
+		if header.Ref == "" && header.Value != nil {
+			l.schema(lintPointer(pointer, "headers", name, "schema"), header.Value.Schema)
+		}
+	}
+	for _, name := range SortedRequestBodyKeys(components.RequestBodies) {
+		body := components.RequestBodies[name]
+		if body.Ref == "" {
+			l.requestBody(lintPointer(pointer, "requestBodies", name), body.Value)
+		}
+	}
+	for _, name := range SortedResponsesKeys(components.Responses) {
+		response := components.Responses[name]
+		if response.Ref == "" {
+			l.response(lintPointer(pointer, "responses", name), response.Value)
+		}
+	}
+}
+
+func (l *linter) parameterRef(pointer string, param *openapi3.ParameterRef) {
+	if param == nil || param.Ref != "" || param.Value == nil {
+		return
+	}
+	p := param.Value
+	l.schema(lintPointer(pointer, "schema"), p.Schema)
+	for _, contentType := range SortedContentKeys(p.Content) {
+		if len(p.Content) > 1 || contentType != "application/json" {
+			l.report(lintPointer(pointer, "content", contentType), LintInfo,
+				"parameter content type %s is passed through as a string", contentType)
+			continue
+		}
+		l.schema(lintPointer(pointer, "content", contentType, "schema"), p.Content[contentType].Schema)
+	}
+}
+
+// requestBody reports the content types GenerateBodyDefinitions skips.
+func (l *linter) requestBody(pointer string, body *openapi3.RequestBody) {
+	if body == nil {
+		return
+	}
+	for _, contentType := range SortedContentKeys(body.Content) {
+		contentPointer := lintPointer(pointer, "content", contentType)
+		if _, found := requestBodyNameTags[contentType]; !found {
+			l.report(contentPointer, LintWarning,
+				"request body content type %s is not supported, no typed body is generated for it", contentType)
+			continue
+		}
+		switch contentType {
+		case "text/plain", "application/octet-stream":
+			// The schema doesn't matter, these are always a string or a stream.
+		default:
+			l.schema(lintPointer(contentPointer, "schema"), body.Content[contentType].Schema)
+		}
+	}
+}
+
+// response reports the content types GetResponseTypeDefinitions drops, which
+// the strict server writes as they are.
+func (l *linter) response(pointer string, response *openapi3.Response) {
+	if response == nil {
+		return
+	}
+	for _, name := range SortedHeaderKeys(response.Headers) {
+		header := response.Headers[name]
+		if header.Ref == "" && header.Value != nil {
+			l.schema(lintPointer(pointer, "headers", name, "schema"), header.Value.Schema)
+		}
+	}
+	for _, contentType := range SortedContentKeys(response.Content) {
+		contentPointer := lintPointer(pointer, "content", contentType)
+		if !StringInArray(contentType, contentTypesJSON) &&
+			!StringInArray(contentType, contentTypesYAML) &&
+			!StringInArray(contentType, contentTypesXML) {
+			l.report(contentPointer, LintWarning,
+				"response content type %s is not supported, it's dropped from the typed client responses", contentType)
+			continue
+		}
+		schema := response.Content[contentType].Schema
+		if schema == nil {
+			l.report(contentPointer, LintWarning,
+				"response content has no schema, it's dropped from the typed responses")
+			continue
+		}
+		l.schema(lintPointer(contentPointer, "schema"), schema)
+	}
+}
+
+// schema reports what GenerateGoSchema degrades, or fails on, in the given
+// schema and the schemas it's made of.
+func (l *linter) schema(pointer string, sref *openapi3.SchemaRef) {
+	// References are reported where they're defined.
+	if sref == nil || sref.Ref != "" || sref.Value == nil {
+		return
+	}
+	schema := sref.Value
+
+	if schema.AnyOf != nil {
+		l.report(lintPointer(pointer, "anyOf"), LintWarning, "anyOf is not supported, it's generated as interface{}")
+		return
+	}
+	if schema.OneOf != nil {
+		l.report(lintPointer(pointer, "oneOf"), LintWarning, "oneOf is not supported, it's generated as interface{}")
+		return
+	}
+	if schema.Not != nil {
+		l.report(lintPointer(pointer, "not"), LintInfo, "not is ignored")
+	}
+	if schema.AllOf != nil {
+		for i, s := range schema.AllOf {
+			l.schema(lintPointer(pointer, "allOf", strconv.Itoa(i)), s)
+		}
+		return
+	}
+	if extension, ok := schema.Extensions[extPropGoType]; ok {
+		if _, err := extTypeName(extension); err != nil {
+			l.report(lintPointer(pointer, extPropGoType), LintError, "invalid value for %q: %s", extPropGoType, err)
+		}
+		return
+	}
+
+	f := schema.Format
+	switch schema.Type {
+	case "", "object":
+		if len(schema.Properties) == 0 && !SchemaHasAdditionalProperties(schema) {
+			if schema.Type == "" {
+				l.report(pointer, LintInfo, "schema has no type, it's generated as interface{}")
+			}
+			return
+		}
+		for _, name := range SortedSchemaKeys(schema.Properties) {
+			l.schema(lintPointer(pointer, "properties", name), schema.Properties[name])
+		}
+		l.schema(lintPointer(pointer, "additionalProperties"), schema.AdditionalProperties)
+	case "array":
+		if schema.Items == nil {
+			l.report(pointer, LintInfo, "array has no items, its items are generated as interface{}")
+			return
+		}
+		l.schema(lintPointer(pointer, "items"), schema.Items)
+	case "integer":
+		if !StringInArray(f, []string{"", "int32", "int64", "uint32", "uint64"}) {
+			l.report(lintPointer(pointer, "format"), LintError, "invalid integer format: %s", f)
+		}
+	case "number":
+		if !StringInArray(f, []string{"", "float", "double"}) {
+			l.report(lintPointer(pointer, "format"), LintError, "invalid number format: %s", f)
+		}
+	case "boolean":
+		if f != "" {
+			l.report(lintPointer(pointer, "format"), LintError, "invalid format (%s) for boolean", f)
+		}
+	case "string":
+		for i, value := range schema.Enum {
+			if _, ok := value.(string); !ok {
+				l.report(lintPointer(pointer, "enum", strconv.Itoa(i)), LintError, "string enum value %v is not a string", value)
+			}
+		}
+		if !StringInArray(f, []string{"", "byte", "email", "date", "date-time", "binary", "json"}) {
+			l.report(lintPointer(pointer, "format"), LintInfo, "unknown string format %s, it's generated as string", f)
+		}
+	default:
+		l.report(lintPointer(pointer, "type"), LintError, "unhandled Schema type: %s", schema.Type)
+	}
+}
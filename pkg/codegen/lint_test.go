@@ -0,0 +1,131 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lintOpenAPIDefinition = `
+openapi: 3.0.1
+info:
+  title: Lint
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    parameters:
+    - name: id
+      in: path
+      required: true
+      schema:
+        type: string
+        format: uuid
+    put:
+      operationId: updatePet
+      parameters:
+      - name: filter
+        in: query
+        content:
+          application/xml:
+            schema:
+              type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+          application/xml:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        200:
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+            text/csv:
+              schema:
+                type: string
+        default:
+          $ref: '#/components/responses/Error'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        age:
+          type: integer
+          format: int8
+        kind:
+          oneOf:
+          - $ref: '#/components/schemas/Cat'
+          - $ref: '#/components/schemas/Dog'
+        tags:
+          type: array
+          items:
+            anyOf:
+            - type: string
+            - type: integer
+        anything: {}
+    Cat:
+      type: object
+      properties:
+        name:
+          type: string
+    Dog:
+      allOf:
+      - $ref: '#/components/schemas/Cat'
+      - type: object
+        properties:
+          weight:
+            type: number
+            format: decimal
+  responses:
+    Error:
+      description: Error
+      content:
+        application/problem+json:
+          schema:
+            type: object
+`
+
+func TestLint(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(lintOpenAPIDefinition))
+	require.NoError(t, err)
+
+	assert.Equal(t, []LintFinding{
+		{"#/paths/~1pets~1{id}/parameters/0/schema/format", LintInfo, "unknown string format uuid, it's generated as string"},
+		{"#/paths/~1pets~1{id}/put/parameters/0/content/application~1xml", LintInfo, "parameter content type application/xml is passed through as a string"},
+		{"#/paths/~1pets~1{id}/put/requestBody/content/application~1xml", LintWarning, "request body content type application/xml is not supported, no typed body is generated for it"},
+		{"#/paths/~1pets~1{id}/put/responses/200/content/text~1csv", LintWarning, "response content type text/csv is not supported, it's dropped from the typed client responses"},
+		{"#/components/schemas/Dog/allOf/1/properties/weight/format", LintError, "invalid number format: decimal"},
+		{"#/components/schemas/Pet/properties/age/format", LintError, "invalid integer format: int8"},
+		{"#/components/schemas/Pet/properties/anything", LintInfo, "schema has no type, it's generated as interface{}"},
+		{"#/components/schemas/Pet/properties/kind/oneOf", LintWarning, "oneOf is not supported, it's generated as interface{}"},
+		{"#/components/schemas/Pet/properties/tags/items/anyOf", LintWarning, "anyOf is not supported, it's generated as interface{}"},
+		{"#/components/responses/Error/content/application~1problem+json", LintWarning, "response content type application/problem+json is not supported, it's dropped from the typed client responses"},
+	}, Lint(swagger))
+}
+
+func TestLintPetStore(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testOpenAPIDefinition))
+	require.NoError(t, err)
+
+	for _, finding := range Lint(swagger) {
+		assert.NotEqual(t, LintError, finding.Severity, finding.String())
+	}
+}
+
+func TestParseLintSeverity(t *testing.T) {
+	for _, severity := range []LintSeverity{LintInfo, LintWarning, LintError} {
+		parsed, err := ParseLintSeverity(severity.String())
+		require.NoError(t, err)
+		assert.Equal(t, severity, parsed)
+	}
+
+	_, err := ParseLintSeverity("fatal")
+	assert.EqualError(t, err, "unknown lint severity 'fatal', must be one of info, warning, error")
+}
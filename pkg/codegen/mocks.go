@@ -0,0 +1,229 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package codegen
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// This describes the mock generated for one of the generated interfaces.
+type MockDefinition struct {
+	// The name of the interface, eg, ClientInterface
+	InterfaceName string
+
+	Methods []MockMethodDefinition
+}
+
+// The name of the mock type, eg, ClientInterfaceMock
+func (m MockDefinition) TypeName() string {
+	return m.InterfaceName + "Mock"
+}
+
+// The name of the unexported variable listing the methods of the mock, eg,
+// clientInterfaceMockMethods
+func (m MockDefinition) MethodsVarName() string {
+	return LowercaseFirstCharacter(m.TypeName()) + "Methods"
+}
+
+// This describes a method of a mocked interface.
+type MockMethodDefinition struct {
+	Name    string
+	Params  []MockParamDefinition
+	Results []string // The types of the results
+}
+
+// The Go parameter declarations of the method, eg,
+// "ctx context.Context, id int64"
+func (m MockMethodDefinition) ParamArgs() string {
+	parts := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		parts[i] = p.Name + " " + p.TypeDecl
+	}
+	return strings.Join(parts, ", ")
+}
+
+// The names of the parameters of the method, eg, "ctx, id"
+func (m MockMethodDefinition) ParamNames() string {
+	parts := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		parts[i] = p.Name
+	}
+	return strings.Join(parts, ", ")
+}
+
+// The results of the method as they're declared, eg, "(*http.Response,
+// error)", or "" for methods without results
+func (m MockMethodDefinition) ResultsDecl() string {
+	switch len(m.Results) {
+	case 0:
+		return ""
+	case 1:
+		return m.Results[0]
+	default:
+		return "(" + strings.Join(m.Results, ", ") + ")"
+	}
+}
+
+// The type of the struct holding the arguments of a call to the method, which
+// has a field for each parameter.
+func (m MockMethodDefinition) CallType() string {
+	fields := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		fields[i] = p.FieldName() + " " + p.TypeDecl
+	}
+	return "struct {\n" + strings.Join(fields, "\n") + "\n}"
+}
+
+// This describes a parameter of a mocked method.
+type MockParamDefinition struct {
+	Name     string
+	TypeDecl string
+}
+
+// The name of the field holding the argument in the record of a call, eg,
+// Ctx
+func (p MockParamDefinition) FieldName() string {
+	return UppercaseFirstCharacter(p.Name)
+}
+
+// operationMockParams returns the parameters of the path parameters, and the
+// parameter object, of an operation, which follow the leading parameters of
+// the methods of the server and client interfaces.
+func operationMockParams(op OperationDefinition, paramsPointer bool) []MockParamDefinition {
+	var params []MockParamDefinition
+	for _, p := range op.PathParams {
+		params = append(params, MockParamDefinition{Name: p.GoVariableName(), TypeDecl: p.TypeDef()})
+	}
+	if op.RequiresParamObject() {
+		typeDecl := op.OperationId + "Params"
+		if paramsPointer {
+			typeDecl = "*" + typeDecl
+		}
+		params = append(params, MockParamDefinition{Name: "params", TypeDecl: typeDecl})
+	}
+	return params
+}
+
+// clientMockMethods returns the methods which the client interfaces have for
+// an operation: one taking any body, and one for each typed body, returning
+// the given results.
+func clientMockMethods(op OperationDefinition, suffix string, results []string) []MockMethodDefinition {
+	ctx := MockParamDefinition{Name: "ctx", TypeDecl: "context.Context"}
+	params := append([]MockParamDefinition{ctx}, operationMockParams(op, true)...)
+
+	var methods []MockMethodDefinition
+	if op.HasBody() {
+		methods = append(methods, MockMethodDefinition{
+			Name: op.OperationId + "WithBody" + suffix,
+			Params: append(append([]MockParamDefinition{}, params...),
+				MockParamDefinition{Name: "contentType", TypeDecl: "string"},
+				MockParamDefinition{Name: "body", TypeDecl: "io.Reader"}),
+			Results: results,
+		})
+	} else {
+		methods = append(methods, MockMethodDefinition{
+			Name:    op.OperationId + suffix,
+			Params:  params,
+			Results: results,
+		})
+	}
+	for _, body := range op.Bodies {
+		methods = append(methods, MockMethodDefinition{
+			Name: op.OperationId + body.Suffix() + suffix,
+			Params: append(append([]MockParamDefinition{}, params...),
+				MockParamDefinition{Name: "body", TypeDecl: op.OperationId + body.NameTag + "RequestBody"}),
+			Results: results,
+		})
+	}
+	return methods
+}
+
+// DescribeMocks returns the mocks of the interfaces generated for the given
+// options: ServerInterface, StrictServerInterface, ClientInterface and
+// ClientWithResponsesInterface.
+func DescribeMocks(ops []OperationDefinition, opts Options) []MockDefinition {
+	var mocks []MockDefinition
+
+	if opts.GenerateEchoServer || opts.GenerateChiServer || opts.GenerateStdHTTPServer {
+		mock := MockDefinition{InterfaceName: "ServerInterface"}
+		for _, op := range ops {
+			method := MockMethodDefinition{Name: op.OperationId}
+			if opts.GenerateEchoServer {
+				method.Params = []MockParamDefinition{{Name: "ctx", TypeDecl: "echo.Context"}}
+				method.Results = []string{"error"}
+			} else {
+				method.Params = []MockParamDefinition{
+					{Name: "w", TypeDecl: "http.ResponseWriter"},
+					{Name: "r", TypeDecl: "*http.Request"},
+				}
+			}
+			method.Params = append(method.Params, operationMockParams(op, false)...)
+			for _, body := range op.DecodedBodies() {
+				method.Params = append(method.Params, MockParamDefinition{Name: body.ArgName(), TypeDecl: op.BodyArgTypeDecl(body)})
+			}
+			mock.Methods = append(mock.Methods, method)
+		}
+		mocks = append(mocks, mock)
+	}
+
+	if opts.GenerateStrictServer {
+		mock := MockDefinition{InterfaceName: "StrictServerInterface"}
+		for _, op := range ops {
+			mock.Methods = append(mock.Methods, MockMethodDefinition{
+				Name: op.OperationId,
+				Params: []MockParamDefinition{
+					{Name: "ctx", TypeDecl: "context.Context"},
+					{Name: "request", TypeDecl: op.OperationId + "RequestObject"},
+				},
+				Results: []string{op.OperationId + "ResponseObject", "error"},
+			})
+		}
+		mocks = append(mocks, mock)
+	}
+
+	if opts.GenerateClient {
+		client := MockDefinition{InterfaceName: "ClientInterface"}
+		withResponses := MockDefinition{InterfaceName: "ClientWithResponsesInterface"}
+		for _, op := range ops {
+			client.Methods = append(client.Methods,
+				clientMockMethods(op, "", []string{"*http.Response", "error"})...)
+			withResponses.Methods = append(withResponses.Methods,
+				clientMockMethods(op, "WithResponse", []string{"*" + genResponseTypeName(op.OperationId), "error"})...)
+		}
+		mocks = append(mocks, client, withResponses)
+	}
+
+	return mocks
+}
+
+// GenerateMocks generates recording mocks of the given interfaces.
+func GenerateMocks(t *template.Template, mocks []MockDefinition) (string, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	err := t.ExecuteTemplate(w, "mocks.tmpl", mocks)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating mocks")
+	}
+	err = w.Flush()
+	if err != nil {
+		return "", errors.Wrap(err, "error flushing output buffer for mocks")
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,70 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeMocks(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(clientErrorsOpenAPIDefinition))
+	require.NoError(t, err)
+	ops, err := OperationDefinitions(swagger)
+	require.NoError(t, err)
+
+	mocks := DescribeMocks(ops, Options{GenerateChiServer: true, GenerateStrictServer: true, GenerateClient: true})
+	require.Len(t, mocks, 4)
+
+	assert.Equal(t, "ServerInterfaceMock", mocks[0].TypeName())
+	assert.Equal(t, "serverInterfaceMockMethods", mocks[0].MethodsVarName())
+	method := mocks[0].Methods[1]
+	assert.Equal(t, "GetThing", method.Name)
+	assert.Equal(t, "w http.ResponseWriter, r *http.Request, id string", method.ParamArgs())
+	assert.Equal(t, "w, r, id", method.ParamNames())
+	assert.Equal(t, "", method.ResultsDecl())
+	assert.Equal(t, "struct {\nW http.ResponseWriter\nR *http.Request\nId string\n}", method.CallType())
+
+	assert.Equal(t, "StrictServerInterfaceMock", mocks[1].TypeName())
+	assert.Equal(t, "(GetThingResponseObject, error)", mocks[1].Methods[1].ResultsDecl())
+
+	assert.Equal(t, "ClientInterfaceMock", mocks[2].TypeName())
+	assert.Equal(t, "(*http.Response, error)", mocks[2].Methods[1].ResultsDecl())
+	assert.Equal(t, "ClientWithResponsesInterfaceMock", mocks[3].TypeName())
+	assert.Equal(t, "GetThingWithResponse", mocks[3].Methods[1].Name)
+	assert.Equal(t, "(*GetThingResponse, error)", mocks[3].Methods[1].ResultsDecl())
+
+	mocks = DescribeMocks(ops, Options{GenerateEchoServer: true})
+	require.Len(t, mocks, 1)
+	assert.Equal(t, "ctx echo.Context, id string", mocks[0].Methods[1].ParamArgs())
+	assert.Equal(t, "error", mocks[0].Methods[1].ResultsDecl())
+}
+
+func TestGenerateMocks(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testOpenAPIDefinition))
+	require.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{
+		GenerateTypes:      true,
+		GenerateEchoServer: true,
+		GenerateClient:     true,
+		GenerateMocks:      true,
+	})
+	require.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "var _ ServerInterface = &ServerInterfaceMock{}")
+	assert.Contains(t, code, "var _ ClientInterface = &ClientInterfaceMock{}")
+	assert.Contains(t, code, "var _ ClientWithResponsesInterface = &ClientWithResponsesInterfaceMock{}")
+	assert.NotContains(t, code, "StrictServerInterfaceMock")
+
+	// Each method has a function field, records its calls and returns them.
+	assert.Contains(t, code, "GetCatStatusFunc func(ctx echo.Context) error")
+	assert.Contains(t, code, `mock.recorder.Record("GetCatStatus", struct {`)
+	assert.Contains(t, code, "func (mock *ServerInterfaceMock) GetCatStatusCalls() []struct {")
+	assert.Contains(t, code, "func (mock *ClientInterfaceMock) AssertCalled(t runtime.TestReporter, method string, times int) bool {")
+}
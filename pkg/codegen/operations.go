@@ -16,6 +16,7 @@ package codegen
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"text/template"
@@ -113,7 +114,7 @@ func (pd ParameterDefinition) GoVariableName() string {
 	if IsGoKeyword(name) {
 		name = "p" + UppercaseFirstCharacter(name)
 	}
-	if unicode.IsNumber([]rune(name)[0]) {
+	if name != "" && unicode.IsNumber([]rune(name)[0]) {
 		name = "n" + name
 	}
 	return name
@@ -123,6 +124,12 @@ func (pd ParameterDefinition) GoName() string {
 	return ToCamelCase(pd.ParamName)
 }
 
+// The name of a struct field holding the parameter, which, like the fields of
+// the params object, is prefixed when it would start with a digit.
+func (pd ParameterDefinition) GoFieldName() string {
+	return SchemaNameToTypeName(pd.ParamName)
+}
+
 func (pd ParameterDefinition) IndirectOptional() bool {
 	return !pd.Required && !pd.Schema.SkipOptionalPointer
 }
@@ -142,11 +149,15 @@ func (p ParameterDefinitions) FindByName(name string) *ParameterDefinition {
 // descriptors into a flat list. This makes it a lot easier to traverse the
 // data in the template engine.
 func DescribeParameters(params openapi3.Parameters, path []string) ([]ParameterDefinition, error) {
+	return describeParameters(params, path, nil)
+}
+
+func describeParameters(params openapi3.Parameters, path []string, overrides typeNameOverrides) ([]ParameterDefinition, error) {
 	outParams := make([]ParameterDefinition, 0)
 	for _, paramOrRef := range params {
 		param := paramOrRef.Value
 
-		goType, err := paramToGoType(param, append(path, param.Name))
+		goType, err := paramToGoType(param, append(path, param.Name), overrides)
 		if err != nil {
 			return nil, fmt.Errorf("error generating type for param (%s): %s",
 				param.Name, err)
@@ -164,7 +175,7 @@ func DescribeParameters(params openapi3.Parameters, path []string) ([]ParameterD
 		// name as the type. $ref: "#/components/schemas/custom_type" becomes
 		// "CustomType".
 		if paramOrRef.Ref != "" {
-			goType, err := RefPathToGoType(paramOrRef.Ref)
+			goType, err := overrides.refPathToGoType(paramOrRef.Ref)
 			if err != nil {
 				return nil, fmt.Errorf("error dereferencing (%s) for param (%s): %s",
 					paramOrRef.Ref, param.Name, err)
@@ -204,11 +215,19 @@ type OperationDefinition struct {
 	TypeDefinitions     []TypeDefinition      // These are all the types we need to define for this operation
 	SecurityDefinitions []SecurityDefinition  // These are the security providers
 	BodyRequired        bool
-	Bodies              []RequestBodyDefinition // The list of bodies for which to generate handlers.
-	Summary             string                  // Summary string from Swagger, used to generate a comment
-	Method              string                  // GET, POST, DELETE, etc.
-	Path                string                  // The Swagger path for the operation, like /resource/{id}
+	Bodies              []RequestBodyDefinition     // The list of bodies for which to generate handlers.
+	ResponseHeaders     []ResponseHeadersDefinition // The headers declared for each response
+	Summary             string                      // Summary string from Swagger, used to generate a comment
+	Method              string                      // GET, POST, DELETE, etc.
+	Path                string                      // The Swagger path for the operation, like /resource/{id}
+	Callback            *CallbackDefinition         // The callback this is an operation of, nil for the operations of the API itself
+	Deprecated          bool                        // Whether the operation is deprecated
+	Sunset              string                      // The HTTP-date after which the operation may stop working, from x-sunset
 	Spec                *openapi3.Operation
+
+	// The type names of renamed components, for the types described while
+	// the templates are executed
+	typeNameOverrides typeNameOverrides
 }
 
 // Returns the list of all parameters except Path parameters. Path parameters
@@ -234,6 +253,27 @@ func (o *OperationDefinition) RequiresParamObject() bool {
 	return len(o.Params()) > 0
 }
 
+// Returns whether the chi and net/http wrappers of the operation keep the
+// errors of binding its path, query and header parameters in an err variable
+// of their own, which they then declare. Cookies are bound with errors scoped
+// to their blocks, and pass-through parameters can't fail to bind.
+func (o *OperationDefinition) BindsParamsWithErr() bool {
+	for _, params := range [][]ParameterDefinition{o.PathParams, o.QueryParams} {
+		for i := range params {
+			if params[i].IsJson() || params[i].IsStyled() {
+				return true
+			}
+		}
+	}
+	for i := range o.HeaderParams {
+		// The error of a missing required header mentions err too.
+		if o.HeaderParams[i].IsJson() || o.HeaderParams[i].IsStyled() || o.HeaderParams[i].Required {
+			return true
+		}
+	}
+	return false
+}
+
 // This is called by the template engine to determine whether to generate body
 // marshaling code on the client. This is true for all body types, whether or
 // not we generate types for them.
@@ -254,6 +294,16 @@ func (o *OperationDefinition) SummaryAsComment() string {
 	return strings.Join(parts, "\n")
 }
 
+// Returns the paragraph which marks the generated client functions of a
+// deprecated operation as deprecated.
+func (o *OperationDefinition) DeprecationNotice() string {
+	notice := "Deprecated: " + o.OperationId + " is deprecated"
+	if o.Sunset != "" {
+		notice += ", and may stop working after " + o.Sunset
+	}
+	return notice + "."
+}
+
 // Produces a list of type definitions for a given Operation for the response
 // types which we know how to parse. These will be turned into fields on a
 // response object for automatic deserialization of responses in the generated
@@ -280,7 +330,7 @@ func (o *OperationDefinition) GetResponseTypeDefinitions() ([]TypeDefinition, er
 				contentType := responseRef.Value.Content[contentTypeName]
 				// We can only generate a type if we have a schema:
 				if contentType.Schema != nil {
-					responseSchema, err := GenerateGoSchema(contentType.Schema, []string{responseName})
+					responseSchema, err := generateGoSchema(contentType.Schema, []string{responseName}, o.typeNameOverrides)
 					if err != nil {
 						return nil, errors.Wrap(err, fmt.Sprintf("Unable to determine Go type for %s.%s", o.OperationId, contentTypeName))
 					}
@@ -305,7 +355,7 @@ func (o *OperationDefinition) GetResponseTypeDefinitions() ([]TypeDefinition, er
 						ResponseName: responseName,
 					}
 					if contentType.Schema.Ref != "" {
-						refType, err := RefPathToGoType(contentType.Schema.Ref)
+						refType, err := o.typeNameOverrides.refPathToGoType(contentType.Schema.Ref)
 						if err != nil {
 							return nil, errors.Wrap(err, "error dereferencing response Ref")
 						}
@@ -319,6 +369,108 @@ func (o *OperationDefinition) GetResponseTypeDefinitions() ([]TypeDefinition, er
 	return tds, nil
 }
 
+// This describes the headers declared for one of the responses of an
+// operation, which the client binds into a struct, and which strict server
+// handlers return.
+type ResponseHeadersDefinition struct {
+	// The name of the struct type holding the headers, eg,
+	// FindPets200ResponseHeaders
+	TypeName string
+
+	// The name of the response in the spec, eg, 200, 2XX or default
+	ResponseName string
+
+	Headers []ResponseHeaderDefinition
+}
+
+// The name of the field holding these headers in the response type of the
+// client, eg, Headers200
+func (r ResponseHeadersDefinition) FieldName() string {
+	return "Headers" + ToCamelCase(r.ResponseName)
+}
+
+// The condition on rsp.StatusCode under which these headers are bound
+func (r ResponseHeadersDefinition) StatusCondition() string {
+	return getConditionOfResponseName("rsp.StatusCode", r.ResponseName)
+}
+
+// This describes a single response header
+type ResponseHeaderDefinition struct {
+	HeaderName  string
+	Description string
+	Required    bool
+	Schema      Schema
+}
+
+func (h ResponseHeaderDefinition) GoName() string {
+	return ToCamelCase(h.HeaderName)
+}
+
+func (h ResponseHeaderDefinition) TypeDef() string {
+	return h.Schema.TypeDecl()
+}
+
+// Returns whether the header is held by a pointer, as optional headers are.
+func (h ResponseHeaderDefinition) IndirectOptional() bool {
+	return !h.Required && !h.Schema.SkipOptionalPointer
+}
+
+// The type of the field holding the header, which is a pointer for optional
+// headers.
+func (h ResponseHeaderDefinition) GoTypeDef() string {
+	if h.IndirectOptional() {
+		return "*" + h.TypeDef()
+	}
+	return h.TypeDef()
+}
+
+// DescribeResponseHeaders returns the headers declared for each of the given
+// responses. Responses without headers are left out, and the rest are sorted
+// so that specific status codes come before ranges such as 2XX, followed by
+// default.
+func DescribeResponseHeaders(operationID string, responses openapi3.Responses) ([]ResponseHeadersDefinition, error) {
+	return describeResponseHeaders(operationID, responses, nil)
+}
+
+func describeResponseHeaders(operationID string, responses openapi3.Responses, overrides typeNameOverrides) ([]ResponseHeadersDefinition, error) {
+	var result []ResponseHeadersDefinition
+	for _, responseName := range SortedResponsesKeys(responses) {
+		response := responses[responseName].Value
+		if response == nil || len(response.Headers) == 0 {
+			continue
+		}
+		typeName := operationID + ToCamelCase(responseName) + "ResponseHeaders"
+
+		rh := ResponseHeadersDefinition{
+			TypeName:     typeName,
+			ResponseName: responseName,
+		}
+		for _, headerName := range SortedHeaderKeys(response.Headers) {
+			header := response.Headers[headerName].Value
+			if header == nil {
+				continue
+			}
+			// Headers without a schema are passed through as strings
+			schema := Schema{GoType: "string"}
+			if header.Schema != nil {
+				var err error
+				schema, err = generateGoSchema(header.Schema, []string{typeName, headerName}, overrides)
+				if err != nil {
+					return nil, errors.Wrap(err, fmt.Sprintf("error generating type for header %s of response %s", headerName, responseName))
+				}
+			}
+			rh.Headers = append(rh.Headers, ResponseHeaderDefinition{
+				HeaderName:  headerName,
+				Description: header.Description,
+				Required:    header.Required,
+				Schema:      schema,
+			})
+		}
+		result = append(result, rh)
+	}
+	return result, nil
+}
+
 // This describes a request body
 type RequestBodyDefinition struct {
 	// Is this body required, or optional?
@@ -337,6 +489,61 @@ type RequestBodyDefinition struct {
 	// Whether this is the default body type. For an operation named OpFoo, we
 	// will not add suffixes like OpFooJSONBody for this one.
 	Default bool
+
+	// The encodings of the parts of a multipart body, sorted by property name
+	Encodings []RequestBodyEncoding
+}
+
+// This describes how a property of a multipart request body is encoded
+type RequestBodyEncoding struct {
+	// The name of the property, and of the part which carries it
+	PropertyName string
+
+	// The allowed content types of the part, eg, "image/png, image/jpeg"
+	ContentType string
+
+	// The headers described for the part, mapped to whether they're required
+	Headers map[string]bool
+}
+
+// Returns whether the body is sent as JSON.
+func (r RequestBodyDefinition) IsJSON() bool {
+	return r.ContentType == "application/json"
+}
+
+// Returns whether the body is sent as application/x-www-form-urlencoded.
+func (r RequestBodyDefinition) IsFormEncoded() bool {
+	return r.ContentType == "application/x-www-form-urlencoded"
+}
+
+// Returns whether the body is sent as text/plain.
+func (r RequestBodyDefinition) IsText() bool {
+	return r.ContentType == "text/plain"
+}
+
+// Returns whether the body is sent as application/octet-stream.
+func (r RequestBodyDefinition) IsBinary() bool {
+	return r.ContentType == "application/octet-stream"
+}
+
+// Returns whether the body is sent as multipart/form-data.
+func (r RequestBodyDefinition) IsMultipart() bool {
+	return r.ContentType == "multipart/form-data"
+}
+
+// Returns whether the body is sent as multipart/form-data, with binary
+// properties which are sent as file parts. Servers stream these bodies to
+// handlers, rather than parsing them first.
+func (r RequestBodyDefinition) HasFileParts() bool {
+	if !r.IsMultipart() {
+		return false
+	}
+	for _, p := range r.Schema.Properties {
+		if strings.TrimPrefix(p.Schema.TypeDecl(), "[]") == "openapi_types.File" {
+			return true
+		}
+	}
+	return false
 }
 
 // Returns the Go type definition for a request body
@@ -362,6 +569,39 @@ func (r RequestBodyDefinition) Suffix() string {
 	return "With" + r.NameTag + "Body"
 }
 
+// The name of the argument passing the decoded body to server handlers, eg,
+// formdataBody.
+func (r RequestBodyDefinition) ArgName() string {
+	return LowercaseFirstCharacter(r.NameTag) + "Body"
+}
+
+// Returns the bodies which the server wrappers decode, and pass to handlers
+// as arguments. JSON bodies are left to handlers to bind.
+func (o *OperationDefinition) DecodedBodies() []RequestBodyDefinition {
+	var bodies []RequestBodyDefinition
+	for _, body := range o.Bodies {
+		if !body.IsJSON() {
+			bodies = append(bodies, body)
+		}
+	}
+	return bodies
+}
+
+// The type of the argument passing the given decoded body to server
+// handlers, which is nil when the request has another content type. Binary
+// bodies are readers, which are nil anyway, and multipart bodies with file
+// parts are streamed with a runtime.MultipartReader.
+func (o *OperationDefinition) BodyArgTypeDecl(body RequestBodyDefinition) string {
+	if body.HasFileParts() {
+		return "*runtime.MultipartReader"
+	}
+	typeDecl := o.OperationId + body.NameTag + "RequestBody"
+	if !body.IsBinary() {
+		typeDecl = "*" + typeDecl
+	}
+	return typeDecl
+}
+
 // This function returns the subset of the specified parameters which are of the
 // specified type.
 func FilterParameterDefinitionByType(params []ParameterDefinition, in string) []ParameterDefinition {
@@ -376,13 +616,17 @@ func FilterParameterDefinitionByType(params []ParameterDefinition, in string) []
 
 // OperationDefinitions returns all operations for a swagger definition.
 func OperationDefinitions(swagger *openapi3.Swagger) ([]OperationDefinition, error) {
+	return operationDefinitions(swagger, nil)
+}
+
+func operationDefinitions(swagger *openapi3.Swagger, overrides typeNameOverrides) ([]OperationDefinition, error) {
 	var operations []OperationDefinition
 
 	for _, requestPath := range SortedPathsKeys(swagger.Paths) {
 		pathItem := swagger.Paths[requestPath]
 		// These are parameters defined for all methods on a given path. They
 		// are shared by all methods.
-		globalParams, err := DescribeParameters(pathItem.Parameters, nil)
+		globalParams, err := describeParameters(pathItem.Parameters, nil, overrides)
 		if err != nil {
 			return nil, fmt.Errorf("error describing global parameters for %s: %s",
 				requestPath, err)
@@ -399,76 +643,102 @@ func OperationDefinitions(swagger *openapi3.Swagger) ([]OperationDefinition, err
 					return nil, fmt.Errorf("error generating default OperationID for %s/%s: %s",
 						opName, requestPath, err)
 				}
-				op.OperationID = op.OperationID
 			} else {
 				op.OperationID = ToCamelCase(op.OperationID)
 			}
 
-			// These are parameters defined for the specific path method that
-			// we're iterating over.
-			localParams, err := DescribeParameters(op.Parameters, []string{op.OperationID + "Params"})
-			if err != nil {
-				return nil, fmt.Errorf("error describing global parameters for %s/%s: %s",
-					opName, requestPath, err)
-			}
-			// All the parameters required by a handler are the union of the
-			// global parameters and the local parameters.
-			allParams := append(globalParams, localParams...)
-
-			// Order the path parameters to match the order as specified in
-			// the path, not in the swagger spec, and validate that the parameter
-			// names match, as downstream code depends on that.
-			pathParams := FilterParameterDefinitionByType(allParams, "path")
-			pathParams, err = SortParamsByPath(requestPath, pathParams)
+			opDef, err := describeOperation(requestPath, opName, op, globalParams, swagger.Security, overrides)
 			if err != nil {
 				return nil, err
 			}
+			operations = append(operations, opDef)
+		}
+	}
+	return operations, nil
+}
 
-			bodyDefinitions, typeDefinitions, err := GenerateBodyDefinitions(op.OperationID, op.RequestBody)
-			if err != nil {
-				return nil, errors.Wrap(err, "error generating body definitions")
-			}
+// describeOperation builds the definition of an operation whose OperationID
+// has been filled in. Operations which don't declare their own security get
+// defaultSecurity.
+func describeOperation(requestPath string, opName string, op *openapi3.Operation,
+	globalParams []ParameterDefinition, defaultSecurity openapi3.SecurityRequirements,
+	overrides typeNameOverrides) (OperationDefinition, error) {
+	// These are parameters defined for the specific path method that
+	// we're iterating over.
+	localParams, err := describeParameters(op.Parameters, []string{op.OperationID + "Params"}, overrides)
+	if err != nil {
+		return OperationDefinition{}, fmt.Errorf("error describing global parameters for %s/%s: %s",
+			opName, requestPath, err)
+	}
+	// All the parameters required by a handler are the union of the
+	// global parameters and the local parameters.
+	allParams := append(globalParams, localParams...)
+
+	// Order the path parameters to match the order as specified in
+	// the path, not in the swagger spec, and validate that the parameter
+	// names match, as downstream code depends on that.
+	pathParams := FilterParameterDefinitionByType(allParams, "path")
+	pathParams, err = SortParamsByPath(requestPath, pathParams)
+	if err != nil {
+		return OperationDefinition{}, err
+	}
 
-			opDef := OperationDefinition{
-				PathParams:   pathParams,
-				HeaderParams: FilterParameterDefinitionByType(allParams, "header"),
-				QueryParams:  FilterParameterDefinitionByType(allParams, "query"),
-				CookieParams: FilterParameterDefinitionByType(allParams, "cookie"),
-				OperationId:  ToCamelCase(op.OperationID),
-				// Replace newlines in summary.
-				Summary:         op.Summary,
-				Method:          opName,
-				Path:            requestPath,
-				Spec:            op,
-				Bodies:          bodyDefinitions,
-				TypeDefinitions: typeDefinitions,
-			}
+	bodyDefinitions, typeDefinitions, err := generateBodyDefinitions(op.OperationID, op.RequestBody, overrides)
+	if err != nil {
+		return OperationDefinition{}, errors.Wrap(err, "error generating body definitions")
+	}
 
-			// check for overrides of SecurityDefinitions.
-			// See: "Step 2. Applying security:" from the spec:
-			// https://swagger.io/docs/specification/authentication/
-			if op.Security != nil {
-				opDef.SecurityDefinitions = DescribeSecurityDefinition(*op.Security)
-			} else {
-				// use global securityDefinitions
-				// globalSecurityDefinitions contains the top-level securityDefinitions.
-				// They are the default securityPermissions which are injected into each
-				// path, except for the case where a path explicitly overrides them.
-				opDef.SecurityDefinitions = DescribeSecurityDefinition(swagger.Security)
+	responseHeaders, err := describeResponseHeaders(op.OperationID, op.Responses, overrides)
+	if err != nil {
+		return OperationDefinition{}, errors.Wrap(err, "error describing response headers")
+	}
 
-			}
+	opDef := OperationDefinition{
+		PathParams:   pathParams,
+		HeaderParams: FilterParameterDefinitionByType(allParams, "header"),
+		QueryParams:  FilterParameterDefinitionByType(allParams, "query"),
+		CookieParams: FilterParameterDefinitionByType(allParams, "cookie"),
+		OperationId:  ToCamelCase(op.OperationID),
+		// Replace newlines in summary.
+		Summary:         op.Summary,
+		Method:          opName,
+		Path:            requestPath,
+		Spec:            op,
+		Bodies:          bodyDefinitions,
+		ResponseHeaders: responseHeaders,
+		TypeDefinitions: typeDefinitions,
+
+		typeNameOverrides: overrides,
+	}
 
-			if op.RequestBody != nil {
-				opDef.BodyRequired = op.RequestBody.Value.Required
-			}
+	// check for overrides of SecurityDefinitions.
+	// See: "Step 2. Applying security:" from the spec:
+	// https://swagger.io/docs/specification/authentication/
+	if op.Security != nil {
+		opDef.SecurityDefinitions = DescribeSecurityDefinition(*op.Security)
+	} else {
+		// use global securityDefinitions
+		// globalSecurityDefinitions contains the top-level securityDefinitions.
+		// They are the default securityPermissions which are injected into each
+		// path, except for the case where a path explicitly overrides them.
+		opDef.SecurityDefinitions = DescribeSecurityDefinition(defaultSecurity)
+	}
 
-			// Generate all the type definitions needed for this operation
-			opDef.TypeDefinitions = append(opDef.TypeDefinitions, GenerateTypeDefsForOperation(opDef)...)
+	if op.RequestBody != nil {
+		opDef.BodyRequired = op.RequestBody.Value.Required
+	}
 
-			operations = append(operations, opDef)
+	opDef.Deprecated = op.Deprecated
+	if extension, ok := op.Extensions[extPropSunset]; ok {
+		opDef.Sunset, err = extSunset(extension)
+		if err != nil {
+			return OperationDefinition{}, errors.Wrapf(err, "invalid value for %q of %s", extPropSunset, op.OperationID)
 		}
 	}
-	return operations, nil
+
+	// Generate all the type definitions needed for this operation
+	opDef.TypeDefinitions = append(opDef.TypeDefinitions, GenerateTypeDefsForOperation(opDef)...)
+	return opDef, nil
 }
 
 func generateDefaultOperationID(opName string, requestPath string) (string, error) {
@@ -491,9 +761,23 @@ func generateDefaultOperationID(opName string, requestPath string) (string, erro
 	return ToCamelCase(operationId), nil
 }
 
+// requestBodyNameTags maps the content types of request bodies we generate
+// typed code for to the tag used in the names of their types and functions.
+var requestBodyNameTags = map[string]string{
+	"application/json":                  "JSON",
+	"application/x-www-form-urlencoded": "Formdata",
+	"text/plain":                        "Text",
+	"application/octet-stream":          "OctetStream",
+	"multipart/form-data":               "Multipart",
+}
+
 // This function turns the Swagger body definitions into a list of our body
 // definitions which will be used for code generation.
 func GenerateBodyDefinitions(operationID string, bodyOrRef *openapi3.RequestBodyRef) ([]RequestBodyDefinition, []TypeDefinition, error) {
+	return generateBodyDefinitions(operationID, bodyOrRef, nil)
+}
+
+func generateBodyDefinitions(operationID string, bodyOrRef *openapi3.RequestBodyRef, overrides typeNameOverrides) ([]RequestBodyDefinition, []TypeDefinition, error) {
 	if bodyOrRef == nil {
 		return nil, nil, nil
 	}
@@ -502,28 +786,40 @@ func GenerateBodyDefinitions(operationID string, bodyOrRef *openapi3.RequestBody
 	var bodyDefinitions []RequestBodyDefinition
 	var typeDefinitions []TypeDefinition
 
-	for contentType, content := range body.Content {
-		var tag string
-		var defaultBody bool
-
-		switch contentType {
-		case "application/json":
-			tag = "JSON"
-			defaultBody = true
-		default:
+	for _, contentType := range SortedContentKeys(body.Content) {
+		content := body.Content[contentType]
+		tag, found := requestBodyNameTags[contentType]
+		if !found {
 			continue
 		}
+		defaultBody := contentType == "application/json"
 
 		bodyTypeName := operationID + tag + "Body"
-		bodySchema, err := GenerateGoSchema(content.Schema, []string{bodyTypeName})
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "error generating request body definition")
+		var bodySchema Schema
+		switch contentType {
+		case "text/plain":
+			// Text is text, whatever the schema says about its format.
+			bodySchema = Schema{GoType: "string"}
+		case "application/octet-stream":
+			// Binary bodies are streamed, rather than buffered in memory.
+			bodySchema = Schema{GoType: "io.Reader"}
+		default:
+			schema := content.Schema
+			if contentType == "multipart/form-data" {
+				schema = multipartSchema(schema)
+			}
+			var err error
+			bodySchema, err = generateGoSchema(schema, []string{bodyTypeName}, overrides)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "error generating request body definition")
+			}
 		}
 
-		// If the body is a pre-defined type
-		if bodyOrRef.Ref != "" {
+		// If the body is a pre-defined type. We only generate types for the
+		// JSON content of request bodies under #/components.
+		if bodyOrRef.Ref != "" && defaultBody {
 			// Convert the reference path to Go type
-			refType, err := RefPathToGoType(bodyOrRef.Ref)
+			refType, err := overrides.refPathToGoType(bodyOrRef.Ref)
 			if err != nil {
 				return nil, nil, errors.Wrap(err, fmt.Sprintf("error turning reference (%s) into a Go type", bodyOrRef.Ref))
 			}
@@ -549,12 +845,72 @@ func GenerateBodyDefinitions(operationID string, bodyOrRef *openapi3.RequestBody
 			NameTag:     tag,
 			ContentType: contentType,
 			Default:     defaultBody,
+			Encodings:   describeBodyEncodings(content.Encoding),
 		}
 		bodyDefinitions = append(bodyDefinitions, bd)
 	}
 	return bodyDefinitions, typeDefinitions, nil
 }
 
+// multipartSchema returns the schema of a multipart/form-data body, with the
+// binary strings it describes inline turned into openapi_types.File, which
+// are sent and received as file parts. A referenced body schema describing
+// binary properties is inlined, so that the body gets a type of its own,
+// while other referenced schemas keep their component types, in which
+// binary strings are strings, as they are everywhere else.
+func multipartSchema(sref *openapi3.SchemaRef) *openapi3.SchemaRef {
+	if sref == nil || sref.Value == nil {
+		return sref
+	}
+	schema := *sref.Value
+	if schema.Type == "string" && schema.Format == "binary" {
+		schema.Extensions = map[string]interface{}{extPropGoType: json.RawMessage(`"openapi_types.File"`)}
+		return &openapi3.SchemaRef{Value: &schema}
+	}
+	if schema.Type == "array" && schema.Items != nil && schema.Items.Ref == "" {
+		items := multipartSchema(schema.Items)
+		if items == schema.Items {
+			return sref
+		}
+		schema.Items = items
+		return &openapi3.SchemaRef{Value: &schema}
+	}
+
+	properties := make(map[string]*openapi3.SchemaRef, len(schema.Properties))
+	changed := false
+	for name, property := range schema.Properties {
+		properties[name] = property
+		if property.Ref == "" {
+			properties[name] = multipartSchema(property)
+			changed = changed || properties[name] != property
+		}
+	}
+	if !changed {
+		return sref
+	}
+	schema.Properties = properties
+	return &openapi3.SchemaRef{Value: &schema}
+}
+
+// describeBodyEncodings turns the encoding objects of a media type into a
+// list of RequestBodyEncoding, sorted by property name.
+func describeBodyEncodings(encodings map[string]*openapi3.Encoding) []RequestBodyEncoding {
+	var result []RequestBodyEncoding
+	for _, name := range SortedEncodingKeys(encodings) {
+		encoding := encodings[name]
+		headers := make(map[string]bool)
+		for headerName, header := range encoding.Headers {
+			headers[headerName] = header.Value != nil && header.Value.Required
+		}
+		result = append(result, RequestBodyEncoding{
+			PropertyName: name,
+			ContentType:  encoding.ContentType,
+			Headers:      headers,
+		})
+	}
+	return result
+}
+
 func GenerateTypeDefsForOperation(op OperationDefinition) []TypeDefinition {
 	var typeDefs []TypeDefinition
 	// Start with the params object itself
@@ -628,6 +984,11 @@ func GenerateTypesForOperations(t *template.Template, ops []OperationDefinition)
 		return "", errors.Wrap(err, "error generating request bodies for operations")
 	}
 
+	err = t.ExecuteTemplate(w, "response-headers.tmpl", ops)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating response headers for operations")
+	}
+
 	// Generate boiler plate for all additional types.
 	var td []TypeDefinition
 	for _, op := range ops {
@@ -686,6 +1047,36 @@ func GenerateChiServer(t *template.Template, operations []OperationDefinition) (
 	return buf.String(), nil
 }
 
+// GenerateStdHTTPServer generates the ServerInterface, the wrapper functions
+// around our handlers, and a handler which routes requests to them using
+// only net/http. It shares the interface and the wrappers with the chi server.
+func GenerateStdHTTPServer(t *template.Template, operations []OperationDefinition) (string, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	err := t.ExecuteTemplate(w, "chi-interface.tmpl", operations)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating server interface")
+	}
+
+	err = t.ExecuteTemplate(w, "chi-middleware.tmpl", operations)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating server middleware")
+	}
+
+	err = t.ExecuteTemplate(w, "std-http-handler.tmpl", operations)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating server http handler")
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return "", errors.Wrap(err, "error flushing output buffer for server")
+	}
+
+	return buf.String(), nil
+}
+
 // GenerateEchoServer This function generates all the go code for the ServerInterface as well as
 // all the wrapper functions around our handlers.
 func GenerateEchoServer(t *template.Template, operations []OperationDefinition) (string, error) {
@@ -78,4 +78,20 @@ func TestGenerateDefaultOperationID(t *testing.T) {
 			t.Fatalf("Operation ID generation error. Want [%v] Got [%v]", test.want, got)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestParameterGoVariableName(t *testing.T) {
+	for name, want := range map[string]string{
+		"id":    "id",
+		"type":  "pType",
+		"1st":   "n1st",
+		"$":     "",
+		"":      "",
+		"x-req": "xReq",
+	} {
+		pd := ParameterDefinition{ParamName: name}
+		if got := pd.GoVariableName(); got != want {
+			t.Errorf("GoVariableName of %q: want [%v] got [%v]", name, want, got)
+		}
+	}
+}
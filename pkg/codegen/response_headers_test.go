@@ -0,0 +1,104 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const responseHeadersOpenAPIDefinition = `
+openapi: 3.0.1
+info:
+  title: Response headers
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: findPets
+      responses:
+        default:
+          description: Error
+          headers:
+            Retry-After:
+              schema:
+                type: string
+                format: date-time
+        2XX:
+          description: Success
+          headers:
+            ETag:
+              schema:
+                type: string
+        200:
+          description: Pets
+          headers:
+            X-Rate-Limit-Remaining:
+              description: The number of requests left
+              required: true
+              schema:
+                type: integer
+            ETag:
+              schema:
+                type: string
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+        404:
+          description: Not found
+`
+
+func TestDescribeResponseHeaders(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(responseHeadersOpenAPIDefinition))
+	require.NoError(t, err)
+
+	headers, err := DescribeResponseHeaders("FindPets", swagger.Paths["/pets"].Get.Responses)
+	require.NoError(t, err)
+
+	// Specific status codes come first, and responses without headers are
+	// left out.
+	require.Len(t, headers, 3)
+	assert.Equal(t, "FindPets200ResponseHeaders", headers[0].TypeName)
+	assert.Equal(t, "Headers200", headers[0].FieldName())
+	assert.Equal(t, "rsp.StatusCode == 200", headers[0].StatusCondition())
+	assert.Equal(t, "rsp.StatusCode / 100 == 2", headers[1].StatusCondition())
+	assert.Equal(t, "HeadersDefault", headers[2].FieldName())
+	assert.Equal(t, "true", headers[2].StatusCondition())
+
+	require.Len(t, headers[0].Headers, 2)
+	assert.Equal(t, "ETag", headers[0].Headers[0].GoName())
+	assert.Equal(t, "*string", headers[0].Headers[0].GoTypeDef())
+	assert.Equal(t, "XRateLimitRemaining", headers[0].Headers[1].GoName())
+	assert.Equal(t, "int", headers[0].Headers[1].GoTypeDef())
+	assert.Equal(t, "*time.Time", headers[2].Headers[0].GoTypeDef())
+}
+
+func TestGenerateResponseHeaders(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(responseHeadersOpenAPIDefinition))
+	require.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true})
+	require.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "type FindPets200ResponseHeaders struct {")
+	assert.Contains(t, code, "Headers200     *FindPets200ResponseHeaders")
+	assert.Contains(t, code, "HeadersDefault *FindPetsDefaultResponseHeaders")
+	assert.Contains(t, code, `runtime.BindStyledParameter("simple", false, "X-Rate-Limit-Remaining", strings.Join(valueList, ","), &value)`)
+	assert.Contains(t, code, `return nil, fmt.Errorf("invalid format for response header X-Rate-Limit-Remaining: %s", err)`)
+	assert.Contains(t, code, "headers.XRateLimitRemaining = value")
+	assert.Contains(t, code, "headers.ETag = &value")
+
+	// A missing required header fails the parse, like a malformed one does,
+	// while optional headers are left nil.
+	assert.Contains(t, code, `return nil, fmt.Errorf("required response header X-Rate-Limit-Remaining is missing")`)
+	assert.NotContains(t, code, "required response header ETag")
+	assert.NotContains(t, code, "required response header Retry-After")
+}
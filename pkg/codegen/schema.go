@@ -66,14 +66,43 @@ func (p Property) GoFieldName() string {
 	return SchemaNameToTypeName(p.JsonFieldName)
 }
 
+// Returns whether the property is stored by pointer, since it's optional or
+// nullable.
+func (p Property) IndirectOptional() bool {
+	return !p.Schema.SkipOptionalPointer && (!p.Required || p.Nullable)
+}
+
 func (p Property) GoTypeDef() string {
 	typeDef := p.Schema.TypeDecl()
-	if !p.Schema.SkipOptionalPointer && (!p.Required || p.Nullable) {
+	if p.IndirectOptional() {
 		typeDef = "*" + typeDef
 	}
 	return typeDef
 }
 
+// Returns a name for a Go variable holding this property's value, which
+// isn't a Go keyword.
+func (p Property) GoVariableName() string {
+	name := LowercaseFirstCharacter(p.GoFieldName())
+	if IsGoKeyword(name) || IsPredeclaredGoIdentifier(name) {
+		name = "p" + UppercaseFirstCharacter(name)
+	}
+	return name
+}
+
+// builderReservedNames are the identifiers declared by the params
+// constructors of the builders target, besides their arguments.
+var builderReservedNames = map[string]bool{"params": true, "opts": true}
+
+// The name of the argument of a params constructor, which sets the property.
+func (p Property) GoBuilderArgName() string {
+	name := p.GoVariableName()
+	if builderReservedNames[name] {
+		name = "p" + UppercaseFirstCharacter(name)
+	}
+	return name
+}
+
 type TypeDefinition struct {
 	TypeName     string
 	JsonName     string
@@ -86,6 +115,12 @@ func PropertiesEqual(a, b Property) bool {
 }
 
 func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
+	return generateGoSchema(sref, path, nil)
+}
+
+// generateGoSchema is GenerateGoSchema, naming referenced types with the
+// given overrides.
+func generateGoSchema(sref *openapi3.SchemaRef, path []string, overrides typeNameOverrides) (Schema, error) {
 	// If Ref is set on the SchemaRef, it means that this type is actually a reference to
 	// another type. We're not de-referencing, so simply use the referenced type.
 	var refType string
@@ -102,7 +137,7 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 	if sref.Ref != "" {
 		var err error
 		// Convert the reference path to Go type
-		refType, err = RefPathToGoType(sref.Ref)
+		refType, err = overrides.refPathToGoType(sref.Ref)
 		if err != nil {
 			return Schema{}, fmt.Errorf("error turning reference (%s) into a Go type: %s",
 				sref.Ref, err)
@@ -126,7 +161,7 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 	// so that in a RESTful paradigm, the Create operation can return
 	// (object, id), so that other operations can refer to (id)
 	if schema.AllOf != nil {
-		mergedSchema, err := MergeSchemas(schema.AllOf, path)
+		mergedSchema, err := mergeSchemas(schema.AllOf, path, overrides)
 		if err != nil {
 			return Schema{}, errors.Wrap(err, "error merging schemas")
 		}
@@ -172,7 +207,7 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 			for _, pName := range SortedSchemaKeys(schema.Properties) {
 				p := schema.Properties[pName]
 				propertyPath := append(path, pName)
-				pSchema, err := GenerateGoSchema(p, propertyPath)
+				pSchema, err := generateGoSchema(p, propertyPath, overrides)
 				if err != nil {
 					return Schema{}, errors.Wrap(err, fmt.Sprintf("error generating Go schema for property '%s'", pName))
 				}
@@ -214,7 +249,7 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 				GoType: "interface{}",
 			}
 			if schema.AdditionalProperties != nil {
-				additionalSchema, err := GenerateGoSchema(schema.AdditionalProperties, path)
+				additionalSchema, err := generateGoSchema(schema.AdditionalProperties, path, overrides)
 				if err != nil {
 					return Schema{}, errors.Wrap(err, "error generating type for additional properties")
 				}
@@ -231,7 +266,7 @@ func GenerateGoSchema(sref *openapi3.SchemaRef, path []string) (Schema, error) {
 		case "array":
 			// For arrays, we'll get the type of the Items and throw a
 			// [] in front of it.
-			arrayType, err := GenerateGoSchema(schema.Items, path)
+			arrayType, err := generateGoSchema(schema.Items, path, overrides)
 			if err != nil {
 				return Schema{}, errors.Wrap(err, "error generating type for array")
 			}
@@ -357,6 +392,10 @@ func GenStructFromSchema(schema Schema) string {
 
 // Merge all the fields in the schemas supplied into one giant schema.
 func MergeSchemas(allOf []*openapi3.SchemaRef, path []string) (Schema, error) {
+	return mergeSchemas(allOf, path, nil)
+}
+
+func mergeSchemas(allOf []*openapi3.SchemaRef, path []string, overrides typeNameOverrides) (Schema, error) {
 	var outSchema Schema
 	for _, schemaOrRef := range allOf {
 		ref := schemaOrRef.Ref
@@ -364,13 +403,13 @@ func MergeSchemas(allOf []*openapi3.SchemaRef, path []string) (Schema, error) {
 		var refType string
 		var err error
 		if ref != "" {
-			refType, err = RefPathToGoType(ref)
+			refType, err = overrides.refPathToGoType(ref)
 			if err != nil {
 				return Schema{}, errors.Wrap(err, "error converting reference path to a go type")
 			}
 		}
 
-		schema, err := GenerateGoSchema(schemaOrRef, path)
+		schema, err := generateGoSchema(schemaOrRef, path, overrides)
 		if err != nil {
 			return Schema{}, errors.Wrap(err, "error generating Go schema in allOf")
 		}
@@ -401,7 +440,7 @@ func MergeSchemas(allOf []*openapi3.SchemaRef, path []string) (Schema, error) {
 
 	// Now, we generate the struct which merges together all the fields.
 	var err error
-	outSchema.GoType, err = GenStructFromAllOf(allOf, path)
+	outSchema.GoType, err = genStructFromAllOf(allOf, path, overrides)
 	if err != nil {
 		return Schema{}, errors.Wrap(err, "unable to generate aggregate type for AllOf")
 	}
@@ -412,6 +451,10 @@ func MergeSchemas(allOf []*openapi3.SchemaRef, path []string) (Schema, error) {
 // input array. In the case of Ref objects, we use an embedded struct, otherwise,
 // we inline the fields.
 func GenStructFromAllOf(allOf []*openapi3.SchemaRef, path []string) (string, error) {
+	return genStructFromAllOf(allOf, path, nil)
+}
+
+func genStructFromAllOf(allOf []*openapi3.SchemaRef, path []string, overrides typeNameOverrides) (string, error) {
 	// Start out with struct {
 	objectParts := []string{"struct {"}
 	for _, schemaOrRef := range allOf {
@@ -423,7 +466,7 @@ func GenStructFromAllOf(allOf []*openapi3.SchemaRef, path []string) (string, err
 			//   InlinedMember
 			//   ...
 			// }
-			goType, err := RefPathToGoType(ref)
+			goType, err := overrides.refPathToGoType(ref)
 			if err != nil {
 				return "", err
 			}
@@ -434,7 +477,7 @@ func GenStructFromAllOf(allOf []*openapi3.SchemaRef, path []string) (string, err
 		} else {
 			// Inline all the fields from the schema into the output struct,
 			// just like in the simple case of generating an object.
-			goSchema, err := GenerateGoSchema(schemaOrRef, path)
+			goSchema, err := generateGoSchema(schemaOrRef, path, overrides)
 			if err != nil {
 				return "", err
 			}
@@ -460,14 +503,14 @@ func GenStructFromAllOf(allOf []*openapi3.SchemaRef, path []string) (string, err
 
 // This constructs a Go type for a parameter, looking at either the schema or
 // the content, whichever is available
-func paramToGoType(param *openapi3.Parameter, path []string) (Schema, error) {
+func paramToGoType(param *openapi3.Parameter, path []string, overrides typeNameOverrides) (Schema, error) {
 	if param.Content == nil && param.Schema == nil {
 		return Schema{}, fmt.Errorf("parameter '%s' has no schema or content", param.Name)
 	}
 
 	// We can process the schema through the generic schema processor
 	if param.Schema != nil {
-		return GenerateGoSchema(param.Schema, path)
+		return generateGoSchema(param.Schema, path, overrides)
 	}
 
 	// At this point, we have a content type. We know how to deal with
@@ -489,5 +532,5 @@ func paramToGoType(param *openapi3.Parameter, path []string) (Schema, error) {
 	}
 
 	// For json, we go through the standard schema mechanism
-	return GenerateGoSchema(mt.Schema, path)
+	return generateGoSchema(mt.Schema, path, overrides)
 }
@@ -0,0 +1,293 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package codegen
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pkg/errors"
+)
+
+// This describes one of the servers in the servers section of the spec.
+type ServerDefinition struct {
+	// The name of the server, which the generated identifiers are derived
+	// from, eg, Production for ServerURLProduction
+	Name string
+
+	// The URL of the server, which may contain variables, eg,
+	// https://{region}.example.com/v1
+	URL string
+
+	Description string
+
+	// The variables in the URL, in the order in which they first appear
+	Variables []ServerVariableDefinition
+}
+
+// This describes a variable in the URL of a server.
+type ServerVariableDefinition struct {
+	// The name of the variable in the URL
+	Name string
+
+	// The Go type of the variable, which is an enum type for variables with
+	// enumerated values, and string otherwise
+	TypeName string
+
+	// The enumerated values of the variable, keyed by the suffix of the
+	// constant declared for them
+	EnumValues map[string]string
+
+	Default     string
+	Description string
+}
+
+// GoVariableName is the name of the argument holding the variable.
+func (v ServerVariableDefinition) GoVariableName() string {
+	name := LowercaseFirstCharacter(ToCamelCase(v.Name))
+	if IsGoKeyword(name) {
+		name = "p" + UppercaseFirstCharacter(name)
+	}
+	if name != "" && unicode.IsNumber([]rune(name)[0]) {
+		name = "n" + name
+	}
+	return name
+}
+
+// IsEnum returns whether the variable has enumerated values, and so a type
+// of its own.
+func (v ServerVariableDefinition) IsEnum() bool {
+	return len(v.EnumValues) > 0
+}
+
+// EnumConstants returns the names of the constants declared for the
+// enumerated values of the variable, sorted.
+func (v ServerVariableDefinition) EnumConstants() []string {
+	var names []string
+	for _, key := range SortedStringKeys(v.EnumValues) {
+		names = append(names, v.TypeName+"_"+key)
+	}
+	return names
+}
+
+// DefaultURL returns the URL of the server with each variable set to its
+// default value.
+func (s ServerDefinition) DefaultURL() string {
+	return expandServerURL(s.URL, func(name string) string {
+		for _, v := range s.Variables {
+			if v.Name == name {
+				return v.Default
+			}
+		}
+		return ""
+	})
+}
+
+// URLFormatString returns the URL of the server as a format string for
+// fmt.Sprintf, with a %s for each occurrence of a variable.
+func (s ServerDefinition) URLFormatString() string {
+	escaped := strings.Replace(s.URL, "%", "%%", -1)
+	return expandServerURL(escaped, func(string) string {
+		return "%s"
+	})
+}
+
+// URLFormatArgs returns the arguments to URLFormatString, which are the Go
+// variables holding each occurrence of a variable in the URL.
+func (s ServerDefinition) URLFormatArgs() []string {
+	var args []string
+	for _, name := range serverURLVariables(s.URL) {
+		for _, v := range s.Variables {
+			if v.Name == name {
+				arg := v.GoVariableName()
+				if v.IsEnum() {
+					arg = "string(" + arg + ")"
+				}
+				args = append(args, arg)
+			}
+		}
+	}
+	return args
+}
+
+// serverURLVariables returns the names of the variables in a server URL, in
+// order of appearance.
+func serverURLVariables(serverURL string) []string {
+	var names []string
+	for {
+		start := strings.Index(serverURL, "{")
+		if start == -1 {
+			return names
+		}
+		end := strings.Index(serverURL[start:], "}")
+		if end == -1 {
+			return names
+		}
+		names = append(names, serverURL[start+1:start+end])
+		serverURL = serverURL[start+end+1:]
+	}
+}
+
+// expandServerURL replaces each variable in a server URL with the value
+// returned for its name.
+func expandServerURL(serverURL string, value func(string) string) string {
+	var buf strings.Builder
+	for {
+		start := strings.Index(serverURL, "{")
+		end := strings.Index(serverURL, "}")
+		if start == -1 || end < start {
+			buf.WriteString(serverURL)
+			return buf.String()
+		}
+		buf.WriteString(serverURL[:start])
+		buf.WriteString(value(serverURL[start+1 : end]))
+		serverURL = serverURL[end+1:]
+	}
+}
+
+// DescribeServers returns the definitions of the servers of a spec. Servers
+// are named after their description, or their position when they don't have
+// one.
+func DescribeServers(servers openapi3.Servers) []ServerDefinition {
+	var result []ServerDefinition
+	names := make(map[string]bool)
+	for i, server := range servers {
+		if server == nil {
+			continue
+		}
+		name := ToCamelCase(server.Description)
+		if name == "" {
+			name = strconv.Itoa(i + 1)
+		}
+		base := name
+		for suffix := 2; names[name]; suffix++ {
+			name = base + strconv.Itoa(suffix)
+		}
+		names[name] = true
+
+		def := ServerDefinition{
+			Name:        name,
+			URL:         server.URL,
+			Description: server.Description,
+		}
+		seen := make(map[string]bool)
+		for _, varName := range serverURLVariables(server.URL) {
+			if seen[varName] {
+				continue
+			}
+			seen[varName] = true
+
+			variable := ServerVariableDefinition{
+				Name:     varName,
+				TypeName: "string",
+			}
+			if spec := server.Variables[varName]; spec != nil {
+				variable.Description = spec.Description
+				if spec.Default != nil {
+					variable.Default = fmt.Sprint(spec.Default)
+				}
+				if len(spec.Enum) > 0 {
+					values := make([]string, len(spec.Enum))
+					for j, value := range spec.Enum {
+						values[j] = fmt.Sprint(value)
+					}
+					variable.TypeName = "ServerURL" + name + ToCamelCase(varName) + "Variable"
+					variable.EnumValues = SanitizeEnumNames(values)
+				}
+			}
+			def.Variables = append(def.Variables, variable)
+		}
+		result = append(result, def)
+	}
+	return result
+}
+
+// serverBasePath returns the path of the first server of the spec, with
+// variables set to their defaults and without a trailing slash, which is
+// where the servers serve the API by default.
+func serverBasePath(swagger *openapi3.Swagger) string {
+	servers := DescribeServers(swagger.Servers)
+	if len(servers) == 0 {
+		return ""
+	}
+	serverURL, err := url.Parse(servers[0].DefaultURL())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(serverURL.Path, "/")
+}
+
+// GenerateServerURLs generates the constants holding the default URL of each
+// server of the spec, and the functions building the URLs of servers with
+// variables.
+func GenerateServerURLs(t *template.Template, servers []ServerDefinition) (string, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	err := t.ExecuteTemplate(w, "server-urls.tmpl", servers)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating server URLs")
+	}
+	err = w.Flush()
+	if err != nil {
+		return "", errors.Wrap(err, "error flushing output buffer for server URLs")
+	}
+	return buf.String(), nil
+}
+
+// GenerateServerBasePath generates the constant holding the path which the
+// echo, chi and net/http servers are registered under by default.
+func GenerateServerBasePath(t *template.Template, basePath string) (string, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	err := t.ExecuteTemplate(w, "server-base-path.tmpl", basePath)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating server base path")
+	}
+	err = w.Flush()
+	if err != nil {
+		return "", errors.Wrap(err, "error flushing output buffer for server base path")
+	}
+	return buf.String(), nil
+}
+
+// GenerateBadRequest generates the function which the wrappers of the echo
+// server, when echo is true, or of the chi and net/http servers call when a
+// parameter or body can't be bound. With problemDetails, it responds with
+// RFC 7807 problem details rather than plain text.
+func GenerateBadRequest(t *template.Template, echo bool, problemDetails bool) (string, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	err := t.ExecuteTemplate(w, "bad-request.tmpl", struct {
+		Echo           bool
+		ProblemDetails bool
+	}{echo, problemDetails})
+	if err != nil {
+		return "", errors.Wrap(err, "error generating bad request handling")
+	}
+	err = w.Flush()
+	if err != nil {
+		return "", errors.Wrap(err, "error flushing output buffer for bad request handling")
+	}
+	return buf.String(), nil
+}
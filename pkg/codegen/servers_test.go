@@ -0,0 +1,148 @@
+package codegen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const serversOpenAPIDefinition = `
+openapi: 3.0.1
+info:
+  title: Servers
+  version: 1.0.0
+servers:
+- url: https://{region}.example.com:{port}/{basePath}
+  description: Production
+  variables:
+    region:
+      default: us-east
+      enum: [us-east, eu-west]
+      description: The region of the deployment
+    port:
+      default: '443'
+      enum: ['443', '8443']
+    basePath:
+      default: v1
+- url: http://localhost:8080/v1
+  description: Local development
+- url: /v1
+paths:
+  /pets:
+    get:
+      operationId: findPets
+      responses:
+        204:
+          description: None
+`
+
+func TestDescribeServers(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(serversOpenAPIDefinition))
+	require.NoError(t, err)
+
+	servers := DescribeServers(swagger.Servers)
+	require.Len(t, servers, 3)
+
+	production := servers[0]
+	assert.Equal(t, "Production", production.Name)
+	assert.Equal(t, "https://us-east.example.com:443/v1", production.DefaultURL())
+	assert.Equal(t, "https://%s.example.com:%s/%s", production.URLFormatString())
+	assert.Equal(t, []string{"string(region)", "string(port)", "basePath"}, production.URLFormatArgs())
+	require.Len(t, production.Variables, 3)
+	assert.Equal(t, "ServerURLProductionRegionVariable", production.Variables[0].TypeName)
+	assert.Equal(t, []string{
+		"ServerURLProductionRegionVariable_eu_west",
+		"ServerURLProductionRegionVariable_us_east",
+	}, production.Variables[0].EnumConstants())
+	assert.Equal(t, "string", production.Variables[2].TypeName)
+	assert.False(t, production.Variables[2].IsEnum())
+
+	// Servers without a description are named after their position.
+	assert.Equal(t, "LocalDevelopment", servers[1].Name)
+	assert.Empty(t, servers[1].Variables)
+	assert.Equal(t, "3", servers[2].Name)
+
+	assert.Equal(t, "/v1", serverBasePath(swagger))
+	assert.Equal(t, "", serverBasePath(&openapi3.Swagger{}))
+}
+
+func TestGenerateServerURLs(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(serversOpenAPIDefinition))
+	require.NoError(t, err)
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateClient: true, GenerateEchoServer: true})
+	require.NoError(t, err)
+
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, `const ServerURLProduction = "https://us-east.example.com:443/v1"`)
+	assert.Contains(t, code, `const ServerURLLocalDevelopment = "http://localhost:8080/v1"`)
+	assert.Contains(t, code, `const ServerURL3 = "/v1"`)
+	assert.Contains(t, code, "type ServerURLProductionRegionVariable string")
+	assert.Contains(t, code, `ServerURLProductionRegionVariable_eu_west ServerURLProductionRegionVariable = "eu-west"`)
+	assert.Contains(t, code, "func NewServerURLProduction(region ServerURLProductionRegionVariable, port ServerURLProductionPortVariable, basePath string) (string, error) {")
+	assert.NotContains(t, code, "func NewServerURLLocalDevelopment(")
+
+	// The servers are registered under the path of the first server.
+	assert.Contains(t, code, `const ServerBasePath = "/v1"`)
+	assert.Contains(t, code, "RegisterHandlersWithBaseURL(router, si, ServerBasePath)")
+
+	// Types and servers can go into separate files of a package, so the base
+	// path comes with the server.
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true})
+	require.NoError(t, err)
+	assert.Contains(t, code, "ServerURLProduction")
+	assert.NotContains(t, code, "ServerBasePath")
+
+	// Schemas named like the server identifiers are renamed.
+	swagger.Components.Schemas = map[string]*openapi3.SchemaRef{
+		"ServerURLProduction": openapi3.NewStringSchema().NewRef(),
+	}
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, SkipPrune: true})
+	require.NoError(t, err)
+	assert.Contains(t, code, "type ServerURLProductionSchema string")
+}
+
+func TestProblemDetails(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testOpenAPIDefinition))
+	require.NoError(t, err)
+
+	// Binding errors are plain text by default.
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateEchoServer: true})
+	require.NoError(t, err)
+	_, err = format.Source([]byte(code))
+	assert.NoError(t, err)
+	assert.Contains(t, code, `return badRequest(ctx, "query", "$top", fmt.Sprintf("Invalid format for parameter $top: %s", err))`)
+	assert.Contains(t, code, "func badRequest(ctx echo.Context, location, name, reason string) error {\n\treturn echo.NewHTTPError(http.StatusBadRequest, reason)")
+
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, GenerateChiServer: true})
+	require.NoError(t, err)
+	assert.Contains(t, code, `badRequest(w, r, "query", "$top", fmt.Sprintf("Invalid format for parameter $top: %s", err))`)
+	assert.Contains(t, code, "http.Error(w, reason, http.StatusBadRequest)")
+
+	// With ProblemDetails, they're RFC 7807 problem details.
+	for _, opts := range []Options{
+		{GenerateTypes: true, GenerateEchoServer: true, ProblemDetails: true},
+		{GenerateTypes: true, GenerateChiServer: true, ProblemDetails: true},
+		{GenerateTypes: true, GenerateStdHTTPServer: true, ProblemDetails: true},
+	} {
+		code, err := Generate(swagger, "api", opts)
+		require.NoError(t, err)
+		_, err = format.Source([]byte(code))
+		assert.NoError(t, err)
+		assert.Contains(t, code, "runtime.NewValidationProblem(http.StatusBadRequest, runtime.ValidationFailure{")
+		assert.NotContains(t, code, "http.Error(w, reason")
+		// Echo servers leave the problem to the error handler, rather than
+		// writing it themselves.
+		if opts.GenerateEchoServer {
+			assert.Contains(t, code, "return &echo.HTTPError{Code: problem.Status, Message: problem, Internal: problem}")
+			assert.NotContains(t, code, "runtime.WriteProblem(")
+		} else {
+			assert.Contains(t, code, "runtime.WriteProblem(")
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package codegen
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// This describes one of the responses which a strict server handler can
+// return for an operation, for a given status and content type.
+type StrictResponseDefinition struct {
+	// The name of the field holding the response in the response object, eg,
+	// JSON200, or NO204 for a response without content
+	FieldName string
+
+	// The name of the response in the spec, eg, 200, 2XX or default
+	ResponseName string
+
+	// The content type of the response, which is empty when there's no
+	// content
+	ContentType string
+
+	// The schema of the content
+	Schema Schema
+
+	// The headers declared for the response, if any
+	Headers *ResponseHeadersDefinition
+}
+
+// Returns whether the response has any content.
+func (r StrictResponseDefinition) HasContent() bool {
+	return r.ContentType != ""
+}
+
+// Returns whether the content is of a type which we don't marshal, such as
+// text/csv or image/png, and is written from a reader as it is.
+func (r StrictResponseDefinition) IsRaw() bool {
+	return r.HasContent() &&
+		!StringInArray(r.ContentType, contentTypesJSON) &&
+		!StringInArray(r.ContentType, contentTypesYAML) &&
+		!StringInArray(r.ContentType, contentTypesXML)
+}
+
+// The type of the field holding the response in the response object: a
+// pointer to the content, a reader for raw content, or a bool for a response
+// without content.
+func (r StrictResponseDefinition) TypeDecl() string {
+	switch {
+	case !r.HasContent():
+		return "bool"
+	case r.IsRaw():
+		return "io.Reader"
+	default:
+		return "*" + r.Schema.TypeDecl()
+	}
+}
+
+// Returns whether the Content-Type header can be set from the content type
+// of the response, which isn't the case for media ranges such as image/*.
+func (r StrictResponseDefinition) HasFixedContentType() bool {
+	return !strings.Contains(r.ContentType, "*")
+}
+
+// Returns the name of the package used to marshal the content: json, yaml
+// or xml.
+func (r StrictResponseDefinition) Marshaler() string {
+	switch {
+	case StringInArray(r.ContentType, contentTypesYAML):
+		return "yaml"
+	case StringInArray(r.ContentType, contentTypesXML):
+		return "xml"
+	default:
+		return "json"
+	}
+}
+
+// Returns whether the response is declared for a single status code, rather
+// than a range such as 2XX or default.
+func (r StrictResponseDefinition) IsFixedStatus() bool {
+	_, err := strconv.Atoi(r.ResponseName)
+	return err == nil
+}
+
+// The status code written for the response, unless the response object
+// overrides it. Ranges are written with their first code, eg, 200 for 2XX,
+// and default responses with 500.
+func (r StrictResponseDefinition) StatusCode() int {
+	if code, err := strconv.Atoi(r.ResponseName); err == nil {
+		return code
+	}
+	if len(r.ResponseName) == 3 && r.ResponseName[1:] == "XX" {
+		code, _ := strconv.Atoi(r.ResponseName[:1])
+		return code * 100
+	}
+	return 500
+}
+
+// Returns the responses which strict server handlers of this operation can
+// return. Content types which we don't know how to marshal are written as
+// they are, from a reader.
+func (o *OperationDefinition) StrictResponses() ([]StrictResponseDefinition, error) {
+	var result []StrictResponseDefinition
+	fieldNames := make(map[string]bool)
+
+	headers := make(map[string]*ResponseHeadersDefinition)
+	for i, rh := range o.ResponseHeaders {
+		headers[rh.ResponseName] = &o.ResponseHeaders[i]
+	}
+
+	responses := o.Spec.Responses
+	for _, responseName := range SortedResponsesKeys(responses) {
+		response := responses[responseName].Value
+		if response == nil {
+			continue
+		}
+		if len(response.Content) == 0 {
+			result = append(result, StrictResponseDefinition{
+				FieldName:    "NO" + ToCamelCase(responseName),
+				ResponseName: responseName,
+				Headers:      headers[responseName],
+			})
+			continue
+		}
+		for _, contentTypeName := range SortedContentKeys(response.Content) {
+			content := response.Content[contentTypeName]
+
+			var prefix string
+			raw := false
+			switch {
+			case StringInArray(contentTypeName, contentTypesJSON):
+				prefix = "JSON"
+			case StringInArray(contentTypeName, contentTypesYAML):
+				prefix = "YAML"
+			case StringInArray(contentTypeName, contentTypesXML):
+				prefix = "XML"
+			default:
+				prefix = rawResponsePrefix(contentTypeName)
+				raw = true
+			}
+			// Equivalent content types, such as application/yaml and
+			// text/yaml, share a field, which is sent as the first of them.
+			fieldName := prefix + ToCamelCase(responseName)
+			if fieldNames[fieldName] || (!raw && content.Schema == nil) {
+				continue
+			}
+			fieldNames[fieldName] = true
+
+			definition := StrictResponseDefinition{
+				FieldName:    fieldName,
+				ResponseName: responseName,
+				ContentType:  contentTypeName,
+				Headers:      headers[responseName],
+			}
+			if !raw {
+				schema, err := generateGoSchema(content.Schema, []string{responseName}, o.typeNameOverrides)
+				if err != nil {
+					return nil, errors.Wrap(err, fmt.Sprintf("Unable to determine Go type for %s.%s", o.OperationId, contentTypeName))
+				}
+				if content.Schema.Ref != "" {
+					refType, err := o.typeNameOverrides.refPathToGoType(content.Schema.Ref)
+					if err != nil {
+						return nil, errors.Wrap(err, "error dereferencing response Ref")
+					}
+					schema.RefType = refType
+				}
+				definition.Schema = schema
+			}
+			result = append(result, definition)
+		}
+	}
+	return result, nil
+}
+
+// rawResponsePrefix returns the prefix of the fields of responses of a content
+// type we don't marshal, which is the tag of the request bodies of the type
+// where there's one, eg, Text for text/plain, or else the camel cased content
+// type, eg, TextCsv for text/csv.
+func rawResponsePrefix(contentType string) string {
+	if tag, found := requestBodyNameTags[contentType]; found {
+		return tag
+	}
+	return ToCamelCase(strings.Replace(contentType, "/", "-", -1))
+}
+
+// Returns whether any of the responses of the operation is declared for a
+// range of status codes or as default, in which case handlers can choose the
+// status code to send.
+func (o *OperationDefinition) HasVariableStatusResponses() bool {
+	for responseName := range o.Spec.Responses {
+		if _, err := strconv.Atoi(responseName); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateStrictServer generates the StrictServerInterface, the request and
+// response objects of its handlers, and the adapter which turns it into the
+// ServerInterface of the echo, chi or net/http server. The latter two share
+// their ServerInterface, and so the adapter.
+func GenerateStrictServer(t *template.Template, ops []OperationDefinition, opts Options) (string, error) {
+	var adapter string
+	switch {
+	case opts.GenerateEchoServer:
+		adapter = "strict-echo.tmpl"
+	case opts.GenerateChiServer, opts.GenerateStdHTTPServer:
+		adapter = "strict-chi.tmpl"
+	default:
+		return "", errors.New("the strict server requires the echo, chi or net/http server to be generated")
+	}
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	err := t.ExecuteTemplate(w, "strict-interface.tmpl", ops)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating strict server interface")
+	}
+
+	err = t.ExecuteTemplate(w, adapter, ops)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating strict server adapter")
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return "", errors.Wrap(err, "error flushing output buffer for strict server")
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,208 @@
+package codegen
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const strictServerOpenAPIDefinition = `
+openapi: 3.0.1
+info:
+  title: Strict server
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: findPets
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+      responses:
+        200:
+          description: Pets
+          headers:
+            X-Count:
+              required: true
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+            application/yaml:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+            text/csv:
+              schema:
+                type: string
+            text/yaml:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+        default:
+          description: Error
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Error'
+    post:
+      operationId: addPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+          application/x-www-form-urlencoded:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        201:
+          description: Created
+        4XX:
+          description: Invalid pet
+  /pets/{id}:
+    delete:
+      operationId: deletePet
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      responses:
+        204:
+          description: Deleted
+components:
+  schemas:
+    Pet:
+      required:
+      - name
+      properties:
+        name:
+          type: string
+    Error:
+      required:
+      - message
+      properties:
+        message:
+          type: string
+`
+
+func TestStrictResponses(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(strictServerOpenAPIDefinition))
+	require.NoError(t, err)
+
+	ops, err := OperationDefinitions(swagger)
+	require.NoError(t, err)
+	require.Len(t, ops, 3)
+
+	// Equivalent YAML content types share a field.
+	responses, err := ops[0].StrictResponses()
+	require.NoError(t, err)
+	require.Len(t, responses, 4)
+	assert.Equal(t, "JSON200", responses[0].FieldName)
+	assert.Equal(t, "[]Pet", responses[0].Schema.TypeDecl())
+	assert.Equal(t, "*[]Pet", responses[0].TypeDecl())
+	assert.Equal(t, "json", responses[0].Marshaler())
+	assert.Equal(t, "FindPets200ResponseHeaders", responses[0].Headers.TypeName)
+	assert.Equal(t, "YAML200", responses[1].FieldName)
+	assert.Equal(t, "application/yaml", responses[1].ContentType)
+	assert.Equal(t, "yaml", responses[1].Marshaler())
+	assert.False(t, responses[1].IsRaw())
+	// Content types we don't marshal are written from a reader.
+	assert.Equal(t, "TextCsv200", responses[2].FieldName)
+	assert.True(t, responses[2].IsRaw())
+	assert.Equal(t, "io.Reader", responses[2].TypeDecl())
+	assert.Equal(t, "JSONDefault", responses[3].FieldName)
+	assert.False(t, responses[3].IsFixedStatus())
+	assert.Equal(t, 500, responses[3].StatusCode())
+	assert.Nil(t, responses[3].Headers)
+	assert.True(t, ops[0].HasVariableStatusResponses())
+
+	responses, err = ops[1].StrictResponses()
+	require.NoError(t, err)
+	require.Len(t, responses, 2)
+	assert.Equal(t, "NO201", responses[0].FieldName)
+	assert.False(t, responses[0].HasContent())
+	assert.True(t, responses[0].IsFixedStatus())
+	assert.Equal(t, 201, responses[0].StatusCode())
+	assert.Equal(t, "bool", responses[0].TypeDecl())
+	assert.Equal(t, "NO4XX", responses[1].FieldName)
+	assert.Equal(t, 400, responses[1].StatusCode())
+
+	assert.False(t, ops[2].HasVariableStatusResponses())
+}
+
+func TestGenerateStrictServer(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(strictServerOpenAPIDefinition))
+	require.NoError(t, err)
+
+	for _, opts := range []Options{
+		{GenerateTypes: true, GenerateEchoServer: true, GenerateStrictServer: true},
+		{GenerateTypes: true, GenerateChiServer: true, GenerateStrictServer: true},
+	} {
+		code, err := Generate(swagger, "api", opts)
+		require.NoError(t, err)
+
+		_, err = format.Source([]byte(code))
+		assert.NoError(t, err)
+
+		assert.Contains(t, code, "FindPets(ctx context.Context, request FindPetsRequestObject) (FindPetsResponseObject, error)")
+		assert.Contains(t, code, "Params FindPetsParams")
+		assert.Contains(t, code, "FormdataBody *AddPetFormdataRequestBody")
+		assert.Contains(t, code, "Id int")
+		assert.Contains(t, code, "Headers200  *FindPets200ResponseHeaders")
+		assert.Contains(t, code, "StatusCode int")
+		assert.Contains(t, code, "func (r FindPetsResponseObject) WriteResponse(w http.ResponseWriter) error {")
+		assert.Contains(t, code, `runtime.StyleParam("simple", false, "X-Count", r.Headers200.XCount)`)
+		assert.Contains(t, code, "buf, err := yaml.Marshal(r.YAML200)")
+		assert.Contains(t, code, `w.Header().Set("Content-Type", "application/yaml")`)
+		assert.Contains(t, code, "case r.NO4XX:")
+		assert.Contains(t, code, "func NewStrictHandler(ssi StrictServerInterface) ServerInterface {")
+		assert.Contains(t, code, "if request.JSONBody == nil && request.FormdataBody == nil {")
+		assert.Contains(t, code, "request.Id = id")
+
+		// Raw content is copied from its reader.
+		assert.Contains(t, code, "TextCsv200  io.Reader")
+		assert.Contains(t, code, "_, err := io.Copy(w, r.TextCsv200)")
+
+		// The headers of a response are only written along with it.
+		assert.Equal(t, 3, strings.Count(code, "if r.Headers200 != nil {"))
+		assert.Contains(t, code, "case r.JSON200 != nil:\n\t\tstatusCode := 200\n\t\tif r.Headers200 != nil {")
+
+		requireCompiles(t, code)
+	}
+
+	code, err := Generate(swagger, "api", Options{GenerateTypes: true, GenerateEchoServer: true, GenerateStrictServer: true})
+	require.NoError(t, err)
+	assert.Contains(t, code, "func (sh *strictHandler) DeletePet(ctx echo.Context, id int) error {")
+	assert.Contains(t, code, "func (sh *strictHandler) AddPet(ctx echo.Context, formdataBody *AddPetFormdataRequestBody) error {")
+	assert.Contains(t, code, "request.FormdataBody = formdataBody")
+	assert.Contains(t, code, "return response.WriteResponse(ctx.Response())")
+
+	code, err = Generate(swagger, "api", Options{GenerateTypes: true, GenerateChiServer: true, GenerateStrictServer: true})
+	require.NoError(t, err)
+	assert.Contains(t, code, "func (sh *strictHandler) DeletePet(w http.ResponseWriter, r *http.Request, id int) {")
+	assert.Contains(t, code, "func NewStrictHandlerWithOptions(ssi StrictServerInterface, options StrictHTTPServerOptions) ServerInterface {")
+	assert.Contains(t, code, "sh.handleError(w, r, err)")
+	assert.NotContains(t, code, "err.Error(), http.StatusInternalServerError")
+	assert.Contains(t, code, "func (sh *strictHandler) AddPet(w http.ResponseWriter, r *http.Request, formdataBody *AddPetFormdataRequestBody) {")
+	assert.Contains(t, code, "request.FormdataBody = formdataBody")
+
+	// The strict server is an adapter for one of the servers.
+	_, err = Generate(swagger, "api", Options{GenerateTypes: true, GenerateStrictServer: true})
+	assert.Error(t, err)
+}
@@ -81,6 +81,34 @@ func genParamNames(params []ParameterDefinition) string {
 	return ", " + strings.Join(parts, ", ")
 }
 
+// isCallbackClient returns whether the client of the given operations sends
+// callbacks, whose server is the whole URL of a callback, which must be kept
+// as it is, rather than the base URL of an API.
+func isCallbackClient(ops []OperationDefinition) bool {
+	return len(ops) > 0 && ops[0].Callback != nil
+}
+
+// genBodyArgs generates the declarations of the arguments passing the decoded
+// bodies of an operation to server handlers, eg:
+// ", formdataBody *AddPetFormdataRequestBody, textBody *AddPetTextRequestBody"
+func genBodyArgs(op *OperationDefinition) string {
+	var parts []string
+	for _, body := range op.DecodedBodies() {
+		parts = append(parts, fmt.Sprintf(", %s %s", body.ArgName(), op.BodyArgTypeDecl(body)))
+	}
+	return strings.Join(parts, "")
+}
+
+// genBodyNames generates the names of the arguments declared by genBodyArgs,
+// eg: ", formdataBody, textBody"
+func genBodyNames(op *OperationDefinition) string {
+	var parts []string
+	for _, body := range op.DecodedBodies() {
+		parts = append(parts, ", "+body.ArgName())
+	}
+	return strings.Join(parts, "")
+}
+
 func genParamFmtString(path string) string {
 	return ReplacePathParamsWithStr(path)
 }
@@ -235,6 +263,19 @@ func getResponseTypeDefinitions(op *OperationDefinition) []TypeDefinition {
 	return td
 }
 
+// getResponseFieldDefinitions returns the response type definitions which
+// have a body to unmarshal, and so a field in the response struct of the
+// client with responses. Responses without content have none.
+func getResponseFieldDefinitions(op *OperationDefinition) []TypeDefinition {
+	var fields []TypeDefinition
+	for _, td := range getResponseTypeDefinitions(op) {
+		if td.Schema.TypeDecl() != "" {
+			fields = append(fields, td)
+		}
+	}
+	return fields
+}
+
 // Return the statusCode comparison clause from the response name.
 func getConditionOfResponseName(statusCodeVar, responseName string) string {
 	switch responseName {
@@ -260,6 +301,27 @@ func toStringArray(sarr []string) string {
 	return `[]string{"` + strings.Join(sarr, `","`) + `"}`
 }
 
+// genMultipartEncodings produces a map literal of runtime.MultipartEncoding
+// for the given encodings, or nil if there are none.
+func genMultipartEncodings(encodings []RequestBodyEncoding) string {
+	if len(encodings) == 0 {
+		return "nil"
+	}
+	var parts []string
+	for _, e := range encodings {
+		part := fmt.Sprintf("%q: {ContentType: %q", e.PropertyName, e.ContentType)
+		if len(e.Headers) != 0 {
+			var headers []string
+			for _, name := range SortedBoolKeys(e.Headers) {
+				headers = append(headers, fmt.Sprintf("%q: %t", name, e.Headers[name]))
+			}
+			part += ", Headers: map[string]bool{" + strings.Join(headers, ", ") + "}"
+		}
+		parts = append(parts, part+"}")
+	}
+	return "map[string]runtime.MultipartEncoding{" + strings.Join(parts, ", ") + "}"
+}
+
 func stripNewLines(s string) string {
 	r := strings.NewReplacer("\n", "")
 	return r.Replace(s)
@@ -271,6 +333,8 @@ var TemplateFunctions = template.FuncMap{
 	"genParamArgs":               genParamArgs,
 	"genParamTypes":              genParamTypes,
 	"genParamNames":              genParamNames,
+	"genBodyArgs":                genBodyArgs,
+	"genBodyNames":               genBodyNames,
 	"genParamFmtString":          genParamFmtString,
 	"swaggerUriToEchoUri":        SwaggerUriToEchoUri,
 	"swaggerUriToChiUri":         SwaggerUriToChiUri,
@@ -281,8 +345,11 @@ var TemplateFunctions = template.FuncMap{
 	"genResponseTypeName":        genResponseTypeName,
 	"genResponseUnmarshal":       genResponseUnmarshal,
 	"getResponseTypeDefinitions": getResponseTypeDefinitions,
+	"getResponseFieldDefinitions": getResponseFieldDefinitions,
 	"getStatusCode": 			getStatusCode,
 	"toStringArray":              toStringArray,
+	"genMultipartEncodings":      genMultipartEncodings,
+	"isCallbackClient":           isCallbackClient,
 	"lower":                      strings.ToLower,
 	"title":                      strings.Title,
 	"stripNewLines":              stripNewLines,
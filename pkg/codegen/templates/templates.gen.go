@@ -72,15 +72,97 @@ func (a {{.TypeName}}) MarshalJSON() ([]byte, error) {
 	return json.Marshal(object)
 }
 {{end}}
+`,
+	"bad-request.tmpl": `{{if .Echo}}
+// badRequest is returned by the wrappers when a parameter or body of the
+// request can't be bound.
+{{- if .ProblemDetails}} The error carries the problem details, which the
+// default error handler of echo sends as JSON, and which
+// middleware.ProblemErrorHandler sends as application/problem+json.
+{{- end}}
+func badRequest(ctx echo.Context, location, name, reason string) error {
+{{- if .ProblemDetails}}
+    problem := runtime.NewValidationProblem(http.StatusBadRequest, runtime.ValidationFailure{
+        Location:  location,
+        Parameter: name,
+        Reason:    reason,
+    })
+    return &echo.HTTPError{Code: problem.Status, Message: problem, Internal: problem}
+{{- else}}
+    return echo.NewHTTPError(http.StatusBadRequest, reason)
+{{- end}}
+}
+{{else}}
+// badRequest is called by the wrappers when a parameter or body of the
+// request can't be bound.
+func badRequest(w http.ResponseWriter, r *http.Request, location, name, reason string) {
+{{- if .ProblemDetails}}
+    runtime.WriteProblem(w, runtime.NewValidationProblem(http.StatusBadRequest, runtime.ValidationFailure{
+        Location:  location,
+        Parameter: name,
+        Reason:    reason,
+    }))
+{{- else}}
+    http.Error(w, reason, http.StatusBadRequest)
+{{- end}}
+}
+{{end}}
+`,
+	"builders.tmpl": `{{range .Types}}{{$typeName := .TypeName}}
+{{range .Schema.Properties}}{{if .IndirectOptional}}
+// With{{.GoFieldName}} sets the optional {{.JsonFieldName}} field of {{$typeName}} and returns it
+func (t *{{$typeName}}) With{{.GoFieldName}}(value {{.Schema.TypeDecl}}) *{{$typeName}} {
+    t.{{.GoFieldName}} = &value
+    return t
+}
+{{end}}{{end}}
+{{end}}
+
+{{range .Params}}{{$typeName := .TypeName}}
+// {{$typeName}}Option sets an optional parameter in {{$typeName}}.
+type {{$typeName}}Option func(*{{$typeName}})
+
+// New{{$typeName}} creates a {{$typeName}} from the required parameters,
+// and applies the given options to set optional ones.
+func New{{$typeName}}({{range .Schema.Properties}}{{if .Required}}{{.GoBuilderArgName}} {{.GoTypeDef}}, {{end}}{{end}}opts ...{{$typeName}}Option) *{{$typeName}} {
+    params := {{$typeName}}{
+{{- range .Schema.Properties}}{{if .Required}}
+        {{.GoFieldName}}: {{.GoBuilderArgName}},
+{{- end}}{{end}}
+    }
+    for _, o := range opts {
+        o(&params)
+    }
+    return &params
+}
+{{range .Schema.Properties}}{{if .IndirectOptional}}
+// {{$typeName}}With{{.GoFieldName}} sets the optional {{.JsonFieldName}} parameter
+func {{$typeName}}With{{.GoFieldName}}(value {{.Schema.TypeDecl}}) {{$typeName}}Option {
+    return func(params *{{$typeName}}) {
+        params.{{.GoFieldName}} = &value
+    }
+}
+{{end}}{{end}}
+{{end}}
+`,
+	"callback-urls.tmpl": `{{range .}}{{$opid := .OperationId}}{{with .Callback}}
+// {{$opid}}CallbackURL resolves the URL to send the {{.Name}} callback of {{.ParentOperationId}} to,
+// {{.URL}}, from the request which registered the callback. Create the client
+// for the callback with it.
+func {{$opid}}CallbackURL(source runtime.ExpressionSource) (string, error) {
+    return runtime.ExpandExpressions({{printf "%q" .URL}}, source)
+}
+{{end}}{{end}}
 `,
 	"chi-handler.tmpl": `// Handler creates http.Handler with routing matching OpenAPI spec.
 func Handler(si ServerInterface) http.Handler {
   return HandlerFromMux(si, chi.NewRouter())
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux,
+// serving the paths under the ServerBasePath of the spec.
 func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
-    return HandlerFromMuxWithBaseURL(si, r, "")
+    return HandlerFromMuxWithBaseURL(si, r, ServerBasePath)
 }
 
 func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
@@ -94,12 +176,17 @@ func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string)
 {{end}}
   return r
 }
+
+// pathParam returns the value of a path parameter, as matched by chi.
+func pathParam(r *http.Request, name string) string {
+  return chi.URLParam(r, name)
+}
 `,
 	"chi-interface.tmpl": `// ServerInterface represents all server handlers.
 type ServerInterface interface {
 {{range .}}{{.SummaryAsComment }}
 // ({{.Method}} {{.Path}})
-{{.OperationId}}(w http.ResponseWriter, r *http.Request{{genParamArgs .PathParams}}{{if .RequiresParamObject}}, params {{.OperationId}}Params{{end}})
+{{.OperationId}}(w http.ResponseWriter, r *http.Request{{genParamArgs .PathParams}}{{if .RequiresParamObject}}, params {{.OperationId}}Params{{end}}{{genBodyArgs .}})
 {{end}}
 }
 `,
@@ -108,12 +195,18 @@ type ServerInterfaceWrapper struct {
     Handler ServerInterface
 }
 
-{{range .}}{{$opid := .OperationId}}
+{{range .}}{{$op := .}}{{$opid := .OperationId}}
 
 // {{$opid}} operation middleware
 func (siw *ServerInterfaceWrapper) {{$opid}}(w http.ResponseWriter, r *http.Request) {
   ctx := r.Context()
-  {{if or .RequiresParamObject (gt (len .PathParams) 0) }}
+{{if .Deprecated}}
+  w.Header().Set("Deprecation", "true")
+{{- end}}
+{{- if .Sunset}}
+  w.Header().Set("Sunset", "{{.Sunset}}")
+{{- end}}
+  {{if .BindsParamsWithErr}}
   var err error
   {{end}}
 
@@ -121,19 +214,19 @@ func (siw *ServerInterfaceWrapper) {{$opid}}(w http.ResponseWriter, r *http.Requ
   var {{$varName := .GoVariableName}}{{$varName}} {{.TypeDef}}
 
   {{if .IsPassThrough}}
-  {{$varName}} = chi.URLParam(r, "{{.ParamName}}")
+  {{$varName}} = pathParam(r, "{{.ParamName}}")
   {{end}}
   {{if .IsJson}}
-  err = json.Unmarshal([]byte(chi.URLParam(r, "{{.ParamName}}")), &{{$varName}})
+  err = json.Unmarshal([]byte(pathParam(r, "{{.ParamName}}")), &{{$varName}})
   if err != nil {
-    http.Error(w, "Error unmarshaling parameter '{{.ParamName}}' as JSON", http.StatusBadRequest)
+    badRequest(w, r, "path", "{{.ParamName}}", "Error unmarshaling parameter '{{.ParamName}}' as JSON")
     return
   }
   {{end}}
   {{if .IsStyled}}
-  err = runtime.BindStyledParameter("{{.Style}}",{{.Explode}}, "{{.ParamName}}", chi.URLParam(r, "{{.ParamName}}"), &{{$varName}})
+  err = runtime.BindStyledParameter("{{.Style}}",{{.Explode}}, "{{.ParamName}}", pathParam(r, "{{.ParamName}}"), &{{$varName}})
   if err != nil {
-    http.Error(w, fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err), http.StatusBadRequest)
+    badRequest(w, r, "path", "{{.ParamName}}", fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
     return
   }
   {{end}}
@@ -159,20 +252,20 @@ func (siw *ServerInterfaceWrapper) {{$opid}}(w http.ResponseWriter, r *http.Requ
         var value {{.TypeDef}}
         err = json.Unmarshal([]byte(paramValue), &value)
         if err != nil {
-          http.Error(w, "Error unmarshaling parameter '{{.ParamName}}' as JSON", http.StatusBadRequest)
+          badRequest(w, r, "query", "{{.ParamName}}", "Error unmarshaling parameter '{{.ParamName}}' as JSON")
           return
         }
 
         params.{{.GoName}} = {{if not .Required}}&{{end}}value
       {{end}}
       }{{if .Required}} else {
-          http.Error(w, "Query argument {{.ParamName}} is required, but not found", http.StatusBadRequest)
+          badRequest(w, r, "query", "{{.ParamName}}", "Query argument {{.ParamName}} is required, but not found")
           return
       }{{end}}
       {{if .IsStyled}}
       err = runtime.BindQueryParameter("{{.Style}}", {{.Explode}}, {{.Required}}, "{{.ParamName}}", r.URL.Query(), &params.{{.GoName}})
       if err != nil {
-        http.Error(w, fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err), http.StatusBadRequest)
+        badRequest(w, r, "query", "{{.ParamName}}", fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
         return
       }
       {{end}}
@@ -186,7 +279,7 @@ func (siw *ServerInterfaceWrapper) {{$opid}}(w http.ResponseWriter, r *http.Requ
           var {{.GoName}} {{.TypeDef}}
           n := len(valueList)
           if n != 1 {
-            http.Error(w, fmt.Sprintf("Expected one value for {{.ParamName}}, got %d", n), http.StatusBadRequest)
+            badRequest(w, r, "header", "{{.ParamName}}", fmt.Sprintf("Expected one value for {{.ParamName}}, got %d", n))
             return
           }
 
@@ -197,7 +290,7 @@ func (siw *ServerInterfaceWrapper) {{$opid}}(w http.ResponseWriter, r *http.Requ
         {{if .IsJson}}
           err = json.Unmarshal([]byte(valueList[0]), &{{.GoName}})
           if err != nil {
-            http.Error(w, "Error unmarshaling parameter '{{.ParamName}}' as JSON", http.StatusBadRequest)
+            badRequest(w, r, "header", "{{.ParamName}}", "Error unmarshaling parameter '{{.ParamName}}' as JSON")
             return
           }
         {{end}}
@@ -205,7 +298,7 @@ func (siw *ServerInterfaceWrapper) {{$opid}}(w http.ResponseWriter, r *http.Requ
         {{if .IsStyled}}
           err = runtime.BindStyledParameter("{{.Style}}",{{.Explode}}, "{{.ParamName}}", valueList[0], &{{.GoName}})
           if err != nil {
-            http.Error(w, fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err), http.StatusBadRequest)
+            badRequest(w, r, "header", "{{.ParamName}}", fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
             return
           }
         {{end}}
@@ -213,7 +306,7 @@ func (siw *ServerInterfaceWrapper) {{$opid}}(w http.ResponseWriter, r *http.Requ
           params.{{.GoName}} = {{if not .Required}}&{{end}}{{.GoName}}
 
         } {{if .Required}}else {
-            http.Error(w, fmt.Sprintf("Header parameter {{.ParamName}} is required, but not found: %s", err), http.StatusBadRequest)
+            badRequest(w, r, "header", "{{.ParamName}}", fmt.Sprintf("Header parameter {{.ParamName}} is required, but not found: %s", err))
             return
         }{{end}}
 
@@ -232,13 +325,13 @@ func (siw *ServerInterfaceWrapper) {{$opid}}(w http.ResponseWriter, r *http.Requ
         var decoded string
         decoded, err := url.QueryUnescape(cookie.Value)
         if err != nil {
-          http.Error(w, "Error unescaping cookie parameter '{{.ParamName}}'", http.StatusBadRequest)
+          badRequest(w, r, "cookie", "{{.ParamName}}", "Error unescaping cookie parameter '{{.ParamName}}'")
           return
         }
 
         err = json.Unmarshal([]byte(decoded), &value)
         if err != nil {
-          http.Error(w, "Error unmarshaling parameter '{{.ParamName}}' as JSON", http.StatusBadRequest)
+          badRequest(w, r, "cookie", "{{.ParamName}}", "Error unmarshaling parameter '{{.ParamName}}' as JSON")
           return
         }
 
@@ -249,7 +342,7 @@ func (siw *ServerInterfaceWrapper) {{$opid}}(w http.ResponseWriter, r *http.Requ
         var value {{.TypeDef}}
         err = runtime.BindStyledParameter("simple",{{.Explode}}, "{{.ParamName}}", cookie.Value, &value)
         if err != nil {
-          http.Error(w, "Invalid format for parameter {{.ParamName}}: %s", http.StatusBadRequest)
+          badRequest(w, r, "cookie", "{{.ParamName}}", fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
           return
         }
         params.{{.GoName}} = {{if not .Required}}&{{end}}value
@@ -258,18 +351,179 @@ func (siw *ServerInterfaceWrapper) {{$opid}}(w http.ResponseWriter, r *http.Requ
       }
 
       {{- if .Required}} else {
-        http.Error(w, "Query argument {{.ParamName}} is required, but not found", http.StatusBadRequest)
+        badRequest(w, r, "cookie", "{{.ParamName}}", "Query argument {{.ParamName}} is required, but not found")
         return
       }
       {{- end}}
     {{end}}
   {{end}}
-  siw.Handler.{{.OperationId}}(w, r.WithContext(ctx){{genParamNames .PathParams}}{{if .RequiresParamObject}}, params{{end}})
+{{range .DecodedBodies}}
+  // ------------- {{.ContentType}} body -------------
+  var {{.ArgName}} {{$op.BodyArgTypeDecl .}}
+  if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "{{.ContentType}}" {
+  {{- if .HasFileParts}}
+    // The parts are streamed to the handler, which reads them in order.
+    mr, err := runtime.NewMultipartReader(r, {{genMultipartEncodings .Encodings}})
+    if err != nil {
+      badRequest(w, r, "body", "", fmt.Sprintf("Error reading {{.ContentType}} body: %s", err))
+      return
+    }
+    {{.ArgName}} = mr
+  {{- else}}
+    var body {{$opid}}{{.NameTag}}RequestBody
+  {{- if .IsFormEncoded}}
+    if err := r.ParseForm(); err != nil {
+      badRequest(w, r, "body", "", fmt.Sprintf("Error parsing {{.ContentType}} body: %s", err))
+      return
+    }
+    if err := runtime.BindForm(r.PostForm, &body); err != nil {
+      badRequest(w, r, "body", "", fmt.Sprintf("Invalid format for {{.ContentType}} body: %s", err))
+      return
+    }
+  {{- else if .IsText}}
+    buf, err := ioutil.ReadAll(r.Body)
+    if err != nil {
+      badRequest(w, r, "body", "", fmt.Sprintf("Error reading {{.ContentType}} body: %s", err))
+      return
+    }
+    body = {{$opid}}{{.NameTag}}RequestBody(buf)
+  {{- else if .IsBinary}}
+    body = r.Body
+  {{- else if .IsMultipart}}
+    // The whole body is parsed before the handler is called, spooling
+    // parts beyond runtime.MaxMultipartMemory to temporary files.
+    if err := r.ParseMultipartForm(runtime.MaxMultipartMemory); err != nil {
+      badRequest(w, r, "body", "", fmt.Sprintf("Error parsing {{.ContentType}} body: %s", err))
+      return
+    }
+    if err := runtime.BindMultipart(r.MultipartForm, &body, {{genMultipartEncodings .Encodings}}); err != nil {
+      badRequest(w, r, "body", "", fmt.Sprintf("Invalid format for {{.ContentType}} body: %s", err))
+      return
+    }
+  {{- end}}
+    {{.ArgName}} = {{if not .IsBinary}}&{{end}}body
+  {{- end}}
+  }
+{{end}}
+  siw.Handler.{{.OperationId}}(w, r.WithContext(ctx){{genParamNames .PathParams}}{{if .RequiresParamObject}}, params{{end}}{{genBodyNames .}})
+}
+{{end}}
+
+
+
+`,
+	"client-errors.tmpl": `{{range .}}{{$opid := .OperationId}}
+{{range .ErrorResponses}}
+// {{.TypeName}} is the error returned for {{if .IsDefault}}the default response{{else}}{{.ResponseName}} responses{{end}} to {{$opid}}
+type {{.TypeName}} struct {
+    Body         []byte
+    HTTPResponse *http.Response
+    {{- range .Payloads}}
+    {{.TypeName}} *{{.Schema.TypeDecl}}
+    {{- end}}
+    {{- with .Headers}}
+    {{.FieldName}} *{{.TypeName}}
+    {{- end}}
+}
+
+func (e *{{.TypeName}}) Error() string {
+    return runtime.ResponseErrorMessage("{{$opid}}", e.HTTPResponse)
+}
+
+// Response returns the response which the error was returned for.
+func (e *{{.TypeName}}) Response() *http.Response {
+    return e.HTTPResponse
+}
+
+// ResponseBody returns the body of the response.
+func (e *{{.TypeName}}) ResponseBody() []byte {
+    return e.Body
 }
 {{end}}
 
+// Err returns nil for 2XX responses, and otherwise the error for the response,
+// which is {{.ErrTypesDescription}}.
+func (r {{genResponseTypeName $opid}}) Err() error {
+    code := r.StatusCode()
+    switch {
+    case code / 100 == 2:
+        return nil
+{{- $hasDefault := false}}
+{{- range .ErrorResponses}}
+    {{if .IsDefault}}{{$hasDefault = true}}default{{else}}case {{.StatusCondition}}{{end}}:
+        return &{{.TypeName}}{
+            Body:         r.Body,
+            HTTPResponse: r.HTTPResponse,
+            {{- range .Payloads}}
+            {{.TypeName}}: r.{{.TypeName}},
+            {{- end}}
+            {{- with .Headers}}
+            {{.FieldName}}: r.{{.FieldName}},
+            {{- end}}
+        }
+{{- end}}
+{{- if not $hasDefault}}
+    default:
+        return &runtime.UnexpectedResponseError{
+            OperationID:  "{{$opid}}",
+            HTTPResponse: r.HTTPResponse,
+            Body:         r.Body,
+        }
+{{- end}}
+    }
+}
+{{end}}{{/* range . $opid := .OperationId */}}
 
+{{range .}}
+{{$opid := .OperationId -}}
+{{$op := . -}}
+{{$payloads := .SuccessPayloads -}}
+{{/* Generate client methods returning the success payload */}}
 
+// {{$opid}}{{if .HasBody}}WithBody{{end}}WithResult calls {{$opid}}{{if .HasBody}}WithBody{{end}}WithResponse, and returns {{if $payloads}}the JSON payload of a{{else}}nil for a{{end}}
+// 2XX response, or the error returned by Err for any other response.{{if .Deprecated}}
+//
+// {{.DeprecationNotice}}{{end}}
+func (c *ClientWithResponses) {{$opid}}{{if .HasBody}}WithBody{{end}}WithResult(ctx context.Context{{genParamArgs .PathParams}}{{if .RequiresParamObject}}, params *{{$opid}}Params{{end}}{{if .HasBody}}, contentType string, body io.Reader{{end}}) ({{with $payloads}}*{{(index . 0).Schema.TypeDecl}}, {{end}}error) {
+    rsp, err := c.{{$opid}}{{if .HasBody}}WithBody{{end}}WithResponse(ctx{{genParamNames .PathParams}}{{if .RequiresParamObject}}, params{{end}}{{if .HasBody}}, contentType, body{{end}})
+    if err != nil {
+        return {{if $payloads}}nil, {{end}}err
+    }
+    if err := rsp.Err(); err != nil {
+        return {{if $payloads}}nil, {{end}}err
+    }
+{{- range $payloads}}
+    if rsp.{{.TypeName}} != nil {
+        return rsp.{{.TypeName}}, nil
+    }
+{{- end}}
+    return {{if $payloads}}nil, {{end}}nil
+}
+
+{{$hasParams := .RequiresParamObject -}}
+{{$pathParams := .PathParams -}}
+{{range .Bodies}}
+// {{$opid}}{{.Suffix}}WithResult calls {{$opid}}{{.Suffix}}WithResponse, and returns {{if $payloads}}the JSON payload of a{{else}}nil for a{{end}}
+// 2XX response, or the error returned by Err for any other response.{{if $op.Deprecated}}
+//
+// {{$op.DeprecationNotice}}{{end}}
+func (c *ClientWithResponses) {{$opid}}{{.Suffix}}WithResult(ctx context.Context{{genParamArgs $pathParams}}{{if $hasParams}}, params *{{$opid}}Params{{end}}, body {{$opid}}{{.NameTag}}RequestBody) ({{with $payloads}}*{{(index . 0).Schema.TypeDecl}}, {{end}}error) {
+    rsp, err := c.{{$opid}}{{.Suffix}}WithResponse(ctx{{genParamNames $pathParams}}{{if $hasParams}}, params{{end}}, body)
+    if err != nil {
+        return {{if $payloads}}nil, {{end}}err
+    }
+    if err := rsp.Err(); err != nil {
+        return {{if $payloads}}nil, {{end}}err
+    }
+{{- range $payloads}}
+    if rsp.{{.TypeName}} != nil {
+        return rsp.{{.TypeName}}, nil
+    }
+{{- end}}
+    return {{if $payloads}}nil, {{end}}nil
+}
+{{end}}{{/* range .Bodies */}}
+{{end}}{{/* range . $opid := .OperationId */}}
 `,
 	"client-with-responses.tmpl": `// ClientWithResponses builds on ClientInterface to offer response payloads
 type ClientWithResponses struct {
@@ -304,9 +558,13 @@ type ClientWithResponsesInterface interface {
 {{$hasParams := .RequiresParamObject -}}
 {{$pathParams := .PathParams -}}
 {{$opid := .OperationId -}}
-    // {{$opid}} request {{if .HasBody}} with any body{{end}}
+{{$op := . -}}
+    // {{$opid}} request {{if .HasBody}} with any body{{end}}{{if .Deprecated}}
+    //
+    // {{.DeprecationNotice}}{{end}}
     {{$opid}}{{if .HasBody}}WithBody{{end}}WithResponse(ctx context.Context{{genParamArgs .PathParams}}{{if .RequiresParamObject}}, params *{{$opid}}Params{{end}}{{if .HasBody}}, contentType string, body io.Reader{{end}}) (*{{genResponseTypeName $opid}}, error)
-{{range .Bodies}}
+{{range .Bodies}}{{if $op.Deprecated}}
+    // {{$op.DeprecationNotice}}{{end}}
     {{$opid}}{{.Suffix}}WithResponse(ctx context.Context{{genParamArgs $pathParams}}{{if $hasParams}}, params *{{$opid}}Params{{end}}, body {{$opid}}{{.NameTag}}RequestBody) (*{{genResponseTypeName $opid}}, error)
 {{end}}{{/* range .Bodies */}}
 {{end}}{{/* range . $opid := .OperationId */}}
@@ -316,9 +574,12 @@ type ClientWithResponsesInterface interface {
 type {{$opid | ucFirst}}Response struct {
     Body         []byte
 	HTTPResponse *http.Response
-    {{- range getResponseTypeDefinitions .}}
+    {{- range getResponseFieldDefinitions .}}
     {{.TypeName}} *{{.Schema.TypeDecl}}
     {{- end}}
+    {{- range .ResponseHeaders}}
+    {{.FieldName}} *{{.TypeName}}
+    {{- end}}
 }
 
 // Status returns HTTPResponse.Status
@@ -341,9 +602,12 @@ func (r {{$opid | ucFirst}}Response) StatusCode() int {
 
 {{range .}}
 {{$opid := .OperationId -}}
+{{$op := . -}}
 {{/* Generate client methods (with responses)*/}}
 
-// {{$opid}}{{if .HasBody}}WithBody{{end}}WithResponse request{{if .HasBody}} with arbitrary body{{end}} returning *{{$opid}}Response
+// {{$opid}}{{if .HasBody}}WithBody{{end}}WithResponse request{{if .HasBody}} with arbitrary body{{end}} returning *{{$opid}}Response{{if .Deprecated}}
+//
+// {{.DeprecationNotice}}{{end}}
 func (c *ClientWithResponses) {{$opid}}{{if .HasBody}}WithBody{{end}}WithResponse(ctx context.Context{{genParamArgs .PathParams}}{{if .RequiresParamObject}}, params *{{$opid}}Params{{end}}{{if .HasBody}}, contentType string, body io.Reader{{end}}) (*{{genResponseTypeName $opid}}, error){
     rsp, err := c.{{$opid}}{{if .HasBody}}WithBody{{end}}(ctx{{genParamNames .PathParams}}{{if .RequiresParamObject}}, params{{end}}{{if .HasBody}}, contentType, body{{end}})
     if err != nil {
@@ -355,7 +619,8 @@ func (c *ClientWithResponses) {{$opid}}{{if .HasBody}}WithBody{{end}}WithRespons
 {{$hasParams := .RequiresParamObject -}}
 {{$pathParams := .PathParams -}}
 {{$bodyRequired := .BodyRequired -}}
-{{range .Bodies}}
+{{range .Bodies}}{{if $op.Deprecated}}
+// {{$op.DeprecationNotice}}{{end}}
 func (c *ClientWithResponses) {{$opid}}{{.Suffix}}WithResponse(ctx context.Context{{genParamArgs $pathParams}}{{if $hasParams}}, params *{{$opid}}Params{{end}}, body {{$opid}}{{.NameTag}}RequestBody) (*{{genResponseTypeName $opid}}, error) {
     rsp, err := c.{{$opid}}{{.Suffix}}(ctx{{genParamNames $pathParams}}{{if $hasParams}}, params{{end}}, body)
     if err != nil {
@@ -381,7 +646,26 @@ func Parse{{genResponseTypeName $opid | ucFirst}}(rsp *http.Response) (*{{genRes
     response := {{genResponsePayload $opid}}
 
     {{genResponseUnmarshal .}}
-
+{{if .ResponseHeaders}}
+    switch {
+{{- range .ResponseHeaders}}
+    case {{.StatusCondition}}:
+        var headers {{.TypeName}}
+{{- range .Headers}}
+        if valueList, found := rsp.Header[http.CanonicalHeaderKey("{{.HeaderName}}")]; found {
+            var value {{.TypeDef}}
+            if err := runtime.BindStyledParameter("simple", false, "{{.HeaderName}}", strings.Join(valueList, ","), &value); err != nil {
+                return nil, fmt.Errorf("invalid format for response header {{.HeaderName}}: %s", err)
+            }
+            headers.{{.GoName}} = {{if not .Required}}&{{end}}value
+        }{{if .Required}} else {
+            return nil, fmt.Errorf("required response header {{.HeaderName}} is missing")
+        }{{end}}
+{{- end}}
+        response.{{.FieldName}} = &headers
+{{- end}}
+    }
+{{end}}
     return response, nil
 }
 {{end}}{{/* range . $opid := .OperationId */}}
@@ -390,6 +674,10 @@ func Parse{{genResponseTypeName $opid | ucFirst}}(rsp *http.Response) (*{{genRes
 	"client.tmpl": `// RequestEditorFn  is the function signature for the RequestEditor callback function
 type RequestEditorFn func(ctx context.Context, req *http.Request) error
 
+// DeprecatedCallFn is the function signature for the OnDeprecatedCall callback
+// function, which gets the ID of the operation called and its response.
+type DeprecatedCallFn func(ctx context.Context, operationID string, rsp *http.Response)
+
 // Doer performs HTTP requests.
 //
 // The standard http.Client implements this interface.
@@ -412,6 +700,10 @@ type Client struct {
 	// A callback for modifying requests which are generated before sending over
 	// the network.
 	RequestEditor RequestEditorFn
+
+	// A callback for responses which have a Deprecation header, which is
+	// useful to find the callers of deprecated operations.
+	OnDeprecatedCall DeprecatedCallFn
 }
 
 // ClientOption allows setting custom parameters during construction
@@ -429,10 +721,12 @@ func NewClient(server string, opts ...ClientOption) (*Client, error) {
             return nil, err
         }
     }
+{{- if not (isCallbackClient .)}}
     // ensure the server URL always has a trailing slash
     if !strings.HasSuffix(client.Server, "/") {
         client.Server += "/"
     }
+{{- end}}
     // create httpClient, if not already present
     if client.Client == nil {
         client.Client = http.DefaultClient
@@ -458,15 +752,37 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 	}
 }
 
+// WithOnDeprecatedCall allows setting up a callback function, which will be
+// called with each response which has a Deprecation header, meaning that the
+// operation called is deprecated.
+func WithOnDeprecatedCall(fn DeprecatedCallFn) ClientOption {
+	return func(c *Client) error {
+		c.OnDeprecatedCall = fn
+		return nil
+	}
+}
+
+// notifyDeprecatedCall calls OnDeprecatedCall if the response has a
+// Deprecation header.
+func (c *Client) notifyDeprecatedCall(ctx context.Context, operationID string, rsp *http.Response) {
+	if c.OnDeprecatedCall != nil && rsp.Header.Get("Deprecation") != "" {
+		c.OnDeprecatedCall(ctx, operationID, rsp)
+	}
+}
+
 // The interface specification for the client above.
 type ClientInterface interface {
 {{range . -}}
 {{$hasParams := .RequiresParamObject -}}
 {{$pathParams := .PathParams -}}
 {{$opid := .OperationId -}}
-    // {{$opid}} request {{if .HasBody}} with any body{{end}}
+{{$op := . -}}
+    // {{$opid}} request {{if .HasBody}} with any body{{end}}{{if .Deprecated}}
+    //
+    // {{.DeprecationNotice}}{{end}}
     {{$opid}}{{if .HasBody}}WithBody{{end}}(ctx context.Context{{genParamArgs $pathParams}}{{if $hasParams}}, params *{{$opid}}Params{{end}}{{if .HasBody}}, contentType string, body io.Reader{{end}}) (*http.Response, error)
-{{range .Bodies}}
+{{range .Bodies}}{{if $op.Deprecated}}
+    // {{$op.DeprecationNotice}}{{end}}
     {{$opid}}{{.Suffix}}(ctx context.Context{{genParamArgs $pathParams}}{{if $hasParams}}, params *{{$opid}}Params{{end}}, body {{$opid}}{{.NameTag}}RequestBody) (*http.Response, error)
 {{end}}{{/* range .Bodies */}}
 {{end}}{{/* range . $opid := .OperationId */}}
@@ -478,7 +794,9 @@ type ClientInterface interface {
 {{$hasParams := .RequiresParamObject -}}
 {{$pathParams := .PathParams -}}
 {{$opid := .OperationId -}}
-
+{{$op := . -}}
+{{if .Deprecated}}
+// {{.DeprecationNotice}}{{end}}
 func (c *Client) {{$opid}}{{if .HasBody}}WithBody{{end}}(ctx context.Context{{genParamArgs $pathParams}}{{if $hasParams}}, params *{{$opid}}Params{{end}}{{if .HasBody}}, contentType string, body io.Reader{{end}}) (*http.Response, error) {
     req, err := New{{$opid}}Request{{if .HasBody}}WithBody{{end}}(c.Server{{genParamNames .PathParams}}{{if $hasParams}}, params{{end}}{{if .HasBody}}, contentType, body{{end}})
     if err != nil {
@@ -491,12 +809,17 @@ func (c *Client) {{$opid}}{{if .HasBody}}WithBody{{end}}(ctx context.Context{{ge
             return nil, err
         }
     }
-    return c.Client.Do(req)
+    rsp, err := c.Client.Do(req)
+    if err == nil {
+        c.notifyDeprecatedCall(ctx, "{{$opid}}", rsp)
+    }
+    return rsp, err
 }
 
-{{range .Bodies}}
+{{range .Bodies}}{{if $op.Deprecated}}
+// {{$op.DeprecationNotice}}{{end}}
 func (c *Client) {{$opid}}{{.Suffix}}(ctx context.Context{{genParamArgs $pathParams}}{{if $hasParams}}, params *{{$opid}}Params{{end}}, body {{$opid}}{{.NameTag}}RequestBody) (*http.Response, error) {
-    req, err := New{{$opid}}{{.Suffix}}Request(c.Server{{genParamNames $pathParams}}{{if $hasParams}}, params{{end}}, body)
+    req, err := New{{$opid}}Request{{.Suffix}}(c.Server{{genParamNames $pathParams}}{{if $hasParams}}, params{{end}}, body)
     if err != nil {
         return nil, err
     }
@@ -507,7 +830,11 @@ func (c *Client) {{$opid}}{{.Suffix}}(ctx context.Context{{genParamArgs $pathPar
             return nil, err
         }
     }
-    return c.Client.Do(req)
+    rsp, err := c.Client.Do(req)
+    if err == nil {
+        c.notifyDeprecatedCall(ctx, "{{$opid}}", rsp)
+    }
+    return rsp, err
 }
 {{end}}{{/* range .Bodies */}}
 {{end}}
@@ -518,21 +845,40 @@ func (c *Client) {{$opid}}{{.Suffix}}(ctx context.Context{{genParamArgs $pathPar
 {{$pathParams := .PathParams -}}
 {{$bodyRequired := .BodyRequired -}}
 {{$opid := .OperationId -}}
+{{$op := . -}}
 
 {{range .Bodies}}
-// New{{$opid}}Request{{.Suffix}} calls the generic {{$opid}} builder with {{.ContentType}} body
+// New{{$opid}}Request{{.Suffix}} calls the generic {{$opid}} builder with {{.ContentType}} body{{if $op.Deprecated}}
+//
+// {{$op.DeprecationNotice}}{{end}}
 func New{{$opid}}Request{{.Suffix}}(server string{{genParamArgs $pathParams}}{{if $hasParams}}, params *{{$opid}}Params{{end}}, body {{$opid}}{{.NameTag}}RequestBody) (*http.Request, error) {
     var bodyReader io.Reader
+{{- if .IsFormEncoded}}
+    form, err := runtime.MarshalForm(body)
+    if err != nil {
+        return nil, err
+    }
+    bodyReader = strings.NewReader(form.Encode())
+{{- else if .IsText}}
+    bodyReader = strings.NewReader(string(body))
+{{- else if .IsBinary}}
+    bodyReader = body
+{{- else if .IsMultipart}}
+    contentType, bodyReader := runtime.NewMultipartBody(body, {{genMultipartEncodings .Encodings}})
+{{- else}}
     buf, err := json.Marshal(body)
     if err != nil {
         return nil, err
     }
     bodyReader = bytes.NewReader(buf)
-    return New{{$opid}}RequestWithBody(server{{genParamNames $pathParams}}{{if $hasParams}}, params{{end}}, "{{.ContentType}}", bodyReader)
+{{- end}}
+    return New{{$opid}}RequestWithBody(server{{genParamNames $pathParams}}{{if $hasParams}}, params{{end}}, {{if .IsMultipart}}contentType{{else}}"{{.ContentType}}"{{end}}, bodyReader)
 }
 {{end}}
 
-// New{{$opid}}Request{{if .HasBody}}WithBody{{end}} generates requests for {{$opid}}{{if .HasBody}} with any type of body{{end}}
+// New{{$opid}}Request{{if .HasBody}}WithBody{{end}} generates requests for {{$opid}}{{if .HasBody}} with any type of body{{end}}{{if .Deprecated}}
+//
+// {{.DeprecationNotice}}{{end}}
 func New{{$opid}}Request{{if .HasBody}}WithBody{{end}}(server string{{genParamArgs $pathParams}}{{if $hasParams}}, params *{{$opid}}Params{{end}}{{if .HasBody}}, contentType string, body io.Reader{{end}}) (*http.Request, error) {
     var err error
 {{range $paramIdx, $param := .PathParams}}
@@ -554,12 +900,19 @@ func New{{$opid}}Request{{if .HasBody}}WithBody{{end}}(server string{{genParamAr
         return nil, err
     }
     {{end}}
+{{end}}
+{{if .Path}}
+    // ensure the server URL has a trailing slash, so that the path is
+    // appended to it
+    if !strings.HasSuffix(server, "/") {
+        server += "/"
+    }
 {{end}}
     queryUrl, err := url.Parse(server)
     if err != nil {
         return nil, err
     }
-
+{{if .Path}}
     basePath := fmt.Sprintf("{{genParamFmtString .Path}}"{{range $paramIdx, $param := .PathParams}}, pathParam{{$paramIdx}}{{end}})
     if basePath[0] == '/' {
         basePath = basePath[1:]
@@ -569,7 +922,7 @@ func New{{$opid}}Request{{if .HasBody}}WithBody{{end}}(server string{{genParamAr
     if err != nil {
         return nil, err
     }
-{{if .QueryParams}}
+{{end}}{{if .QueryParams}}
     queryValues := queryUrl.Query()
 {{range $paramIdx, $param := .QueryParams}}
     {{if not .Required}} if params.{{.GoName}} != nil { {{end}}
@@ -665,7 +1018,7 @@ func New{{$opid}}Request{{if .HasBody}}WithBody{{end}}(server string{{genParamAr
 `,
 	"imports.tmpl": `// Package {{.PackageName}} provides primitives to interact the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen DO NOT EDIT.
+// Code generated by github.com/leslie-wang/oapi-codegen DO NOT EDIT.
 package {{.PackageName}}
 
 import (
@@ -679,14 +1032,17 @@ import (
 	"gopkg.in/yaml.v2"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/deepmap/oapi-codegen/pkg/runtime"
-	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/leslie-wang/oapi-codegen/pkg/middleware"
+	"github.com/leslie-wang/oapi-codegen/pkg/runtime"
+	openapi_types "github.com/leslie-wang/oapi-codegen/pkg/types"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi"
 	"github.com/labstack/echo/v4"
@@ -702,29 +1058,130 @@ var swaggerSpec = []string{
     "{{.}}",{{end}}
 }
 
+var (
+    rawSpecOnce sync.Once
+    rawSpecData []byte
+    rawSpecErr  error
+
+    cachedSwaggerOnce sync.Once
+    cachedSwagger     *openapi3.Swagger
+    cachedSwaggerErr  error
+)
+
+// rawSpec returns the JSON of the Swagger specification, which is decoded the
+// first time it's needed.
+func rawSpec() ([]byte, error) {
+    rawSpecOnce.Do(func() {
+        zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+        if err != nil {
+            rawSpecErr = fmt.Errorf("error base64 decoding spec: %s", err)
+            return
+        }
+        zr, err := gzip.NewReader(bytes.NewReader(zipped))
+        if err != nil {
+            rawSpecErr = fmt.Errorf("error decompressing spec: %s", err)
+            return
+        }
+        var buf bytes.Buffer
+        _, err = buf.ReadFrom(zr)
+        if err != nil {
+            rawSpecErr = fmt.Errorf("error decompressing spec: %s", err)
+            return
+        }
+        rawSpecData = buf.Bytes()
+    })
+    return rawSpecData, rawSpecErr
+}
+
 // GetSwagger returns the Swagger specification corresponding to the generated code
-// in this file.
+// in this file. Each call returns a copy of its own, which may be modified.
 func GetSwagger() (*openapi3.Swagger, error) {
-    zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
-    if err != nil {
-        return nil, fmt.Errorf("error base64 decoding spec: %s", err)
-    }
-    zr, err := gzip.NewReader(bytes.NewReader(zipped))
+    data, err := rawSpec()
     if err != nil {
-        return nil, fmt.Errorf("error decompressing spec: %s", err)
-    }
-    var buf bytes.Buffer
-    _, err = buf.ReadFrom(zr)
-    if err != nil {
-        return nil, fmt.Errorf("error decompressing spec: %s", err)
+        return nil, err
     }
 
-    swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(buf.Bytes())
+    swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(data)
     if err != nil {
         return nil, fmt.Errorf("error loading Swagger: %s", err)
     }
     return swagger, nil
 }
+
+// GetCachedSwagger returns the Swagger specification corresponding to the
+// generated code in this file, which is loaded the first time it's needed,
+// and shared by all the callers, so it mustn't be modified.
+func GetCachedSwagger() (*openapi3.Swagger, error) {
+    cachedSwaggerOnce.Do(func() {
+        cachedSwagger, cachedSwaggerErr = GetSwagger()
+    })
+    return cachedSwagger, cachedSwaggerErr
+}
+`,
+	"mocks.tmpl": `{{range .}}{{$mock := .TypeName}}{{$iface := .InterfaceName}}
+// Ensure that {{$mock}} implements {{$iface}}.
+var _ {{$iface}} = &{{$mock}}{}
+
+// {{$mock}} is a mock implementation of {{$iface}}. Each method records
+// its call, and then calls the function set in the field of the same name
+// with a Func suffix, panicking when it isn't set. The calls are returned by
+// the methods with a Calls suffix, and checked by AssertCalled.
+type {{$mock}} struct {
+{{- range .Methods}}
+    // {{.Name}}Func mocks the {{.Name}} method.
+    {{.Name}}Func func({{.ParamArgs}}) {{.ResultsDecl}}
+{{end}}
+    recorder runtime.MockRecorder
+}
+
+var {{.MethodsVarName}} = []string{
+{{- range .Methods}}
+    "{{.Name}}",
+{{- end}}
+}
+{{range .Methods}}
+// {{.Name}} records the call, and calls {{.Name}}Func.
+func (mock *{{$mock}}) {{.Name}}({{.ParamArgs}}) {{.ResultsDecl}} {
+    mock.recorder.Record("{{.Name}}", {{.CallType}}{ {{.ParamNames}} })
+    if mock.{{.Name}}Func == nil {
+        panic("{{$mock}}.{{.Name}}Func: method is nil but {{$iface}}.{{.Name}} was just called")
+    }
+    {{if .Results}}return {{end}}mock.{{.Name}}Func({{.ParamNames}})
+}
+
+// {{.Name}}Calls returns the calls made to {{.Name}}, in order.
+func (mock *{{$mock}}) {{.Name}}Calls() []{{.CallType}} {
+    var calls []{{.CallType}}
+    for _, call := range mock.recorder.Calls("{{.Name}}") {
+        calls = append(calls, call.Args.({{.CallType}}))
+    }
+    return calls
+}
+{{end}}
+// AllCalls returns the calls made to all the methods of the mock, in order.
+func (mock *{{$mock}}) AllCalls() []runtime.MockCall {
+    return mock.recorder.Calls("")
+}
+
+// ResetCalls forgets the calls made to the mock.
+func (mock *{{$mock}}) ResetCalls() {
+    mock.recorder.Reset()
+}
+
+// AssertCalled reports an error to t unless method was called the given
+// number of times, and returns whether it was.
+func (mock *{{$mock}}) AssertCalled(t runtime.TestReporter, method string, times int) bool {
+    t.Helper()
+    return mock.recorder.AssertCalled(t, "{{$mock}}", {{.MethodsVarName}}, method, times)
+}
+
+// AssertNotCalled reports an error to t if method was called, and returns
+// whether it wasn't.
+func (mock *{{$mock}}) AssertNotCalled(t runtime.TestReporter, method string) bool {
+    t.Helper()
+    return mock.recorder.AssertCalled(t, "{{$mock}}", {{.MethodsVarName}}, method, 0)
+}
+{{end}}
 `,
 	"param-types.tmpl": `{{range .}}{{$opid := .OperationId}}
 {{range .TypeDefinitions}}
@@ -750,9 +1207,10 @@ type EchoRouter interface {
 	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
 }
 
-// RegisterHandlers adds each server route to the EchoRouter.
+// RegisterHandlers adds each server route to the EchoRouter, under the
+// ServerBasePath of the spec.
 func RegisterHandlers(router EchoRouter, si ServerInterface) {
-    RegisterHandlersWithBaseURL(router, si, "")
+    RegisterHandlersWithBaseURL(router, si, ServerBasePath)
 }
 
 // Registers handlers, and prepends BaseURL to the paths, so that the paths
@@ -769,18 +1227,377 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 `,
 	"request-bodies.tmpl": `{{range .}}{{$opid := .OperationId}}
 {{range .Bodies}}
-// {{$opid}}RequestBody defines body for {{$opid}} for application/json ContentType.
+// {{$opid}}{{.NameTag}}RequestBody defines body for {{$opid}} for {{.ContentType}} ContentType.
 type {{$opid}}{{.NameTag}}RequestBody {{.TypeDef}}
 {{end}}
 {{end}}
+`,
+	"response-headers.tmpl": `{{range .}}{{$opid := .OperationId}}
+{{range .ResponseHeaders}}
+// {{.TypeName}} holds the headers of the {{.ResponseName}} response of {{$opid}}.
+type {{.TypeName}} struct {
+{{- range .Headers}}
+    {{if .Description}}// {{.Description | stripNewLines}}
+    {{end}}{{.GoName}} {{.GoTypeDef}}
+{{- end}}
+}
+{{end}}
+{{- end}}
+`,
+	"server-base-path.tmpl": `
+// ServerBasePath is the path of the URL of the first server of the spec,
+// which the handlers are registered under unless another base URL is given.
+const ServerBasePath = {{printf "%q" .}}
 `,
 	"server-interface.tmpl": `// ServerInterface represents all server handlers.
 type ServerInterface interface {
 {{range .}}{{.SummaryAsComment }}
 // ({{.Method}} {{.Path}})
-{{.OperationId}}(ctx echo.Context{{genParamArgs .PathParams}}{{if .RequiresParamObject}}, params {{.OperationId}}Params{{end}}) error
+{{.OperationId}}(ctx echo.Context{{genParamArgs .PathParams}}{{if .RequiresParamObject}}, params {{.OperationId}}Params{{end}}{{genBodyArgs .}}) error
+{{end}}
+}
+`,
+	"server-urls.tmpl": `{{range .}}{{$server := .}}
+// ServerURL{{.Name}} is the URL of {{if .Description}}the "{{.Description}}" server{{else}}server {{.Name}}{{end}}{{if .Variables}},
+// with its variables set to their defaults{{end}}.
+const ServerURL{{.Name}} = {{printf "%q" .DefaultURL}}
+{{range .Variables}}{{if .IsEnum}}
+// {{.TypeName}} is the {{.Name}} variable of the URL of server {{$server.Name}}.{{if .Description}}
+// {{.Description}}{{end}}
+type {{.TypeName}} string
+
+// List of {{.TypeName}}
+const (
+{{- $typeName := .TypeName }}
+{{- range $key, $value := .EnumValues }}
+    {{ $typeName }}_{{ $key }} {{ $typeName }} = {{printf "%q" $value}}
+{{- end }}
+)
+{{end}}{{end}}
+{{- if .Variables}}
+// NewServerURL{{.Name}} returns the URL of server {{.Name}}, {{.URL}},
+// for the given values of its variables.
+func NewServerURL{{.Name}}({{range $i, $v := .Variables}}{{if $i}}, {{end}}{{.GoVariableName}} {{.TypeName}}{{end}}) (string, error) {
+{{- range .Variables}}{{if .IsEnum}}
+    switch {{.GoVariableName}} {
+    case {{range $i, $c := .EnumConstants}}{{if $i}}, {{end}}{{$c}}{{end}}:
+    default:
+        return "", fmt.Errorf("invalid value for variable {{.Name}} of server {{$server.Name}}: %s", {{.GoVariableName}})
+    }
+{{- end}}{{end}}
+    return fmt.Sprintf({{printf "%q" .URLFormatString}}{{range .URLFormatArgs}}, {{.}}{{end}}), nil
+}
+{{end}}{{end}}
+`,
+	"spec-handler.tmpl": `var (
+    specHandlerOnce sync.Once
+    specHandler     *middleware.SpecHandler
+    specHandlerErr  error
+)
+
+// SpecHandler returns the handler which serves the spec embedded in this
+// file, as JSON to requests for paths ending in .json, as YAML to those
+// ending in .yaml, and as an HTML reference page to the others. It returns
+// an error if the embedded spec can't be loaded.
+func SpecHandler() (http.Handler, error) {
+    specHandlerOnce.Do(func() {
+        data, err := rawSpec()
+        if err != nil {
+            specHandlerErr = err
+            return
+        }
+        specHandler, specHandlerErr = middleware.NewSpecHandler(data)
+    })
+    if specHandlerErr != nil {
+        return nil, specHandlerErr
+    }
+    return specHandler, nil
+}
+{{if .Echo}}
+// RegisterSpecHandlers serves the spec embedded in this file at
+// prefix/openapi.json and prefix/openapi.yaml, and its reference page at
+// prefix/docs. It returns an error, without registering anything, if the
+// embedded spec can't be loaded.
+func RegisterSpecHandlers(router EchoRouter, prefix string) error {
+    specHandler, err := SpecHandler()
+    if err != nil {
+        return err
+    }
+    handler := echo.WrapHandler(specHandler)
+    for _, path := range []string{"/openapi.json", "/openapi.yaml", "/docs"} {
+        router.GET(prefix+path, handler)
+        router.HEAD(prefix+path, handler)
+    }
+    return nil
+}
+{{else}}
+// RegisterSpecHandlers serves the spec embedded in this file at
+// prefix/openapi.json and prefix/openapi.yaml, and its reference page at
+// prefix/docs, with a chi.Router or an *http.ServeMux. It returns an error,
+// without registering anything, if the embedded spec can't be loaded.
+func RegisterSpecHandlers(mux interface{ Handle(string, http.Handler) }, prefix string) error {
+    handler, err := SpecHandler()
+    if err != nil {
+        return err
+    }
+    for _, path := range []string{"/openapi.json", "/openapi.yaml", "/docs"} {
+        mux.Handle(prefix+path, handler)
+    }
+    return nil
+}
+{{end}}
+`,
+	"std-http-handler.tmpl": `// Handler creates http.Handler with routing matching OpenAPI spec, serving
+// the paths under the ServerBasePath of the spec.
+func Handler(si ServerInterface) http.Handler {
+  return HandlerWithBaseURL(si, ServerBasePath)
+}
+
+// HandlerWithBaseURL creates http.Handler with routing matching OpenAPI spec,
+// and prepends baseURL to the paths, so that they can be served under a prefix.
+// Requests for unknown paths get a 404 response, and requests with a method
+// the path doesn't support get a 405 response.
+func HandlerWithBaseURL(si ServerInterface, baseURL string) http.Handler {
+{{if .}}wrapper := ServerInterfaceWrapper{
+        Handler: si,
+    }
 {{end}}
+  return runtime.NewRouter(baseURL, []runtime.Route{
+{{range .}}    {Method: "{{.Method}}", Path: "{{.Path}}", Handler: wrapper.{{.OperationId}}},
+{{end}}  })
+}
+
+// pathParam returns the value of a path parameter, as matched by the router.
+func pathParam(r *http.Request, name string) string {
+  return runtime.PathParam(r, name)
 }
+`,
+	"strict-chi.tmpl": `type strictHandler struct {
+    ssi     StrictServerInterface
+    options StrictHTTPServerOptions
+}
+
+// StrictHTTPServerOptions holds the options of the strict handler.
+type StrictHTTPServerOptions struct {
+    // ErrorHandler, if set, is called with the errors returned by the
+    // handlers, or met writing their responses, to write the response for
+    // them. Otherwise a bare 500 response is written, which doesn't disclose
+    // the error.
+    ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// NewStrictHandler returns a ServerInterface which decodes requests for the
+// given StrictServerInterface, and writes the responses it returns.
+func NewStrictHandler(ssi StrictServerInterface) ServerInterface {
+    return NewStrictHandlerWithOptions(ssi, StrictHTTPServerOptions{})
+}
+
+// NewStrictHandlerWithOptions is NewStrictHandler, with the given options.
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, options StrictHTTPServerOptions) ServerInterface {
+    return &strictHandler{ssi: ssi, options: options}
+}
+
+// handleError writes the response for an error of a handler, or of writing
+// its response.
+func (sh *strictHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+    if sh.options.ErrorHandler != nil {
+        sh.options.ErrorHandler(w, r, err)
+        return
+    }
+    http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+{{range .}}{{$opid := .OperationId}}
+// {{$opid}} operation adapter for the strict handler.
+func (sh *strictHandler) {{$opid}}(w http.ResponseWriter, r *http.Request{{genParamArgs .PathParams}}{{if .RequiresParamObject}}, params {{$opid}}Params{{end}}{{genBodyArgs .}}) {
+    var request {{$opid}}RequestObject
+{{range .PathParams}}
+    request.{{.GoFieldName}} = {{.GoVariableName}}
+{{- end}}
+{{- if .RequiresParamObject}}
+    request.Params = params
+{{- end}}
+{{range .Bodies}}
+{{- if .IsJSON}}
+    if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "{{.ContentType}}" {
+        var body {{$opid}}{{.NameTag}}RequestBody
+        if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+            http.Error(w, fmt.Sprintf("Error decoding {{.ContentType}} body: %s", err), http.StatusBadRequest)
+            return
+        }
+        request.{{.NameTag}}Body = &body
+    }
+{{- else}}
+    request.{{.NameTag}}Body = {{.ArgName}}
+{{- end}}
+{{- end}}
+{{- if and .BodyRequired .Bodies}}
+    if {{range $i, $body := .Bodies}}{{if $i}} && {{end}}request.{{$body.NameTag}}Body == nil{{end}} {
+        http.Error(w, "Request body is required, but not found", http.StatusBadRequest)
+        return
+    }
+{{- end}}
+
+    response, err := sh.ssi.{{$opid}}(r.Context(), request)
+    if err != nil {
+        sh.handleError(w, r, err)
+        return
+    }
+    if err := response.WriteResponse(w); err != nil {
+        sh.handleError(w, r, err)
+    }
+}
+{{end}}
+`,
+	"strict-echo.tmpl": `type strictHandler struct {
+    ssi StrictServerInterface
+}
+
+// NewStrictHandler returns a ServerInterface which decodes requests for the
+// given StrictServerInterface, and writes the responses it returns.
+func NewStrictHandler(ssi StrictServerInterface) ServerInterface {
+    return &strictHandler{ssi: ssi}
+}
+
+{{range .}}{{$opid := .OperationId}}
+// {{$opid}} operation adapter for the strict handler.
+func (sh *strictHandler) {{$opid}}(ctx echo.Context{{genParamArgs .PathParams}}{{if .RequiresParamObject}}, params {{$opid}}Params{{end}}{{genBodyArgs .}}) error {
+    var request {{$opid}}RequestObject
+{{range .PathParams}}
+    request.{{.GoFieldName}} = {{.GoVariableName}}
+{{- end}}
+{{- if .RequiresParamObject}}
+    request.Params = params
+{{- end}}
+{{range .Bodies}}
+{{- if .IsJSON}}
+    if mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType)); mediaType == "{{.ContentType}}" {
+        var body {{$opid}}{{.NameTag}}RequestBody
+        if err := json.NewDecoder(ctx.Request().Body).Decode(&body); err != nil {
+            return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Error decoding {{.ContentType}} body: %s", err))
+        }
+        request.{{.NameTag}}Body = &body
+    }
+{{- else}}
+    request.{{.NameTag}}Body = {{.ArgName}}
+{{- end}}
+{{- end}}
+{{- if and .BodyRequired .Bodies}}
+    if {{range $i, $body := .Bodies}}{{if $i}} && {{end}}request.{{$body.NameTag}}Body == nil{{end}} {
+        return echo.NewHTTPError(http.StatusBadRequest, "Request body is required, but not found")
+    }
+{{- end}}
+
+    reqCtx := ctx.Request().Context()
+{{- range .SecurityDefinitions}}
+    reqCtx = context.WithValue(reqCtx, "{{.ProviderName}}.Scopes", ctx.Get("{{.ProviderName}}.Scopes"))
+{{- end}}
+    response, err := sh.ssi.{{$opid}}(reqCtx, request)
+    if err != nil {
+        return err
+    }
+    return response.WriteResponse(ctx.Response())
+}
+{{end}}
+`,
+	"strict-interface.tmpl": `// StrictServerInterface represents all server handlers, which receive the
+// decoded request and return the response to send.
+type StrictServerInterface interface {
+{{range .}}{{.SummaryAsComment }}
+// ({{.Method}} {{.Path}})
+{{.OperationId}}(ctx context.Context, request {{.OperationId}}RequestObject) ({{.OperationId}}ResponseObject, error)
+{{end}}
+}
+
+{{range .}}{{$op := .}}{{$opid := .OperationId}}
+// {{$opid}}RequestObject holds the decoded request of {{$opid}}.
+type {{$opid}}RequestObject struct {
+{{- range .PathParams}}
+    {{.GoFieldName}} {{.TypeDef}}
+{{- end}}
+{{- if .RequiresParamObject}}
+    Params {{$opid}}Params
+{{- end}}
+{{- range .Bodies}}
+    // {{.NameTag}}Body is set when the request is sent as {{.ContentType}}.
+    {{.NameTag}}Body {{$op.BodyArgTypeDecl .}}
+{{- end}}
+}
+
+// {{$opid}}ResponseObject is returned by the {{$opid}} handler. The first
+// response which is set, in the order of the fields, is sent.
+type {{$opid}}ResponseObject struct {
+{{- range .StrictResponses}}
+    {{.FieldName}} {{.TypeDecl}}
+{{- end}}
+{{- range .ResponseHeaders}}
+    {{.FieldName}} *{{.TypeName}}
+{{- end}}
+{{- if .HasVariableStatusResponses}}
+
+    // StatusCode is the status sent for a response declared as a range,
+    // such as 2XX, or as default. It defaults to the first code of the
+    // range, or 500 for default.
+    StatusCode int
+{{- end}}
+}
+
+// WriteResponse writes the response which is set to w, along with the
+// headers of that response, if they're set.
+func (r {{$opid}}ResponseObject) WriteResponse(w http.ResponseWriter) error {
+    switch {
+{{- range .StrictResponses}}
+    case r.{{.FieldName}}{{if .HasContent}} != nil{{end}}:
+{{- if .IsFixedStatus}}
+        statusCode := {{.StatusCode}}
+{{- else}}
+        statusCode := r.StatusCode
+        if statusCode == 0 {
+            statusCode = {{.StatusCode}}
+        }
+{{- end}}
+{{- with .Headers}}{{$field := .FieldName}}
+        if r.{{$field}} != nil {
+{{- range .Headers}}
+{{- if .IndirectOptional}}
+            if r.{{$field}}.{{.GoName}} != nil {
+{{- else}}
+            {
+{{- end}}
+                value, err := runtime.StyleParam("simple", false, "{{.HeaderName}}", r.{{$field}}.{{.GoName}})
+                if err != nil {
+                    return fmt.Errorf("invalid value for response header {{.HeaderName}}: %s", err)
+                }
+                w.Header().Set("{{.HeaderName}}", value)
+            }
+{{- end}}
+        }
+{{- end}}
+{{- if .IsRaw}}
+{{- if .HasFixedContentType}}
+        w.Header().Set("Content-Type", "{{.ContentType}}")
+{{- end}}
+        w.WriteHeader(statusCode)
+        _, err := io.Copy(w, r.{{.FieldName}})
+        return err
+{{- else if .HasContent}}
+        buf, err := {{.Marshaler}}.Marshal(r.{{.FieldName}})
+        if err != nil {
+            return err
+        }
+        w.Header().Set("Content-Type", "{{.ContentType}}")
+        w.WriteHeader(statusCode)
+        _, err = w.Write(buf)
+        return err
+{{- else}}
+        w.WriteHeader(statusCode)
+        return nil
+{{- end}}
+{{- end}}
+    }
+    return fmt.Errorf("no response set for {{$opid}}")
+}
+{{end}}
 `,
 	"typedef.tmpl": `{{range .Types}}
 // {{.TypeName}} defines model for {{.JsonName}}.
@@ -795,15 +1612,102 @@ const (
 )
 {{- end }}
 {{end}}
+`,
+	"validator.tmpl": `var (
+    specRouterOnce sync.Once
+    specRouter     *middleware.Router
+    specRouterErr  error
+)
+
+// getSpecRouter returns the router of the spec embedded in this file, which
+// is compiled the first time it's needed, and shared by the validators. It
+// matches the paths under the ServerBasePath, where the handlers are
+// registered, rather than the servers of the spec.
+func getSpecRouter() (*middleware.Router, error) {
+    specRouterOnce.Do(func() {
+        swagger, err := GetSwagger()
+        if err != nil {
+            specRouterErr = err
+            return
+        }
+        swagger.Servers = nil
+        if ServerBasePath != "" {
+            swagger.Servers = openapi3.Servers{ {URL: ServerBasePath} }
+        }
+        specRouter, specRouterErr = middleware.NewRouter(swagger)
+    })
+    return specRouter, specRouterErr
+}
+
+// ValidateRequest validates a request against the spec embedded in this
+// file, returning a *middleware.RequestValidationError if it's invalid.
+func ValidateRequest(r *http.Request) error {
+    return ValidateRequestWithOptions(r, nil)
+}
+
+// ValidateRequestWithOptions validates a request against the spec embedded
+// in this file, with validation options, which authenticate requests for
+// operations with security requirements.
+func ValidateRequestWithOptions(r *http.Request, options *middleware.Options) error {
+    router, err := getSpecRouter()
+    if err != nil {
+        return err
+    }
+    _, _, err = middleware.ValidateRequest(r.Context(), r, router, options)
+    return err
+}
+{{if .Echo}}
+// Validator returns the middleware which validates requests against the
+// spec embedded in this file, before they reach the handlers. It returns an
+// error if the embedded spec can't be loaded.
+func Validator() (echo.MiddlewareFunc, error) {
+    return ValidatorWithOptions(nil)
+}
+
+// ValidatorWithOptions returns the middleware which validates requests
+// against the spec embedded in this file, with validation options. It
+// returns an error if the embedded spec can't be loaded.
+func ValidatorWithOptions(options *middleware.Options) (echo.MiddlewareFunc, error) {
+    router, err := getSpecRouter()
+    if err != nil {
+        return nil, err
+    }
+    return middleware.OapiRequestValidatorWithRouter(router, options), nil
+}
+{{else}}
+// Validator returns the middleware which validates requests against the
+// spec embedded in this file, before they reach the handlers. It returns an
+// error if the embedded spec can't be loaded.
+func Validator() (func(http.Handler) http.Handler, error) {
+    return ValidatorWithOptions(nil)
+}
+
+// ValidatorWithOptions returns the middleware which validates requests
+// against the spec embedded in this file, with validation options. It
+// returns an error if the embedded spec can't be loaded.
+func ValidatorWithOptions(options *middleware.Options) (func(http.Handler) http.Handler, error) {
+    router, err := getSpecRouter()
+    if err != nil {
+        return nil, err
+    }
+    return middleware.OapiRequestValidatorHTTPWithRouter(router, options), nil
+}
+{{end}}
 `,
 	"wrappers.tmpl": `// ServerInterfaceWrapper converts echo contexts to parameters.
 type ServerInterfaceWrapper struct {
     Handler ServerInterface
 }
 
-{{range .}}{{$opid := .OperationId}}// {{$opid}} converts echo context to params.
+{{range .}}{{$op := .}}{{$opid := .OperationId}}// {{$opid}} converts echo context to params.
 func (w *ServerInterfaceWrapper) {{.OperationId}} (ctx echo.Context) error {
     var err error
+{{if .Deprecated}}
+    ctx.Response().Header().Set("Deprecation", "true")
+{{- end}}
+{{- if .Sunset}}
+    ctx.Response().Header().Set("Sunset", "{{.Sunset}}")
+{{- end}}
 {{range .PathParams}}// ------------- Path parameter "{{.ParamName}}" -------------
     var {{$varName := .GoVariableName}}{{$varName}} {{.TypeDef}}
 {{if .IsPassThrough}}
@@ -812,13 +1716,13 @@ func (w *ServerInterfaceWrapper) {{.OperationId}} (ctx echo.Context) error {
 {{if .IsJson}}
     err = json.Unmarshal([]byte(ctx.Param("{{.ParamName}}")), &{{$varName}})
     if err != nil {
-        return echo.NewHTTPError(http.StatusBadRequest, "Error unmarshaling parameter '{{.ParamName}}' as JSON")
+        return badRequest(ctx, "path", "{{.ParamName}}", "Error unmarshaling parameter '{{.ParamName}}' as JSON")
     }
 {{end}}
 {{if .IsStyled}}
     err = runtime.BindStyledParameter("{{.Style}}",{{.Explode}}, "{{.ParamName}}", ctx.Param("{{.ParamName}}"), &{{$varName}})
     if err != nil {
-        return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
+        return badRequest(ctx, "path", "{{.ParamName}}", fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
     }
 {{end}}
 {{end}}
@@ -834,7 +1738,7 @@ func (w *ServerInterfaceWrapper) {{.OperationId}} (ctx echo.Context) error {
     {{if .IsStyled}}
     err = runtime.BindQueryParameter("{{.Style}}", {{.Explode}}, {{.Required}}, "{{.ParamName}}", ctx.QueryParams(), &params.{{.GoName}})
     if err != nil {
-        return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
+        return badRequest(ctx, "query", "{{.ParamName}}", fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
     }
     {{else}}
     if paramValue := ctx.QueryParam("{{.ParamName}}"); paramValue != "" {
@@ -845,12 +1749,12 @@ func (w *ServerInterfaceWrapper) {{.OperationId}} (ctx echo.Context) error {
     var value {{.TypeDef}}
     err = json.Unmarshal([]byte(paramValue), &value)
     if err != nil {
-        return echo.NewHTTPError(http.StatusBadRequest, "Error unmarshaling parameter '{{.ParamName}}' as JSON")
+        return badRequest(ctx, "query", "{{.ParamName}}", "Error unmarshaling parameter '{{.ParamName}}' as JSON")
     }
     params.{{.GoName}} = {{if not .Required}}&{{end}}value
     {{end}}
     }{{if .Required}} else {
-        return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query argument {{.ParamName}} is required, but not found"))
+        return badRequest(ctx, "query", "{{.ParamName}}", "Query argument {{.ParamName}} is required, but not found")
     }{{end}}
     {{end}}
 {{end}}
@@ -862,7 +1766,7 @@ func (w *ServerInterfaceWrapper) {{.OperationId}} (ctx echo.Context) error {
         var {{.GoName}} {{.TypeDef}}
         n := len(valueList)
         if n != 1 {
-            return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for {{.ParamName}}, got %d", n))
+            return badRequest(ctx, "header", "{{.ParamName}}", fmt.Sprintf("Expected one value for {{.ParamName}}, got %d", n))
         }
 {{if .IsPassThrough}}
         params.{{.GoName}} = {{if not .Required}}&{{end}}valueList[0]
@@ -870,18 +1774,18 @@ func (w *ServerInterfaceWrapper) {{.OperationId}} (ctx echo.Context) error {
 {{if .IsJson}}
         err = json.Unmarshal([]byte(valueList[0]), &{{.GoName}})
         if err != nil {
-            return echo.NewHTTPError(http.StatusBadRequest, "Error unmarshaling parameter '{{.ParamName}}' as JSON")
+            return badRequest(ctx, "header", "{{.ParamName}}", "Error unmarshaling parameter '{{.ParamName}}' as JSON")
         }
 {{end}}
 {{if .IsStyled}}
         err = runtime.BindStyledParameter("{{.Style}}",{{.Explode}}, "{{.ParamName}}", valueList[0], &{{.GoName}})
         if err != nil {
-            return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
+            return badRequest(ctx, "header", "{{.ParamName}}", fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
         }
 {{end}}
         params.{{.GoName}} = {{if not .Required}}&{{end}}{{.GoName}}
         } {{if .Required}}else {
-            return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter {{.ParamName}} is required, but not found"))
+            return badRequest(ctx, "header", "{{.ParamName}}", "Header parameter {{.ParamName}} is required, but not found")
         }{{end}}
 {{end}}
 {{end}}
@@ -896,11 +1800,11 @@ func (w *ServerInterfaceWrapper) {{.OperationId}} (ctx echo.Context) error {
     var decoded string
     decoded, err := url.QueryUnescape(cookie.Value)
     if err != nil {
-        return echo.NewHTTPError(http.StatusBadRequest, "Error unescaping cookie parameter '{{.ParamName}}'")
+        return badRequest(ctx, "cookie", "{{.ParamName}}", "Error unescaping cookie parameter '{{.ParamName}}'")
     }
     err = json.Unmarshal([]byte(decoded), &value)
     if err != nil {
-        return echo.NewHTTPError(http.StatusBadRequest, "Error unmarshaling parameter '{{.ParamName}}' as JSON")
+        return badRequest(ctx, "cookie", "{{.ParamName}}", "Error unmarshaling parameter '{{.ParamName}}' as JSON")
     }
     params.{{.GoName}} = {{if not .Required}}&{{end}}value
     {{end}}
@@ -908,19 +1812,61 @@ func (w *ServerInterfaceWrapper) {{.OperationId}} (ctx echo.Context) error {
     var value {{.TypeDef}}
     err = runtime.BindStyledParameter("simple",{{.Explode}}, "{{.ParamName}}", cookie.Value, &value)
     if err != nil {
-        return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
+        return badRequest(ctx, "cookie", "{{.ParamName}}", fmt.Sprintf("Invalid format for parameter {{.ParamName}}: %s", err))
     }
     params.{{.GoName}} = {{if not .Required}}&{{end}}value
     {{end}}
     }{{if .Required}} else {
-        return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query argument {{.ParamName}} is required, but not found"))
+        return badRequest(ctx, "cookie", "{{.ParamName}}", "Query argument {{.ParamName}} is required, but not found")
     }{{end}}
 
 {{end}}{{/* .CookieParams */}}
 
 {{end}}{{/* .RequiresParamObject */}}
+{{range .DecodedBodies}}
+    // ------------- {{.ContentType}} body -------------
+    var {{.ArgName}} {{$op.BodyArgTypeDecl .}}
+    if mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType)); mediaType == "{{.ContentType}}" {
+{{- if .HasFileParts}}
+        // The parts are streamed to the handler, which reads them in order.
+        mr, err := runtime.NewMultipartReader(ctx.Request(), {{genMultipartEncodings .Encodings}})
+        if err != nil {
+            return badRequest(ctx, "body", "", fmt.Sprintf("Error reading {{.ContentType}} body: %s", err))
+        }
+        {{.ArgName}} = mr
+{{- else}}
+        var body {{$opid}}{{.NameTag}}RequestBody
+{{- if .IsFormEncoded}}
+        if err := ctx.Request().ParseForm(); err != nil {
+            return badRequest(ctx, "body", "", fmt.Sprintf("Error parsing {{.ContentType}} body: %s", err))
+        }
+        if err := runtime.BindForm(ctx.Request().PostForm, &body); err != nil {
+            return badRequest(ctx, "body", "", fmt.Sprintf("Invalid format for {{.ContentType}} body: %s", err))
+        }
+{{- else if .IsText}}
+        buf, err := ioutil.ReadAll(ctx.Request().Body)
+        if err != nil {
+            return badRequest(ctx, "body", "", fmt.Sprintf("Error reading {{.ContentType}} body: %s", err))
+        }
+        body = {{$opid}}{{.NameTag}}RequestBody(buf)
+{{- else if .IsBinary}}
+        body = ctx.Request().Body
+{{- else if .IsMultipart}}
+        // The whole body is parsed before the handler is called, spooling
+        // parts beyond runtime.MaxMultipartMemory to temporary files.
+        if err := ctx.Request().ParseMultipartForm(runtime.MaxMultipartMemory); err != nil {
+            return badRequest(ctx, "body", "", fmt.Sprintf("Error parsing {{.ContentType}} body: %s", err))
+        }
+        if err := runtime.BindMultipart(ctx.Request().MultipartForm, &body, {{genMultipartEncodings .Encodings}}); err != nil {
+            return badRequest(ctx, "body", "", fmt.Sprintf("Invalid format for {{.ContentType}} body: %s", err))
+        }
+{{- end}}
+        {{.ArgName}} = {{if not .IsBinary}}&{{end}}body
+{{- end}}
+    }
+{{end}}
     // Invoke the callback with all the unmarshalled arguments
-    err = w.Handler.{{.OperationId}}(ctx{{genParamNames .PathParams}}{{if .RequiresParamObject}}, params{{end}})
+    err = w.Handler.{{.OperationId}}(ctx{{genParamNames .PathParams}}{{if .RequiresParamObject}}, params{{end}}{{genBodyNames .}})
     return err
 }
 {{end}}
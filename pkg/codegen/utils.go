@@ -159,6 +159,42 @@ func SortedStringKeys(dict map[string]string) []string {
 	return keys
 }
 
+// This returns bool map keys in sorted order
+func SortedBoolKeys(dict map[string]bool) []string {
+	keys := make([]string, len(dict))
+	i := 0
+	for key := range dict {
+		keys[i] = key
+		i++
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// This returns sorted keys for a HeaderRef dict
+func SortedHeaderKeys(dict map[string]*openapi3.HeaderRef) []string {
+	keys := make([]string, len(dict))
+	i := 0
+	for key := range dict {
+		keys[i] = key
+		i++
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// This returns sorted keys for the encodings of a media type
+func SortedEncodingKeys(dict map[string]*openapi3.Encoding) []string {
+	keys := make([]string, len(dict))
+	i := 0
+	for key := range dict {
+		keys[i] = key
+		i++
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // This returns sorted keys for a ParameterRef dict
 func SortedParameterKeys(dict map[string]*openapi3.ParameterRef) []string {
 	keys := make([]string, len(dict))
@@ -201,12 +237,21 @@ func StringInArray(str string, array []string) bool {
 // URL components (http://deepmap.com/schemas/document.json#Foo) are supported if they present in --import-mapping
 //
 func RefPathToGoType(refPath string) (string, error) {
+	return typeNameOverrides(nil).refPathToGoType(refPath)
+}
+
+// refPathToGoType is RefPathToGoType, for a spec whose local components may
+// have been renamed to avoid collisions.
+func (o typeNameOverrides) refPathToGoType(refPath string) (string, error) {
 	if refPath[0] == '#' {
-		pathParts := strings.Split(refPath, "/")
-		if depth := len(pathParts); depth != 4 {
-			return "", fmt.Errorf("Parameter nesting is deeper than supported: %s has %d", refPath, depth)
+		goType, err := localRefPathToGoType(refPath)
+		if err != nil {
+			return "", err
+		}
+		if override, found := o[refPath]; found {
+			return override, nil
 		}
-		return SchemaNameToTypeName(pathParts[3]), nil
+		return goType, nil
 	}
 	pathParts := strings.Split(refPath, "#")
 	if len(pathParts) != 2 {
@@ -216,7 +261,7 @@ func RefPathToGoType(refPath string) (string, error) {
 	if goImport, ok := importMapping[remoteComponent]; !ok {
 		return "", fmt.Errorf("unrecognized external reference '%s'; please provide the known import for this reference using option --import-mapping", remoteComponent)
 	} else {
-		goType, err := RefPathToGoType("#" + flatComponent)
+		goType, err := localRefPathToGoType("#" + flatComponent)
 		if err != nil {
 			return "", err
 		}
@@ -224,6 +269,17 @@ func RefPathToGoType(refPath string) (string, error) {
 	}
 }
 
+// This converts a reference within a single document, such as
+// #/components/schemas/Foo, to a Go type name, without regard to which
+// document it is in.
+func localRefPathToGoType(refPath string) (string, error) {
+	pathParts := strings.Split(refPath, "/")
+	if depth := len(pathParts); depth != 4 {
+		return "", fmt.Errorf("Parameter nesting is deeper than supported: %s has %d", refPath, depth)
+	}
+	return SchemaNameToTypeName(pathParts[3]), nil
+}
+
 // This function converts a swagger style path URI with parameters to a
 // Echo compatible path URI. We need to replace all of Swagger parameters with
 // ":param". Valid input parameters are:
@@ -493,14 +549,15 @@ func SchemaNameToTypeName(name string) string {
 // If it's false, no additional properties are allowed. We're going to act a little
 // differently, in that if you want additionalProperties code to be generated,
 // you must specify an additionalProperties type
-// If additionalProperties it true/false, this field will be non-nil.
+// If additionalProperties it true/false, this field will be non-nil. The
+// loader sets it to false for schemas too, so the schema is checked first.
 func SchemaHasAdditionalProperties(schema *openapi3.Schema) bool {
-	if schema.AdditionalPropertiesAllowed != nil {
-		return *schema.AdditionalPropertiesAllowed
-	}
 	if schema.AdditionalProperties != nil {
 		return true
 	}
+	if schema.AdditionalPropertiesAllowed != nil {
+		return *schema.AdditionalPropertiesAllowed
+	}
 	return false
 }
 
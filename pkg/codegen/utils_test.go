@@ -233,3 +233,31 @@ Line
 	}
 
 }
+
+func TestSchemaHasAdditionalProperties(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(`
+openapi: 3.0.1
+info:
+  title: Additional properties
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Typed:
+      additionalProperties:
+        type: integer
+    Any:
+      additionalProperties: true
+    None:
+      additionalProperties: false
+    Unset:
+      type: object
+`))
+	assert.NoError(t, err)
+
+	schemas := swagger.Components.Schemas
+	assert.True(t, SchemaHasAdditionalProperties(schemas["Typed"].Value))
+	assert.True(t, SchemaHasAdditionalProperties(schemas["Any"].Value))
+	assert.False(t, SchemaHasAdditionalProperties(schemas["None"].Value))
+	assert.False(t, SchemaHasAdditionalProperties(schemas["Unset"].Value))
+}
@@ -0,0 +1,281 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+)
+
+const PrincipalContextKey = "oapi-codegen/principal"
+
+// authenticationContextKey holds the *authentication of a request while it's
+// validated.
+const authenticationContextKey = "oapi-codegen/authentication"
+
+// ErrMissingCredentials is returned, wrapped in an *AuthenticationError, for
+// requests without the credentials of a security scheme.
+var ErrMissingCredentials = errors.New("missing credentials")
+
+// Credentials are the credentials of a request for a security scheme, which
+// are decoded according to the type of the scheme.
+type Credentials struct {
+	SchemeName string
+	Scheme     *openapi3.SecurityScheme
+
+	// The scopes which the security requirement of the operation asks for
+	Scopes []string
+
+	// The key of apiKey schemes
+	APIKey string
+
+	// The token of http bearer, oauth2 and openIdConnect schemes, or the
+	// credentials following the scheme in the Authorization header for other
+	// http schemes
+	Token string
+
+	// The credentials of http basic schemes
+	Username string
+	Password string
+}
+
+// Authenticator authenticates the credentials of requests for a security
+// scheme, checking that they grant the required scopes, and returns the
+// authenticated principal. Errors are returned as they are when they're an
+// *AuthenticationError or an *echo.HTTPError, and turned into a 401
+// otherwise.
+type Authenticator interface {
+	Authenticate(ctx context.Context, credentials *Credentials) (interface{}, error)
+}
+
+// AuthenticatorFunc is a function which implements Authenticator.
+type AuthenticatorFunc func(ctx context.Context, credentials *Credentials) (interface{}, error)
+
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, credentials *Credentials) (interface{}, error) {
+	return f(ctx, credentials)
+}
+
+// Authenticators maps the names of security schemes to their authenticators.
+type Authenticators map[string]Authenticator
+
+// AuthenticationError is the error of a request which fails authentication
+// for a security scheme, with the status to respond with.
+type AuthenticationError struct {
+	StatusCode int
+	SchemeName string
+	Err        error
+}
+
+func (e *AuthenticationError) Error() string {
+	return fmt.Sprintf("security scheme %s: %s", e.SchemeName, e.Err)
+}
+
+func (e *AuthenticationError) Unwrap() error {
+	return e.Err
+}
+
+// authentication holds the principals authenticated for a request, by the
+// name of their scheme, in the order in which they were authenticated.
+type authentication struct {
+	schemes    []string
+	principals map[string]interface{}
+}
+
+// withAuthentication returns ctx with an authentication to hold the
+// principals of a request, unless it already has one.
+func withAuthentication(ctx context.Context) (context.Context, *authentication) {
+	if auth, ok := ctx.Value(authenticationContextKey).(*authentication); ok {
+		return ctx, auth
+	}
+	auth := &authentication{}
+	return context.WithValue(ctx, authenticationContextKey, auth), auth
+}
+
+// withPrincipals returns ctx with the principals of auth, unless there are
+// none.
+func (auth *authentication) withPrincipals(ctx context.Context) context.Context {
+	if len(auth.schemes) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, PrincipalContextKey, auth)
+}
+
+// principal returns the principal of the first scheme authenticated, which
+// is the first of the requirement in alphabetical order, since that's the
+// order in which openapi3filter authenticates them.
+func (auth *authentication) principal() interface{} {
+	return auth.principals[auth.schemes[0]]
+}
+
+// authenticationFunc returns the openapi3filter.AuthenticationFunc which
+// authenticates requests with the authenticators, falling back to fallback
+// for the schemes which have none.
+func (authenticators Authenticators) authenticationFunc(fallback func(context.Context, *openapi3filter.AuthenticationInput) error) func(context.Context, *openapi3filter.AuthenticationInput) error {
+	return func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		_, auth := withAuthentication(ctx)
+		authenticator, ok := authenticators[input.SecuritySchemeName]
+		var principal interface{}
+		var err error
+		switch {
+		case ok:
+			principal, err = authenticate(ctx, authenticator, input)
+		case fallback == nil:
+			err = fmt.Errorf("no authenticator for security scheme %s", input.SecuritySchemeName)
+		default:
+			err = fallback(ctx, input)
+		}
+		if err != nil {
+			// The requirement which the scheme belongs to fails, whichever
+			// function authenticated it, so the principals of its other
+			// schemes don't count.
+			auth.schemes = nil
+			auth.principals = nil
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		if auth.principals == nil {
+			auth.principals = make(map[string]interface{})
+		}
+		auth.schemes = append(auth.schemes, input.SecuritySchemeName)
+		auth.principals[input.SecuritySchemeName] = principal
+		return nil
+	}
+}
+
+func authenticate(ctx context.Context, authenticator Authenticator, input *openapi3filter.AuthenticationInput) (interface{}, error) {
+	credentials, err := decodeCredentials(input)
+	if err == nil {
+		var principal interface{}
+		principal, err = authenticator.Authenticate(ctx, credentials)
+		if err == nil {
+			return principal, nil
+		}
+	}
+	if isHTTPError(err) {
+		return nil, err
+	}
+	return nil, &AuthenticationError{
+		StatusCode: http.StatusUnauthorized,
+		SchemeName: input.SecuritySchemeName,
+		Err:        err,
+	}
+}
+
+// isHTTPError returns whether err carries the status to respond with.
+func isHTTPError(err error) bool {
+	switch err.(type) {
+	case *AuthenticationError, *echo.HTTPError:
+		return true
+	}
+	return false
+}
+
+// decodeCredentials decodes the credentials of a request for the scheme of
+// input, returning ErrMissingCredentials when there are none.
+func decodeCredentials(input *openapi3filter.AuthenticationInput) (*Credentials, error) {
+	req := input.RequestValidationInput.Request
+	scheme := input.SecurityScheme
+	credentials := &Credentials{
+		SchemeName: input.SecuritySchemeName,
+		Scheme:     scheme,
+		Scopes:     input.Scopes,
+	}
+
+	switch scheme.Type {
+	case "apiKey":
+		switch scheme.In {
+		case "header":
+			credentials.APIKey = req.Header.Get(scheme.Name)
+		case "query":
+			credentials.APIKey = req.URL.Query().Get(scheme.Name)
+		case "cookie":
+			if cookie, err := req.Cookie(scheme.Name); err == nil {
+				credentials.APIKey = cookie.Value
+			}
+		}
+		if credentials.APIKey == "" {
+			return nil, ErrMissingCredentials
+		}
+	case "http":
+		if strings.EqualFold(scheme.Scheme, "basic") {
+			username, password, ok := req.BasicAuth()
+			if !ok {
+				return nil, ErrMissingCredentials
+			}
+			credentials.Username = username
+			credentials.Password = password
+			break
+		}
+		token, ok := authorizationCredentials(req, scheme.Scheme)
+		if !ok {
+			return nil, ErrMissingCredentials
+		}
+		credentials.Token = token
+	case "oauth2", "openIdConnect":
+		token, ok := authorizationCredentials(req, "bearer")
+		if !ok {
+			return nil, ErrMissingCredentials
+		}
+		credentials.Token = token
+	default:
+		return nil, fmt.Errorf("unsupported security scheme type %s", scheme.Type)
+	}
+	return credentials, nil
+}
+
+// authorizationCredentials returns the credentials in the Authorization
+// header of req, when they're for the given scheme.
+func authorizationCredentials(req *http.Request, scheme string) (string, bool) {
+	authorization := req.Header.Get("Authorization")
+	prefix := scheme + " "
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(authorization[len(prefix):]), true
+}
+
+// GetPrincipal returns the principal authenticated for a request by the
+// validator, from the context of the request. When the security requirement
+// has several schemes with authenticators, it's the principal of the first
+// one by name in alphabetical order, rather than in the order of the spec,
+// since openapi3filter sorts them. It returns nil if no principal was
+// authenticated.
+func GetPrincipal(c context.Context) interface{} {
+	auth, ok := c.Value(PrincipalContextKey).(*authentication)
+	if !ok {
+		return nil
+	}
+	return auth.principal()
+}
+
+// GetSchemePrincipal returns the principal authenticated for a request with
+// the given security scheme, from the context of the request. It returns nil
+// if the request wasn't authenticated with the scheme.
+func GetSchemePrincipal(c context.Context, schemeName string) interface{} {
+	auth, ok := c.Value(PrincipalContextKey).(*authentication)
+	if !ok {
+		return nil
+	}
+	return auth.principals[schemeName]
+}
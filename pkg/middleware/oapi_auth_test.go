@@ -0,0 +1,232 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/go-chi/chi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAuthSchema = `openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /bearer:
+    get:
+      security:
+        - BearerAuth: []
+      responses:
+        '200':
+          description: success
+  /scoped:
+    get:
+      security:
+        - OAuth: [admin]
+      responses:
+        '200':
+          description: success
+  /either:
+    get:
+      security:
+        - BasicAuth: []
+        - ApiKeyAuth: []
+      responses:
+        '200':
+          description: success
+  /both:
+    get:
+      security:
+        - ApiKeyAuth: []
+          BasicAuth: []
+      responses:
+        '200':
+          description: success
+  /mixed:
+    get:
+      security:
+        - ApiKeyAuth: []
+          OtherAuth: []
+        - BearerAuth: []
+      responses:
+        '200':
+          description: success
+  /other:
+    get:
+      security:
+        - OtherAuth: []
+      responses:
+        '200':
+          description: success
+components:
+  securitySchemes:
+    BearerAuth:
+      type: http
+      scheme: bearer
+    BasicAuth:
+      type: http
+      scheme: basic
+    ApiKeyAuth:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    OAuth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://example.com/token
+          scopes:
+            admin: Administration
+    OtherAuth:
+      type: apiKey
+      in: query
+      name: key
+`
+
+func testAuthOptions() *Options {
+	return &Options{
+		Authenticators: Authenticators{
+			"BearerAuth": AuthenticatorFunc(func(ctx context.Context, credentials *Credentials) (interface{}, error) {
+				if credentials.Token != "secret" {
+					return nil, errors.New("invalid token")
+				}
+				return "bearer-user", nil
+			}),
+			"OAuth": AuthenticatorFunc(func(ctx context.Context, credentials *Credentials) (interface{}, error) {
+				if credentials.Token != "admin-token" {
+					return nil, &AuthenticationError{
+						StatusCode: http.StatusForbidden,
+						SchemeName: credentials.SchemeName,
+						Err:        fmt.Errorf("token lacks scopes %v", credentials.Scopes),
+					}
+				}
+				return "admin", nil
+			}),
+			"BasicAuth": AuthenticatorFunc(func(ctx context.Context, credentials *Credentials) (interface{}, error) {
+				if credentials.Username != "marcin" || credentials.Password != "pass" {
+					return nil, errors.New("invalid password")
+				}
+				return "basic-" + credentials.Username, nil
+			}),
+			"ApiKeyAuth": AuthenticatorFunc(func(ctx context.Context, credentials *Credentials) (interface{}, error) {
+				return "key-" + credentials.APIKey, nil
+			}),
+		},
+		Options: openapi3filter.Options{
+			AuthenticationFunc: func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+				if input.RequestValidationInput.Request.URL.Query().Get("key") == "other" {
+					return nil
+				}
+				return errors.New("forbidden")
+			},
+		},
+	}
+}
+
+type authTestCase struct {
+	path           string
+	header         http.Header
+	status         int
+	principal      interface{}
+	basicPrincipal interface{}
+}
+
+var authTestCases = []authTestCase{
+	{path: "/bearer", header: http.Header{"Authorization": {"Bearer secret"}}, status: http.StatusOK, principal: "bearer-user"},
+	{path: "/bearer", header: http.Header{"Authorization": {"Bearer wrong"}}, status: http.StatusUnauthorized},
+	{path: "/bearer", status: http.StatusUnauthorized},
+	{path: "/scoped", header: http.Header{"Authorization": {"Bearer admin-token"}}, status: http.StatusOK, principal: "admin"},
+	{path: "/scoped", header: http.Header{"Authorization": {"Bearer user-token"}}, status: http.StatusForbidden},
+	// The principals of failed requirements don't count.
+	{path: "/either", header: http.Header{"Authorization": {"Basic bWFyY2luOnBhc3M="}}, status: http.StatusOK, principal: "basic-marcin", basicPrincipal: "basic-marcin"},
+	{path: "/either", header: http.Header{"X-Api-Key": {"k"}}, status: http.StatusOK, principal: "key-k"},
+	{path: "/both", header: http.Header{"X-Api-Key": {"k"}, "Authorization": {"Basic bWFyY2luOnBhc3M="}}, status: http.StatusOK, principal: "key-k", basicPrincipal: "basic-marcin"},
+	{path: "/both", header: http.Header{"X-Api-Key": {"k"}}, status: http.StatusUnauthorized},
+	// Schemes without authenticators fall back to the AuthenticationFunc,
+	// and when it fails, so do their requirements.
+	{path: "/mixed?key=other", header: http.Header{"X-Api-Key": {"k"}}, status: http.StatusOK, principal: "key-k"},
+	{path: "/mixed", header: http.Header{"X-Api-Key": {"k"}, "Authorization": {"Bearer secret"}}, status: http.StatusOK, principal: "bearer-user"},
+	{path: "/other?key=other", status: http.StatusOK},
+	{path: "/other", status: http.StatusForbidden},
+}
+
+func doAuthRequest(handler http.Handler, tc authTestCase) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+	for name, values := range tc.header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestOapiRequestValidatorAuthenticators(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testAuthSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	var principal, basicPrincipal, echoPrincipal interface{}
+	e := echo.New()
+	e.Use(OapiRequestValidatorWithOptions(swagger, testAuthOptions()))
+	e.GET("/*", func(c echo.Context) error {
+		principal = GetPrincipal(c.Request().Context())
+		basicPrincipal = GetSchemePrincipal(c.Request().Context(), "BasicAuth")
+		echoPrincipal = c.Get(PrincipalContextKey)
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, tc := range authTestCases {
+		principal, basicPrincipal, echoPrincipal = nil, nil, nil
+		rec := doAuthRequest(e, tc)
+		assert.Equal(t, tc.status, rec.Code, tc.path)
+		assert.Equal(t, tc.principal, principal, tc.path)
+		assert.Equal(t, tc.principal, echoPrincipal, tc.path)
+		assert.Equal(t, tc.basicPrincipal, basicPrincipal, tc.path)
+	}
+}
+
+func TestOapiRequestValidatorHTTPAuthenticators(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testAuthSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	var principal, basicPrincipal interface{}
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorHTTPWithOptions(swagger, testAuthOptions()))
+	r.Get("/*", func(w http.ResponseWriter, r *http.Request) {
+		principal = GetPrincipal(r.Context())
+		basicPrincipal = GetSchemePrincipal(r.Context(), "BasicAuth")
+	})
+
+	for _, tc := range authTestCases {
+		principal, basicPrincipal = nil, nil
+		rec := doAuthRequest(r, tc)
+		assert.Equal(t, tc.status, rec.Code, tc.path)
+		assert.Equal(t, tc.principal, principal, tc.path)
+		assert.Equal(t, tc.basicPrincipal, basicPrincipal, tc.path)
+	}
+
+	rec := doAuthRequest(r, authTestCase{path: "/bearer"})
+	assert.Contains(t, rec.Body.String(), "security scheme BearerAuth: missing credentials")
+}
@@ -0,0 +1,348 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultBodyDecoders are the decoders which the validators use for the
+// bodies of these content types, unless the BodyDecoders of the Options
+// replace them. Bodies of other content types, such as JSON, are decoded by
+// openapi3filter.
+var DefaultBodyDecoders = map[string]openapi3filter.BodyDecoder{
+	"application/x-www-form-urlencoded": FormBodyDecoder,
+	"multipart/form-data":               MultipartBodyDecoder,
+	"application/yaml":                  YAMLBodyDecoder,
+	"text/plain":                        PlainTextBodyDecoder,
+}
+
+// bodyDecoder returns the decoder for bodies of the given content type, or
+// nil if they're left to openapi3filter.
+func (o *Options) bodyDecoder(contentType string) openapi3filter.BodyDecoder {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	if o != nil {
+		if decoder, ok := o.BodyDecoders[mediaType]; ok {
+			return decoder
+		}
+	}
+	return DefaultBodyDecoders[mediaType]
+}
+
+// validateRequestBody validates the body of a request against its schema,
+// like openapi3filter.ValidateRequestBody, but decodes it with decoder.
+func validateRequestBody(input *openapi3filter.RequestValidationInput, requestBody *openapi3.RequestBody, decoder openapi3filter.BodyDecoder) error {
+	req := input.Request
+	var data []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		data, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return &openapi3filter.RequestError{Input: input, RequestBody: requestBody, Reason: "reading failed", Err: err}
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+
+	if len(data) == 0 {
+		if requestBody.Required {
+			return &openapi3filter.RequestError{Input: input, RequestBody: requestBody, Err: openapi3filter.ErrInvalidRequired}
+		}
+		return nil
+	}
+	if len(requestBody.Content) == 0 {
+		return nil
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	content := requestBody.Content.Get(contentType)
+	if content == nil {
+		return &openapi3filter.RequestError{
+			Input:       input,
+			RequestBody: requestBody,
+			Reason:      fmt.Sprintf("header 'Content-Type' has unexpected value: %q", contentType),
+		}
+	}
+	if content.Schema == nil {
+		return nil
+	}
+
+	encodingFn := func(name string) *openapi3.Encoding { return content.Encoding[name] }
+	value, err := decoder(bytes.NewReader(data), req.Header, content.Schema, encodingFn)
+	if err != nil {
+		return &openapi3filter.RequestError{Input: input, RequestBody: requestBody, Reason: "failed to decode request body", Err: err}
+	}
+	if err := content.Schema.Value.VisitJSON(value); err != nil {
+		return &openapi3filter.RequestError{Input: input, RequestBody: requestBody, Reason: "doesn't match the schema", Err: err}
+	}
+	return nil
+}
+
+// PlainTextBodyDecoder decodes a body as a string.
+func PlainTextBodyDecoder(body io.Reader, header http.Header, schema *openapi3.SchemaRef, encodingFn openapi3filter.EncodingFn) (interface{}, error) {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+	}
+	return string(data), nil
+}
+
+// YAMLBodyDecoder decodes a YAML body into the values which the same
+// document in JSON decodes into.
+func YAMLBodyDecoder(body io.Reader, header http.Header, schema *openapi3.SchemaRef, encodingFn openapi3filter.EncodingFn) (interface{}, error) {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+	}
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+	}
+	return jsonValue(value), nil
+}
+
+// jsonValue converts a value decoded from YAML into the value decoded from
+// the same JSON.
+func jsonValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			object[fmt.Sprint(k)] = jsonValue(v)
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, v := range value {
+			array[i] = jsonValue(v)
+		}
+		return array
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case uint64:
+		return float64(value)
+	}
+	return value
+}
+
+// FormBodyDecoder decodes an application/x-www-form-urlencoded body into an
+// object, whose properties are decoded according to their schemas and
+// encodings: primitives are parsed, arrays are made of repeated fields, or
+// of delimited values when they aren't exploded, and objects are decoded
+// from JSON. Fields missing from the schema are decoded as strings.
+func FormBodyDecoder(body io.Reader, header http.Header, schema *openapi3.SchemaRef, encodingFn openapi3filter.EncodingFn) (interface{}, error) {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+	}
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+	}
+
+	object := make(map[string]interface{}, len(values))
+	for name, fieldValues := range values {
+		var encoding *openapi3.Encoding
+		if encodingFn != nil {
+			encoding = encodingFn(name)
+		}
+		value, err := decodeFormField(fieldValues, propertySchema(schema, name), encoding)
+		if err != nil {
+			return nil, fieldError(name, err)
+		}
+		object[name] = value
+	}
+	return object, nil
+}
+
+func decodeFormField(values []string, schema *openapi3.Schema, encoding *openapi3.Encoding) (interface{}, error) {
+	if schema == nil {
+		if len(values) == 1 {
+			return values[0], nil
+		}
+		return stringValues(values), nil
+	}
+	if schema.Type != "array" {
+		return decodeFieldValue(values[0], schema, encoding)
+	}
+
+	if encoding != nil && encoding.Explode != nil && !*encoding.Explode {
+		delimiter := ","
+		switch encoding.Style {
+		case openapi3.SerializationSpaceDelimited:
+			delimiter = " "
+		case openapi3.SerializationPipeDelimited:
+			delimiter = "|"
+		}
+		values = strings.Split(values[0], delimiter)
+	}
+	var items *openapi3.Schema
+	if schema.Items != nil {
+		items = schema.Items.Value
+	}
+	array := make([]interface{}, len(values))
+	for i, value := range values {
+		item, err := decodeFieldValue(value, items, encoding)
+		if err != nil {
+			return nil, err
+		}
+		array[i] = item
+	}
+	return array, nil
+}
+
+// MultipartBodyDecoder decodes a multipart/form-data body into an object.
+// Files, ie, parts with a file name or a binary schema, are decoded as
+// strings, parts with a JSON content type, or an object schema, are decoded
+// from JSON, and other parts are parsed according to their schemas. Parts
+// with an array schema are collected into arrays.
+func MultipartBodyDecoder(body io.Reader, header http.Header, schema *openapi3.SchemaRef, encodingFn openapi3filter.EncodingFn) (interface{}, error) {
+	_, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+	}
+
+	object := make(map[string]interface{})
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+		}
+
+		name := part.FormName()
+		data, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, fieldError(name, err)
+		}
+		var encoding *openapi3.Encoding
+		if encodingFn != nil {
+			encoding = encodingFn(name)
+		}
+
+		property := propertySchema(schema, name)
+		itemSchema := property
+		if property != nil && property.Type == "array" {
+			itemSchema = nil
+			if property.Items != nil {
+				itemSchema = property.Items.Value
+			}
+		}
+
+		var value interface{} = string(data)
+		if part.FileName() == "" && (itemSchema == nil || itemSchema.Format != "binary") {
+			partEncoding := encoding
+			if partType := part.Header.Get("Content-Type"); partType != "" {
+				partEncoding = &openapi3.Encoding{ContentType: partType}
+			}
+			if value, err = decodeFieldValue(string(data), itemSchema, partEncoding); err != nil {
+				return nil, fieldError(name, err)
+			}
+		}
+
+		if property != nil && property.Type == "array" {
+			array, _ := object[name].([]interface{})
+			object[name] = append(array, value)
+		} else if _, ok := object[name]; !ok {
+			object[name] = value
+		}
+	}
+	return object, nil
+}
+
+// decodeFieldValue decodes the value of a form field or part with the given
+// schema and encoding.
+func decodeFieldValue(value string, schema *openapi3.Schema, encoding *openapi3.Encoding) (interface{}, error) {
+	if encoding != nil && isJSONContentType(encoding.ContentType) || schema != nil && schema.Type == "object" {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Value: value, Reason: "not valid JSON", Cause: err}
+		}
+		return decoded, nil
+	}
+	if schema == nil {
+		return value, nil
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Value: value, Reason: "an invalid " + schema.Type, Cause: err}
+		}
+		return number, nil
+	case "boolean":
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Value: value, Reason: "an invalid boolean", Cause: err}
+		}
+		return boolean, nil
+	}
+	return value, nil
+}
+
+// propertySchema returns the schema of the named property of an object
+// schema, or nil if it has none.
+func propertySchema(schema *openapi3.SchemaRef, name string) *openapi3.Schema {
+	if schema == nil || schema.Value == nil {
+		return nil
+	}
+	if property := schema.Value.Properties[name]; property != nil {
+		return property.Value
+	}
+	if additional := schema.Value.AdditionalProperties; additional != nil {
+		return additional.Value
+	}
+	return nil
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+func stringValues(values []string) []interface{} {
+	array := make([]interface{}, len(values))
+	for i, value := range values {
+		array[i] = value
+	}
+	return array
+}
+
+func fieldError(name string, err error) error {
+	return &openapi3filter.ParseError{Kind: openapi3filter.KindOther, Reason: fmt.Sprintf("field %q", name), Cause: err}
+}
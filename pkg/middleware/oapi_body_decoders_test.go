@@ -0,0 +1,246 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBodyDecodersSchema = `openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /things:
+    post:
+      operationId: postThing
+      requestBody:
+        required: true
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [id]
+              properties:
+                id:
+                  type: integer
+                  maximum: 100
+                tags:
+                  type: array
+                  items:
+                    type: integer
+                ids:
+                  type: array
+                  items:
+                    type: integer
+                owner:
+                  type: object
+                  properties:
+                    name:
+                      type: string
+              additionalProperties: false
+            encoding:
+              ids:
+                style: pipeDelimited
+                explode: false
+          multipart/form-data:
+            schema:
+              type: object
+              required: [file]
+              properties:
+                file:
+                  type: string
+                  format: binary
+                count:
+                  type: integer
+                  minimum: 1
+                labels:
+                  type: array
+                  items:
+                    type: string
+                    maxLength: 3
+                owner:
+                  type: object
+                  required: [name]
+                  properties:
+                    name:
+                      type: string
+          application/yaml:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                count:
+                  type: integer
+                  maximum: 10
+          text/plain:
+            schema:
+              type: string
+              maxLength: 5
+          application/xml:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  minLength: 2
+      responses:
+        '204':
+          description: No content
+`
+
+// xmlBodyDecoder decodes a <thing><name/></thing> XML body.
+func xmlBodyDecoder(body io.Reader, header http.Header, schema *openapi3.SchemaRef, encodingFn openapi3filter.EncodingFn) (interface{}, error) {
+	var thing struct {
+		Name *string `xml:"name"`
+	}
+	if err := xml.NewDecoder(body).Decode(&thing); err != nil {
+		return nil, err
+	}
+	object := map[string]interface{}{}
+	if thing.Name != nil {
+		object["name"] = *thing.Name
+	}
+	return object, nil
+}
+
+func newMultipartBody(t *testing.T, write func(w *multipart.Writer)) (string, string) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	write(w)
+	require.NoError(t, w.Close())
+	return w.FormDataContentType(), body.String()
+}
+
+func TestOapiRequestValidatorBodyDecoders(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testBodyDecodersSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	var received string
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorHTTPWithOptions(swagger, &Options{
+		BodyDecoders: map[string]openapi3filter.BodyDecoder{"application/xml": xmlBodyDecoder},
+	}))
+	r.Post("/things", func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	multipartType, validMultipart := newMultipartBody(t, func(w *multipart.Writer) {
+		file, _ := w.CreateFormFile("file", "thing.bin")
+		file.Write([]byte{0, 1, 2})
+		w.WriteField("count", "2")
+		w.WriteField("labels", "a")
+		w.WriteField("labels", "bc")
+		w.WriteField("owner", `{"name": "Marcin"}`)
+	})
+	_, invalidMultipart := newMultipartBody(t, func(w *multipart.Writer) {
+		w.SetBoundary("invalid-boundary")
+		w.WriteField("count", "0")
+		w.WriteField("labels", "abcd")
+	})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		valid       bool
+		reason      string
+	}{
+		{"form", "application/x-www-form-urlencoded", "id=7&tags=1&tags=2&ids=3|4&owner=%7B%22name%22%3A%22Marcin%22%7D", true, ""},
+		{"form out of range", "application/x-www-form-urlencoded", "id=700", false, `"/id"`},
+		{"form not a number", "application/x-www-form-urlencoded", "id=7&tags=x", false, "invalid integer"},
+		{"form unknown field", "application/x-www-form-urlencoded", "id=7&color=red", false, "color"},
+		{"multipart", multipartType, validMultipart, true, ""},
+		{"multipart invalid", "multipart/form-data; boundary=invalid-boundary", invalidMultipart, false, ""},
+		{"yaml", "application/yaml", "name: cat\ncount: 3\n", true, ""},
+		{"yaml out of range", "application/yaml", "name: cat\ncount: 30\n", false, `"/count"`},
+		{"yaml missing property", "application/yaml", "count: 3\n", false, "name"},
+		{"yaml malformed", "application/yaml", "name: [cat\n", false, "failed to decode"},
+		{"text", "text/plain; charset=utf-8", "hello", true, ""},
+		{"text too long", "text/plain", "hello world", false, "length"},
+		{"custom", "application/xml", "<thing><name>cat</name></thing>", true, ""},
+		{"custom invalid", "application/xml", "<thing><name>c</name></thing>", false, "length"},
+		{"unexpected content type", "application/toml", "name = 'cat'", false, "unexpected value"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			received = ""
+			req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if test.valid {
+				assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+				// Handlers still get the body which was validated.
+				assert.Equal(t, test.body, received)
+				return
+			}
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), test.reason)
+			assert.Empty(t, received)
+		})
+	}
+}
+
+func TestOapiRequestValidatorBodyDecodersProblemDetails(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testBodyDecodersSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorHTTPWithOptions(swagger, &Options{ProblemDetails: true}))
+	r.Post("/things", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader("name: cat\ncount: 30\n"))
+	req.Header.Set("Content-Type", "application/yaml")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	problem := decodeProblem(t, rec)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "body", problem.Errors[0].Location)
+	assert.Equal(t, "/count", problem.Errors[0].Pointer)
+}
+
+func TestYAMLBodyDecoder(t *testing.T) {
+	value, err := YAMLBodyDecoder(strings.NewReader("name: cat\ncount: 3\ntags: [a, 1]\nowner: {admin: true, 2: two}\n"), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":  "cat",
+		"count": float64(3),
+		"tags":  []interface{}{"a", float64(1)},
+		"owner": map[string]interface{}{"admin": true, "2": "two"},
+	}, value)
+}
@@ -0,0 +1,299 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// applyDefaults rewrites a request which passed validation, so that it's
+// what the spec says it means: the query and header parameters it's missing
+// are set to their defaults, those it has are coerced into the canonical
+// form of their type, and the properties missing from its JSON body are set
+// to their defaults, unless its body wasn't validated. Only parameters with
+// primitive values, or arrays of them, in the default style of their
+// location, are rewritten.
+func applyDefaults(req *http.Request, route *openapi3filter.Route, validatedBody bool) error {
+	operation := route.Operation
+	var parameters []*openapi3.Parameter
+	for _, parameterRef := range route.PathItem.Parameters {
+		parameter := parameterRef.Value
+		if operation.Parameters.GetByInAndName(parameter.In, parameter.Name) == nil {
+			parameters = append(parameters, parameter)
+		}
+	}
+	for _, parameterRef := range operation.Parameters {
+		parameters = append(parameters, parameterRef.Value)
+	}
+
+	query := req.URL.Query()
+	queryChanged := false
+	for _, parameter := range parameters {
+		schema := parameterSchema(parameter)
+		if schema == nil {
+			continue
+		}
+		switch parameter.In {
+		case openapi3.ParameterInQuery:
+			if parameter.Style != "" && parameter.Style != openapi3.SerializationForm {
+				continue
+			}
+			explode := parameter.Explode == nil || *parameter.Explode
+			values, ok := query[parameter.Name]
+			if !ok {
+				values = defaultParameterValues(schema, explode)
+			} else {
+				values = coerceParameterValues(schema, values, explode)
+			}
+			if values != nil {
+				query[parameter.Name] = values
+				queryChanged = true
+			}
+		case openapi3.ParameterInHeader:
+			if parameter.Style != "" && parameter.Style != openapi3.SerializationSimple {
+				continue
+			}
+			name := http.CanonicalHeaderKey(parameter.Name)
+			values, ok := req.Header[name]
+			if !ok {
+				values = defaultParameterValues(schema, false)
+			} else {
+				values = coerceParameterValues(schema, values, false)
+			}
+			if values != nil {
+				req.Header[name] = values
+			}
+		}
+	}
+	if queryChanged {
+		req.URL.RawQuery = query.Encode()
+	}
+
+	if operation.RequestBody != nil && validatedBody {
+		return applyBodyDefaults(req, operation.RequestBody.Value)
+	}
+	return nil
+}
+
+// parameterSchema returns the schema of a parameter, when its values are
+// primitives or arrays of them.
+func parameterSchema(parameter *openapi3.Parameter) *openapi3.Schema {
+	if parameter.Schema == nil || parameter.Schema.Value == nil {
+		return nil
+	}
+	schema := parameter.Schema.Value
+	if schema.Type == "array" {
+		if schema.Items == nil || schema.Items.Value == nil || !isPrimitiveType(schema.Items.Value.Type) {
+			return nil
+		}
+		return schema
+	}
+	if !isPrimitiveType(schema.Type) {
+		return nil
+	}
+	return schema
+}
+
+func isPrimitiveType(schemaType string) bool {
+	switch schemaType {
+	case "integer", "number", "boolean", "string":
+		return true
+	}
+	return false
+}
+
+// defaultParameterValues returns the values of a missing parameter with the
+// given schema, or nil if it has no default.
+func defaultParameterValues(schema *openapi3.Schema, explode bool) []string {
+	if schema.Default == nil {
+		return nil
+	}
+	if schema.Type != "array" {
+		value, ok := formatPrimitive(schema.Default)
+		if !ok {
+			return nil
+		}
+		return []string{value}
+	}
+
+	defaults, ok := schema.Default.([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, len(defaults))
+	for i, item := range defaults {
+		if values[i], ok = formatPrimitive(item); !ok {
+			return nil
+		}
+	}
+	if !explode {
+		return []string{strings.Join(values, ",")}
+	}
+	return values
+}
+
+// coerceParameterValues returns the values of a parameter in the canonical
+// form of their type, or nil if they're already in it.
+func coerceParameterValues(schema *openapi3.Schema, values []string, explode bool) []string {
+	itemType := schema.Type
+	if schema.Type == "array" {
+		itemType = schema.Items.Value.Type
+	}
+	if itemType == "string" {
+		return nil
+	}
+
+	changed := false
+	coerced := make([]string, len(values))
+	for i, value := range values {
+		if schema.Type == "array" && !explode {
+			items := strings.Split(value, ",")
+			for j, item := range items {
+				items[j] = coercePrimitive(itemType, item)
+			}
+			coerced[i] = strings.Join(items, ",")
+		} else {
+			coerced[i] = coercePrimitive(itemType, value)
+		}
+		changed = changed || coerced[i] != value
+	}
+	if !changed {
+		return nil
+	}
+	return coerced
+}
+
+// coercePrimitive returns value in the canonical form of the given type, or
+// as it is if it isn't of the type.
+func coercePrimitive(schemaType string, value string) string {
+	switch schemaType {
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return strconv.FormatInt(i, 10)
+		}
+		if f, err := strconv.ParseFloat(value, 64); err == nil && f == math.Trunc(f) {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return strconv.FormatBool(b)
+		}
+	}
+	return value
+}
+
+// formatPrimitive formats the default of a primitive schema, which is
+// decoded from JSON.
+func formatPrimitive(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case bool:
+		return strconv.FormatBool(value), true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case int, int64:
+		return fmt.Sprint(value), true
+	}
+	return "", false
+}
+
+// applyBodyDefaults sets the properties missing from the JSON body of req to
+// their defaults.
+func applyBodyDefaults(req *http.Request, requestBody *openapi3.RequestBody) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || !(mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+	content := requestBody.Content.Get(mediaType)
+	if content == nil || content.Schema == nil || content.Schema.Value == nil {
+		return nil
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil
+	}
+	if !setDefaults(content.Schema.Value, body) {
+		return nil
+	}
+	data, err = json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Length", strconv.Itoa(len(data)))
+	return nil
+}
+
+// setDefaults sets the properties missing from value, and from the objects
+// nested in it, to their defaults in schema, returning whether it set any.
+func setDefaults(schema *openapi3.Schema, value interface{}) bool {
+	changed := false
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for name, propertyRef := range schema.Properties {
+			if propertyRef == nil || propertyRef.Value == nil {
+				continue
+			}
+			property, ok := value[name]
+			if !ok {
+				if propertyRef.Value.Default != nil {
+					value[name] = propertyRef.Value.Default
+					changed = true
+				}
+				continue
+			}
+			changed = setDefaults(propertyRef.Value, property) || changed
+		}
+		for _, schemaRef := range schema.AllOf {
+			if schemaRef != nil && schemaRef.Value != nil {
+				changed = setDefaults(schemaRef.Value, value) || changed
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil && schema.Items.Value != nil {
+			for _, item := range value {
+				changed = setDefaults(schema.Items.Value, item) || changed
+			}
+		}
+	}
+	return changed
+}
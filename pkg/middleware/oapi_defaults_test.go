@@ -0,0 +1,188 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDefaultsSchema = `openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /things:
+    parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+          default: 20
+    post:
+      operationId: postThing
+      parameters:
+        - name: sort
+          in: query
+          schema:
+            type: string
+            default: name
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+            default: [a, b]
+        - name: ids
+          in: query
+          explode: false
+          schema:
+            type: array
+            items:
+              type: number
+        - name: verbose
+          in: query
+          schema:
+            type: boolean
+        - name: X-Page-Size
+          in: header
+          schema:
+            type: number
+            default: 2.5
+      requestBody:
+        content:
+          application/json:
+            schema:
+              properties:
+                name:
+                  type: string
+                color:
+                  type: string
+                  default: black
+                owner:
+                  properties:
+                    name:
+                      type: string
+                    admin:
+                      type: boolean
+                      default: false
+      responses:
+        '204':
+          description: No content
+`
+
+type defaultedRequest struct {
+	query  string
+	header http.Header
+	body   string
+}
+
+func newDefaultsRequest(url, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func recordDefaultedRequest(t *testing.T, got *defaultedRequest, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	require.NoError(t, err)
+	got.query = r.URL.RawQuery
+	got.header = r.Header
+	got.body = string(body)
+	assert.Equal(t, int64(len(body)), r.ContentLength)
+}
+
+func assertDefaultedRequests(t *testing.T, handler http.Handler, got *defaultedRequest) {
+	// Missing parameters and properties are set to their defaults.
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newDefaultsRequest("/things", `{"name": "cat", "owner": {"name": "Marcin"}}`))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "limit=20&sort=name&tags=a&tags=b", got.query)
+	assert.Equal(t, "2.5", got.header.Get("X-Page-Size"))
+	assert.JSONEq(t, `{"name": "cat", "color": "black", "owner": {"name": "Marcin", "admin": false}}`, got.body)
+
+	// Present values are coerced into their canonical form, and are
+	// otherwise left as they are.
+	req := newDefaultsRequest("/things?limit=1e2&sort=age&tags=c&ids=1.50,2&verbose=1", `{"color": "white", "owner": {"admin": true}}`)
+	req.Header.Set("X-Page-Size", "04")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "ids=1.5%2C2&limit=100&sort=age&tags=c&verbose=true", got.query)
+	assert.Equal(t, "4", got.header.Get("X-Page-Size"))
+	assert.JSONEq(t, `{"color": "white", "owner": {"admin": true}}`, got.body)
+
+	// Requests without a body are left without one.
+	req = newDefaultsRequest("/things?limit=5&sort=age&tags=c", "")
+	req.Header.Set("X-Page-Size", "1")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "limit=5&sort=age&tags=c", got.query)
+	assert.Empty(t, got.body)
+}
+
+func TestOapiRequestValidatorApplyDefaults(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testDefaultsSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	var got defaultedRequest
+	e := echo.New()
+	e.Use(OapiRequestValidatorWithOptions(swagger, &Options{ApplyDefaults: true}))
+	e.POST("/things", func(c echo.Context) error {
+		recordDefaultedRequest(t, &got, c.Request())
+		// Echo binds the rewritten parameters.
+		assert.Equal(t, c.Request().URL.Query().Get("limit"), c.QueryParam("limit"))
+		return c.NoContent(http.StatusNoContent)
+	})
+	assertDefaultedRequests(t, e, &got)
+}
+
+func TestOapiRequestValidatorHTTPApplyDefaults(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testDefaultsSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	var got defaultedRequest
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorHTTPWithOptions(swagger, &Options{ApplyDefaults: true}))
+	r.Post("/things", func(w http.ResponseWriter, r *http.Request) {
+		recordDefaultedRequest(t, &got, r)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	assertDefaultedRequests(t, r, &got)
+
+	// Without the option, requests are passed on as they are.
+	r = chi.NewRouter()
+	r.Use(OapiRequestValidatorHTTPWithOptions(swagger, nil))
+	r.Post("/things", func(w http.ResponseWriter, r *http.Request) {
+		recordDefaultedRequest(t, &got, r)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, newDefaultsRequest("/things?verbose=1", `{}`))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "verbose=1", got.query)
+	assert.Equal(t, `{}`, got.body)
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// The x-validation extension of an operation, or of its path item, controls
+// which parts of its requests are validated. It holds one, or a list, of
+// these values.
+const (
+	extPropValidation = "x-validation"
+
+	validationSkipBody     = "skip-body"
+	validationSkipSecurity = "skip-security"
+	validationSkipAll      = "skip-all"
+)
+
+// RouteSkipper is called with each request, and the route which it matched,
+// and returns true for those which shouldn't be validated.
+type RouteSkipper func(req *http.Request, route *openapi3filter.Route) bool
+
+// SkipOperations returns a RouteSkipper which skips validating the requests
+// of the operations with the given IDs.
+func SkipOperations(operationIDs ...string) RouteSkipper {
+	skipped := make(map[string]bool, len(operationIDs))
+	for _, operationID := range operationIDs {
+		skipped[operationID] = true
+	}
+	return func(req *http.Request, route *openapi3filter.Route) bool {
+		return skipped[route.Operation.OperationID]
+	}
+}
+
+// validationControl is what the x-validation extensions of a route skip.
+type validationControl struct {
+	skipBody     bool
+	skipSecurity bool
+	skipAll      bool
+}
+
+// routeValidationControl returns what the x-validation extensions of the
+// operation of a route, and of its path item, skip.
+func routeValidationControl(route *openapi3filter.Route) (validationControl, error) {
+	var control validationControl
+	for _, props := range []openapi3.ExtensionProps{route.PathItem.ExtensionProps, route.Operation.ExtensionProps} {
+		values, err := extValidation(props.Extensions[extPropValidation])
+		if err != nil {
+			return control, err
+		}
+		for _, value := range values {
+			switch value {
+			case validationSkipBody:
+				control.skipBody = true
+			case validationSkipSecurity:
+				control.skipSecurity = true
+			case validationSkipAll:
+				control.skipAll = true
+			default:
+				return control, fmt.Errorf("unknown %s value '%s'", extPropValidation, value)
+			}
+		}
+	}
+	return control, nil
+}
+
+// extValidation returns the values of an x-validation extension, which is
+// either a string or a list of them.
+func extValidation(extPropValue interface{}) ([]string, error) {
+	if extPropValue == nil {
+		return nil, nil
+	}
+	raw, ok := extPropValue.(json.RawMessage)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert type: %T", extPropValue)
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return []string{value}, nil
+	}
+	var values []string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("%s must be a string or a list of strings", extPropValidation)
+	}
+	return values, nil
+}
+
+// withoutSecurity returns a copy of route whose operation has no security
+// requirements.
+func withoutSecurity(route *openapi3filter.Route) *openapi3filter.Route {
+	operation := *route.Operation
+	operation.Security = &openapi3.SecurityRequirements{}
+	copied := *route
+	copied.Operation = &operation
+	return &copied
+}
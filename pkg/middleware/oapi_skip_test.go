@@ -0,0 +1,197 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/go-chi/chi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSkipSchema = `openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: TestServer
+security:
+  - BearerAuth: []
+components:
+  securitySchemes:
+    BearerAuth:
+      type: http
+      scheme: bearer
+  parameters:
+    count:
+      name: count
+      in: query
+      schema:
+        type: integer
+  requestBodies:
+    thing:
+      required: true
+      content:
+        application/json:
+          schema:
+            required: [name]
+            properties:
+              name:
+                type: string
+paths:
+  /things:
+    post:
+      operationId: postThing
+      parameters:
+        - $ref: '#/components/parameters/count'
+      requestBody:
+        $ref: '#/components/requestBodies/thing'
+      responses:
+        '204':
+          description: No content
+  /uploads:
+    post:
+      operationId: upload
+      x-validation: skip-body
+      parameters:
+        - $ref: '#/components/parameters/count'
+      requestBody:
+        $ref: '#/components/requestBodies/thing'
+      responses:
+        '204':
+          description: No content
+  /public:
+    x-validation: [skip-security]
+    post:
+      operationId: postPublic
+      parameters:
+        - $ref: '#/components/parameters/count'
+      requestBody:
+        $ref: '#/components/requestBodies/thing'
+      responses:
+        '204':
+          description: No content
+  /proxy:
+    post:
+      operationId: proxy
+      x-validation: [skip-all]
+      parameters:
+        - $ref: '#/components/parameters/count'
+      requestBody:
+        $ref: '#/components/requestBodies/thing'
+      responses:
+        '204':
+          description: No content
+`
+
+func authenticateBearerOK(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+	if input.RequestValidationInput.Request.Header.Get("Authorization") != "Bearer ok" {
+		return errors.New("unauthorized")
+	}
+	return nil
+}
+
+func newSkipRequest(path, body string, authorized bool) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if authorized {
+		req.Header.Set("Authorization", "Bearer ok")
+	}
+	return req
+}
+
+func TestValidationExtension(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testSkipSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	e := echo.New()
+	e.Use(OapiRequestValidatorWithOptions(swagger, &Options{
+		Options: openapi3filter.Options{AuthenticationFunc: authenticateBearerOK},
+	}))
+	for _, path := range []string{"/things", "/uploads", "/public", "/proxy"} {
+		e.POST(path, func(c echo.Context) error {
+			return c.NoContent(http.StatusNoContent)
+		})
+	}
+
+	tests := []struct {
+		path       string
+		body       string
+		authorized bool
+		statusCode int
+	}{
+		{"/things", `{"name": "cat"}`, true, http.StatusNoContent},
+		{"/things", `{}`, true, http.StatusBadRequest},
+		{"/things", `{"name": "cat"}`, false, http.StatusForbidden},
+
+		// skip-body still validates parameters and security.
+		{"/uploads", `not json`, true, http.StatusNoContent},
+		{"/uploads?count=x", `not json`, true, http.StatusBadRequest},
+		{"/uploads", `not json`, false, http.StatusForbidden},
+
+		// skip-security on the path item applies to its operations.
+		{"/public", `{"name": "cat"}`, false, http.StatusNoContent},
+		{"/public", `{}`, false, http.StatusBadRequest},
+
+		{"/proxy?count=x", `not json`, false, http.StatusNoContent},
+	}
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, newSkipRequest(test.path, test.body, test.authorized))
+		assert.Equal(t, test.statusCode, rec.Code, "%s %s authorized: %v", test.path, test.body, test.authorized)
+	}
+
+	// Invalid values are reported when the router is created.
+	swagger.Paths["/proxy"].Post.Extensions[extPropValidation] = json.RawMessage(`"skip-everything"`)
+	_, err = NewRouter(swagger)
+	assert.EqualError(t, err, "POST /proxy: unknown x-validation value 'skip-everything'")
+	swagger.Paths["/proxy"].Post.Extensions[extPropValidation] = json.RawMessage(`{"skip": "all"}`)
+	_, err = NewRouter(swagger)
+	assert.EqualError(t, err, "POST /proxy: x-validation must be a string or a list of strings")
+}
+
+func TestRouteSkipper(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testSkipSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorHTTPWithOptions(swagger, &Options{
+		Options:      openapi3filter.Options{AuthenticationFunc: authenticateBearerOK},
+		RouteSkipper: SkipOperations("postThing"),
+	}))
+	r.Post("/things", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "postThing", GetMatchedRoute(r.Context()).Route.Operation.OperationID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Post("/uploads", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, newSkipRequest("/things?count=x", `{}`, false))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, newSkipRequest("/uploads?count=x", `{}`, true))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -25,6 +25,8 @@ import (
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/runtime"
 )
 
 const EchoContextKey = "oapi-codegen/echo-context"
@@ -61,11 +63,54 @@ type Options struct {
 	ParamDecoder openapi3filter.ContentParameterDecoder
 	UserData     interface{}
 	Skipper      echomiddleware.Skipper
+
+	// BodyDecoders decode the bodies of requests of the content types they're
+	// registered for into the values validated against their schemas. They
+	// replace the DefaultBodyDecoders.
+	BodyDecoders map[string]openapi3filter.BodyDecoder
+
+	// HTTPSkipper is the Skipper of the net/http validator, which doesn't
+	// validate the requests it returns true for.
+	HTTPSkipper func(r *http.Request) bool
+
+	// ErrorWriter writes the response to requests which fail validation in
+	// the net/http validator. It defaults to DefaultErrorWriter.
+	ErrorWriter ErrorWriter
+
+	// Authenticators authenticate requests for the security schemes they're
+	// registered for, and the principals they return are stored in the
+	// context of the request. The AuthenticationFunc of the Options, if any,
+	// authenticates requests for the other schemes.
+	Authenticators Authenticators
+
+	// RouteSkipper is called with the route which each request matched, and
+	// the requests which it returns true for aren't validated, whichever
+	// validator handles them.
+	RouteSkipper RouteSkipper
+
+	// ProblemDetails validates all the parameters and the body of requests,
+	// rather than stopping at the first failure, and responds to invalid
+	// requests with an RFC 7807 application/problem+json body listing every
+	// failure. The echo validator returns it in an *echo.HTTPError, for the
+	// error handler to send; see ProblemErrorHandler.
+	ProblemDetails bool
+
+	// ApplyDefaults rewrites requests which pass validation, so that the
+	// parameters bound downstream reflect the spec: missing query and header
+	// parameters, and missing properties of JSON bodies, are set to their
+	// defaults, and the values of numeric and boolean parameters are coerced
+	// into their canonical form.
+	ApplyDefaults bool
 }
 
 // Create a validator from a swagger object, with validation options
 func OapiRequestValidatorWithOptions(swagger *openapi3.Swagger, options *Options) echo.MiddlewareFunc {
-	router := openapi3filter.NewRouter().WithSwagger(swagger)
+	return OapiRequestValidatorWithRouter(mustNewRouter(swagger), options)
+}
+
+// Create a validator which finds the routes of requests with router, so that
+// validators of the same spec can share it, with validation options
+func OapiRequestValidatorWithRouter(router RouteFinder, options *Options) echo.MiddlewareFunc {
 	skipper := getSkipperFromOptions(options)
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -84,8 +129,93 @@ func OapiRequestValidatorWithOptions(swagger *openapi3.Swagger, options *Options
 
 // This function is called from the middleware above and actually does the work
 // of validating a request.
-func ValidateRequestFromContext(ctx echo.Context, router *openapi3filter.Router, options *Options) error {
-	req := ctx.Request()
+func ValidateRequestFromContext(ctx echo.Context, router RouteFinder, options *Options) error {
+	// Pass the Echo context into the request validator, so that any callbacks
+	// which it invokes make it available.
+	requestContext := context.WithValue(context.Background(), EchoContextKey, ctx)
+	requestContext, auth := withAuthentication(requestContext)
+
+	_, _, err := ValidateRequest(requestContext, ctx.Request(), router, options)
+	if err == nil {
+		if len(auth.schemes) > 0 {
+			ctx.Set(PrincipalContextKey, auth.principal())
+			req := ctx.Request()
+			ctx.SetRequest(req.WithContext(auth.withPrincipals(req.Context())))
+		}
+		return nil
+	}
+	validationErr := err.(*RequestValidationError)
+	// Problem details are left for the error handler to send; see
+	// ProblemErrorHandler.
+	if validationErr.Problem != nil {
+		return &echo.HTTPError{
+			Code:     validationErr.StatusCode,
+			Message:  validationErr.Problem,
+			Internal: validationErr.Problem,
+		}
+	}
+	// Errors returned by the authentication function as echo errors are
+	// returned as they are.
+	if httpErr, ok := validationErr.Err.(*echo.HTTPError); ok {
+		return httpErr
+	}
+	return &echo.HTTPError{
+		Code:     validationErr.StatusCode,
+		Message:  validationErr.Message,
+		Internal: validationErr.Err,
+	}
+}
+
+// ProblemErrorHandler wraps an echo error handler, so that the errors
+// carrying problem details, which the validator returns with the
+// ProblemDetails option, and generated servers with the problem-details
+// option, are sent as application/problem+json. The default error handler
+// of echo sends them as plain JSON. Other errors are passed on to next.
+func ProblemErrorHandler(next echo.HTTPErrorHandler) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if httpErr, ok := err.(*echo.HTTPError); ok {
+			if problem, ok := httpErr.Message.(*runtime.Problem); ok {
+				if !c.Response().Committed {
+					runtime.WriteProblem(c.Response(), problem)
+				}
+				return
+			}
+		}
+		next(err, c)
+	}
+}
+
+// RequestValidationError is returned by ValidateRequest for requests which
+// fail validation.
+type RequestValidationError struct {
+	// The status to respond with
+	StatusCode int
+
+	Message string
+
+	// The error returned by openapi3filter
+	Err error
+
+	// The problem details to respond with, which are set for invalid
+	// parameters and bodies when the ProblemDetails option is on
+	Problem *runtime.Problem
+}
+
+func (e *RequestValidationError) Error() string {
+	return e.Message
+}
+
+func (e *RequestValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateRequest validates a request against the spec of router, whatever
+// the framework serving it, and returns the route and path parameters which
+// it matched. ctx is passed on to openapi3filter, along with the UserData of
+// the options, so that it's available to the AuthenticationFunc and the
+// Authenticators. Requests which fail validation get a
+// *RequestValidationError.
+func ValidateRequest(ctx context.Context, req *http.Request, router RouteFinder, options *Options) (*openapi3filter.Route, map[string]string, error) {
 	route, pathParams, err := router.FindRoute(req.Method, req.URL)
 
 	// We failed to find a matching route for the request.
@@ -94,32 +224,88 @@ func ValidateRequestFromContext(ctx echo.Context, router *openapi3filter.Router,
 		case *openapi3filter.RouteError:
 			// We've got a bad request, the path requested doesn't match
 			// either server, or path, or something.
-			return echo.NewHTTPError(http.StatusBadRequest, e.Reason)
+			return nil, nil, &RequestValidationError{
+				StatusCode: http.StatusBadRequest,
+				Message:    e.Reason,
+				Err:        err,
+			}
 		default:
 			// This should never happen today, but if our upstream code changes,
 			// we don't want to crash the server, so handle the unexpected error.
-			return echo.NewHTTPError(http.StatusInternalServerError,
-				fmt.Sprintf("error validating route: %s", err.Error()))
+			return nil, nil, &RequestValidationError{
+				StatusCode: http.StatusInternalServerError,
+				Message:    fmt.Sprintf("error validating route: %s", err.Error()),
+				Err:        err,
+			}
 		}
 	}
 
+	if options != nil && options.RouteSkipper != nil && options.RouteSkipper(req, route) {
+		return route, pathParams, nil
+	}
+	control, err := routeValidationControl(route)
+	if err != nil {
+		return route, pathParams, &RequestValidationError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("error validating request: %s", err),
+			Err:        err,
+		}
+	}
+	if control.skipAll {
+		return route, pathParams, nil
+	}
+
 	validationInput := &openapi3filter.RequestValidationInput{
 		Request:    req,
 		PathParams: pathParams,
 		Route:      route,
 	}
 
-	// Pass the Echo context into the request validator, so that any callbacks
-	// which it invokes make it available.
-	requestContext := context.WithValue(context.Background(), EchoContextKey, ctx)
-
+	var filterOptions openapi3filter.Options
 	if options != nil {
-		validationInput.Options = &options.Options
+		filterOptions = options.Options
+		if len(options.Authenticators) > 0 {
+			filterOptions.AuthenticationFunc = options.Authenticators.authenticationFunc(options.Options.AuthenticationFunc)
+		}
 		validationInput.ParamDecoder = options.ParamDecoder
-		requestContext = context.WithValue(requestContext, UserDataKey, options.UserData)
+		ctx = context.WithValue(ctx, UserDataKey, options.UserData)
+	}
+	if control.skipBody {
+		filterOptions.ExcludeRequestBody = true
 	}
+	if control.skipSecurity {
+		validationInput.Route = withoutSecurity(route)
+	}
+
+	// Bodies with a decoder of ours are validated after openapi3filter is
+	// done with the rest of the request.
+	var bodyDecoder openapi3filter.BodyDecoder
+	if route.Operation.RequestBody != nil && !filterOptions.ExcludeRequestBody {
+		bodyDecoder = options.bodyDecoder(req.Header.Get("Content-Type"))
+		filterOptions.ExcludeRequestBody = bodyDecoder != nil
+	}
+	validationInput.Options = &filterOptions
 
-	err = openapi3filter.ValidateRequest(requestContext, validationInput)
+	if options != nil && options.ProblemDetails {
+		var failures []runtime.ValidationFailure
+		failures, err = validateRequestFully(ctx, validationInput, bodyDecoder)
+		if len(failures) > 0 {
+			problem := runtime.NewValidationProblem(http.StatusBadRequest, failures...)
+			return route, pathParams, &RequestValidationError{
+				StatusCode: http.StatusBadRequest,
+				Message:    problem.Detail,
+				Err:        err,
+				Problem:    problem,
+			}
+		}
+		// Everything but the security requirements has been validated.
+		err = validateSecurity(ctx, validationInput)
+	} else {
+		err = openapi3filter.ValidateRequest(ctx, validationInput)
+		if err == nil && bodyDecoder != nil {
+			err = validateRequestBody(validationInput, route.Operation.RequestBody.Value, bodyDecoder)
+		}
+	}
 	if err != nil {
 		switch e := err.(type) {
 		case *openapi3filter.RequestError:
@@ -127,34 +313,54 @@ func ValidateRequestFromContext(ctx echo.Context, router *openapi3filter.Router,
 			// Split up the verbose error by lines and return the first one
 			// openapi errors seem to be multi-line with a decent message on the first
 			errorLines := strings.Split(e.Error(), "\n")
-			return &echo.HTTPError{
-				Code:     http.StatusBadRequest,
-				Message:  errorLines[0],
-				Internal: err,
+			return route, pathParams, &RequestValidationError{
+				StatusCode: http.StatusBadRequest,
+				Message:    errorLines[0],
+				Err:        err,
 			}
 		case *openapi3filter.SecurityRequirementsError:
 			for _, err := range e.Errors {
-				httpErr, ok := err.(*echo.HTTPError)
-				if ok {
-					return httpErr
+				switch err := err.(type) {
+				case *echo.HTTPError:
+					return route, pathParams, &RequestValidationError{
+						StatusCode: err.Code,
+						Message:    fmt.Sprint(err.Message),
+						Err:        err,
+					}
+				case *AuthenticationError:
+					return route, pathParams, &RequestValidationError{
+						StatusCode: err.StatusCode,
+						Message:    err.Error(),
+						Err:        err,
+					}
 				}
 			}
-			return &echo.HTTPError{
-				Code:     http.StatusForbidden,
-				Message:  e.Error(),
-				Internal: err,
+			return route, pathParams, &RequestValidationError{
+				StatusCode: http.StatusForbidden,
+				Message:    e.Error(),
+				Err:        err,
 			}
 		default:
 			// This should never happen today, but if our upstream code changes,
 			// we don't want to crash the server, so handle the unexpected error.
-			return &echo.HTTPError{
-				Code:     http.StatusInternalServerError,
-				Message:  fmt.Sprintf("error validating request: %s", err),
-				Internal: err,
+			return route, pathParams, &RequestValidationError{
+				StatusCode: http.StatusInternalServerError,
+				Message:    fmt.Sprintf("error validating request: %s", err),
+				Err:        err,
+			}
+		}
+	}
+
+	if options != nil && options.ApplyDefaults {
+		if err := applyDefaults(req, route, !control.skipBody && !options.Options.ExcludeRequestBody); err != nil {
+			return route, pathParams, &RequestValidationError{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("error applying defaults: %s", err),
+				Err:        err,
 			}
 		}
 	}
-	return nil
+	return route, pathParams, nil
 }
 
 // Helper function to get the echo context from within requests. It returns
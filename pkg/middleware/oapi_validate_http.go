@@ -0,0 +1,106 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/runtime"
+)
+
+const RouteContextKey = "oapi-codegen/route"
+
+// ErrorWriter writes the response to a request which failed validation with
+// the given status. err is a *RequestValidationError, which wraps the error
+// returned by openapi3filter.
+type ErrorWriter func(w http.ResponseWriter, r *http.Request, statusCode int, err error)
+
+// DefaultErrorWriter writes the problem details of err, when it has them, and
+// otherwise its message as plain text.
+func DefaultErrorWriter(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	if validationErr, ok := err.(*RequestValidationError); ok && validationErr.Problem != nil {
+		runtime.WriteProblem(w, validationErr.Problem)
+		return
+	}
+	http.Error(w, err.Error(), statusCode)
+}
+
+// MatchedRoute is the route of the spec which a request matched, which the
+// net/http validator stores in the context of the requests it passes on.
+type MatchedRoute struct {
+	Route      *openapi3filter.Route
+	PathParams map[string]string
+}
+
+// Create a net/http validator from a swagger object, for use with chi or any
+// other router working with http.Handler.
+func OapiRequestValidatorHTTP(swagger *openapi3.Swagger) func(http.Handler) http.Handler {
+	return OapiRequestValidatorHTTPWithOptions(swagger, nil)
+}
+
+// Create a net/http validator from a swagger object, with validation
+// options. Requests which fail validation are answered by the ErrorWriter of
+// the options, and the rest are passed on to the next handler with the route
+// they matched, and their principal, in their context.
+func OapiRequestValidatorHTTPWithOptions(swagger *openapi3.Swagger, options *Options) func(http.Handler) http.Handler {
+	return OapiRequestValidatorHTTPWithRouter(mustNewRouter(swagger), options)
+}
+
+// Create a net/http validator which finds the routes of requests with
+// router, so that validators of the same spec can share it, with validation
+// options.
+func OapiRequestValidatorHTTPWithRouter(router RouteFinder, options *Options) func(http.Handler) http.Handler {
+	skipper := func(*http.Request) bool { return false }
+	errorWriter := DefaultErrorWriter
+	if options != nil && options.HTTPSkipper != nil {
+		skipper = options.HTTPSkipper
+	}
+	if options != nil && options.ErrorWriter != nil {
+		errorWriter = options.ErrorWriter
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skipper(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, auth := withAuthentication(r.Context())
+			route, pathParams, err := ValidateRequest(ctx, r, router, options)
+			if err != nil {
+				errorWriter(w, r, err.(*RequestValidationError).StatusCode, err)
+				return
+			}
+			ctx = auth.withPrincipals(r.Context())
+			ctx = context.WithValue(ctx, RouteContextKey, &MatchedRoute{
+				Route:      route,
+				PathParams: pathParams,
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Helper function to get the route which a request matched from within
+// handlers behind the net/http validator. It returns nil if not found.
+func GetMatchedRoute(c context.Context) *MatchedRoute {
+	route, _ := c.Value(RouteContextKey).(*MatchedRoute)
+	return route
+}
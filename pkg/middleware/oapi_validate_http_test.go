@@ -0,0 +1,140 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOapiRequestValidatorHTTP(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	options := Options{
+		Options: openapi3filter.Options{
+			AuthenticationFunc: func(c context.Context, input *openapi3filter.AuthenticationInput) error {
+				// User data should be propagated into here.
+				assert.EqualValues(t, "hi!", GetUserData(c))
+				for _, s := range input.Scopes {
+					if s == "someScope" {
+						return nil
+					}
+				}
+				return errors.New("forbidden")
+			},
+		},
+		UserData: "hi!",
+		HTTPSkipper: func(r *http.Request) bool {
+			return r.URL.Path == "/skipped"
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorHTTPWithOptions(swagger, &options))
+
+	var matched *MatchedRoute
+	called := false
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		matched = GetMatchedRoute(r.Context())
+	})
+	r.Post("/resource", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		// The body is still there to be read.
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "Marcin"}`, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Get("/protected_resource2", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	r.Get("/skipped", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		called = false
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A good request is passed on with the route it matched.
+	rec := do("GET", "http://deepmap.ai/resource?id=50", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, called, "Handler should have been called")
+	require.NotNil(t, matched)
+	assert.Equal(t, "getResource", matched.Route.Operation.OperationID)
+
+	// Bad requests are rejected.
+	rec = do("GET", "http://deepmap.ai/resource?id=500", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.False(t, called, "Handler should not have been called")
+
+	rec = do("GET", "http://not.deepmap.ai/resource", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.False(t, called, "Handler should not have been called")
+
+	rec = do("POST", "http://deepmap.ai/resource", `{"name": 7}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.False(t, called, "Handler should not have been called")
+
+	rec = do("POST", "http://deepmap.ai/resource", `{"name": "Marcin"}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.True(t, called, "Handler should have been called")
+
+	rec = do("GET", "http://deepmap.ai/protected_resource2", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.False(t, called, "Handler should not have been called")
+
+	// Skipped requests aren't validated.
+	rec = do("GET", "http://deepmap.ai/skipped", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, called, "Handler should have been called")
+
+	// Errors are written by the error writer.
+	options.ErrorWriter = func(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+		var requestErr *openapi3filter.RequestError
+		assert.True(t, errors.As(err, &requestErr))
+		w.WriteHeader(statusCode)
+		fmt.Fprintf(w, "invalid %s", requestErr.Parameter.Name)
+	}
+	r = chi.NewRouter()
+	r.Use(OapiRequestValidatorHTTPWithOptions(swagger, &options))
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	rec = do("GET", "http://deepmap.ai/resource?id=500", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "invalid id", rec.Body.String())
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/runtime"
+)
+
+// validateRequestFully validates each of the parameters of a request, and
+// its body, like openapi3filter.ValidateRequest, but carries on past the
+// first failure. It returns every failure, along with the first error. The
+// security requirements are left to validateSecurity. The body is decoded
+// with bodyDecoder, if any.
+func validateRequestFully(ctx context.Context, input *openapi3filter.RequestValidationInput, bodyDecoder openapi3filter.BodyDecoder) ([]runtime.ValidationFailure, error) {
+	options := input.Options
+	if options == nil {
+		options = openapi3filter.DefaultOptions
+	}
+	operation := input.Route.Operation
+
+	var failures []runtime.ValidationFailure
+	var firstErr error
+	check := func(err error) {
+		if err == nil {
+			return
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		failures = append(failures, validationFailure(err))
+	}
+
+	for _, parameterRef := range input.Route.PathItem.Parameters {
+		parameter := parameterRef.Value
+		if operation.Parameters.GetByInAndName(parameter.In, parameter.Name) != nil {
+			continue
+		}
+		check(openapi3filter.ValidateParameter(ctx, input, parameter))
+	}
+	for _, parameterRef := range operation.Parameters {
+		check(openapi3filter.ValidateParameter(ctx, input, parameterRef.Value))
+	}
+	if operation.RequestBody != nil && bodyDecoder != nil {
+		check(validateRequestBody(input, operation.RequestBody.Value, bodyDecoder))
+	} else if operation.RequestBody != nil && !options.ExcludeRequestBody {
+		check(openapi3filter.ValidateRequestBody(ctx, input, operation.RequestBody.Value))
+	}
+	return failures, firstErr
+}
+
+// validateSecurity validates the security requirements of a request, which
+// are those of its operation, or the global ones of the spec if the
+// operation has none, like openapi3filter.ValidateRequest does after the
+// parameters and the body.
+func validateSecurity(ctx context.Context, input *openapi3filter.RequestValidationInput) error {
+	security := input.Route.Operation.Security
+	if security == nil {
+		if input.Route.Swagger == nil {
+			return nil
+		}
+		security = &input.Route.Swagger.Security
+	}
+	return openapi3filter.ValidateSecurityRequirements(ctx, input, *security)
+}
+
+// validationFailure describes an error returned by openapi3filter for a
+// parameter or the body of a request.
+func validationFailure(err error) runtime.ValidationFailure {
+	requestErr, ok := err.(*openapi3filter.RequestError)
+	if !ok {
+		return runtime.ValidationFailure{Reason: err.Error()}
+	}
+
+	failure := runtime.ValidationFailure{Reason: requestErr.Reason}
+	if requestErr.Parameter != nil {
+		failure.Location = requestErr.Parameter.In
+		failure.Parameter = requestErr.Parameter.Name
+	} else {
+		failure.Location = "body"
+	}
+
+	if schemaErr, ok := requestErr.Err.(*openapi3.SchemaError); ok {
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			failure.Pointer = jsonPointer(pointer)
+		}
+		failure.Reason = schemaErr.Reason
+		if schemaErr.Origin != nil || failure.Reason == "" {
+			failure.Reason = schemaErr.Error()
+		}
+	} else if requestErr.Err != nil {
+		if failure.Reason == "" {
+			failure.Reason = requestErr.Err.Error()
+		} else {
+			failure.Reason += ": " + requestErr.Err.Error()
+		}
+	}
+	return failure
+}
+
+// jsonPointer returns the JSON pointer to the given path.
+func jsonPointer(path []string) string {
+	var b strings.Builder
+	for _, token := range path {
+		token = strings.Replace(token, "~", "~0", -1)
+		token = strings.Replace(token, "/", "~1", -1)
+		b.WriteString("/" + token)
+	}
+	return b.String()
+}
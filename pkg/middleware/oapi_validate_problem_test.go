@@ -0,0 +1,262 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/go-chi/chi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/runtime"
+)
+
+var testProblemSchema = `openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /things/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    put:
+      operationId: putThing
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+        - name: X-Request-Id
+          in: header
+          required: true
+          schema:
+            type: string
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              properties:
+                owner:
+                  properties:
+                    name:
+                      type: string
+      responses:
+        '204':
+          description: No content
+`
+
+func newProblemRequest(url string) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, url, strings.NewReader(`{"owner": {"name": 7}}`))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) runtime.Problem {
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, runtime.ProblemContentType, rec.Header().Get("Content-Type"))
+	var problem runtime.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	return problem
+}
+
+func assertProblemFailures(t *testing.T, problem runtime.Problem) {
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "The request failed validation in 3 ways", problem.Detail)
+	require.Len(t, problem.Errors, 3)
+
+	assert.Equal(t, "query", problem.Errors[0].Location)
+	assert.Equal(t, "limit", problem.Errors[0].Parameter)
+	assert.Contains(t, problem.Errors[0].Reason, "100")
+
+	assert.Equal(t, "header", problem.Errors[1].Location)
+	assert.Equal(t, "X-Request-Id", problem.Errors[1].Parameter)
+
+	assert.Equal(t, "body", problem.Errors[2].Location)
+	assert.Equal(t, "/owner/name", problem.Errors[2].Pointer)
+	assert.Empty(t, problem.Errors[2].Parameter)
+}
+
+func TestOapiRequestValidatorProblemDetails(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testProblemSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	called := false
+	e := echo.New()
+	e.Use(OapiRequestValidatorWithOptions(swagger, &Options{ProblemDetails: true}))
+	e.PUT("/things/:id", func(c echo.Context) error {
+		called = true
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	// The default error handler sends the problem as plain JSON.
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, newProblemRequest("/things/1?limit=1000"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, rec.Header().Get("Content-Type"))
+	var problem runtime.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assertProblemFailures(t, problem)
+
+	// ProblemErrorHandler sends it once, as application/problem+json.
+	handled := 0
+	problemHandler := ProblemErrorHandler(e.DefaultHTTPErrorHandler)
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		handled++
+		problemHandler(err, c)
+	}
+
+	// Every failure is listed, rather than only the first one.
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, newProblemRequest("/things/1?limit=1000"))
+	assertProblemFailures(t, decodeProblem(t, rec))
+	assert.Equal(t, 1, handled)
+	assert.False(t, called)
+
+	req := newProblemRequest("/things/1?limit=10")
+	req.Body = http.NoBody
+	req.Header.Set("X-Request-Id", "abc")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	problem = decodeProblem(t, rec)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "body", problem.Errors[0].Location)
+	assert.Equal(t, "The request failed validation: "+problem.Errors[0].String(), problem.Detail)
+
+	req = httptest.NewRequest(http.MethodPut, "/things/1", strings.NewReader(`{"owner": {"name": "Marcin"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-Id", "abc")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.True(t, called)
+}
+
+func TestOapiRequestValidatorHTTPProblemDetails(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testProblemSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	called := false
+	r := chi.NewRouter()
+	r.Use(OapiRequestValidatorHTTPWithOptions(swagger, &Options{ProblemDetails: true}))
+	r.Put("/things/{id}", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, newProblemRequest("/things/1?limit=1000"))
+	assertProblemFailures(t, decodeProblem(t, rec))
+	assert.False(t, called)
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, newProblemRequest("/things/abc?limit=10"))
+	problem := decodeProblem(t, rec)
+	require.Len(t, problem.Errors, 3)
+	assert.Equal(t, "path", problem.Errors[0].Location)
+	assert.Equal(t, "id", problem.Errors[0].Parameter)
+}
+
+var testProblemSecuritySchema = `openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: TestServer
+paths:
+  /notes:
+    post:
+      operationId: addNote
+      security:
+        - ApiKey: []
+      requestBody:
+        required: true
+        content:
+          text/plain:
+            schema:
+              type: string
+              maxLength: 10
+      responses:
+        '204':
+          description: No content
+components:
+  securitySchemes:
+    ApiKey:
+      type: apiKey
+      in: header
+      name: X-Api-Key
+`
+
+func TestValidateRequestProblemDetailsOnce(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testProblemSecuritySchema))
+	require.NoError(t, err, "Error initializing swagger")
+	router, err := NewRouter(swagger)
+	require.NoError(t, err)
+
+	decoded, authenticated := 0, 0
+	options := &Options{
+		ProblemDetails: true,
+		BodyDecoders: map[string]openapi3filter.BodyDecoder{
+			"text/plain": func(body io.Reader, header http.Header, schema *openapi3.SchemaRef, encodingFn openapi3filter.EncodingFn) (interface{}, error) {
+				decoded++
+				return PlainTextBodyDecoder(body, header, schema, encodingFn)
+			},
+		},
+		Options: openapi3filter.Options{
+			AuthenticationFunc: func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+				authenticated++
+				if input.RequestValidationInput.Request.Header.Get("X-Api-Key") != "secret" {
+					return errors.New("invalid key")
+				}
+				return nil
+			},
+		},
+	}
+	newRequest := func(key string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/notes", strings.NewReader("note"))
+		req.Header.Set("Content-Type", "text/plain")
+		req.Header.Set("X-Api-Key", key)
+		return req
+	}
+
+	// After the full pass, only the security requirements are validated, so
+	// nothing is validated twice.
+	req := newRequest("secret")
+	_, _, err = ValidateRequest(req.Context(), req, router, options)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, decoded)
+	assert.Equal(t, 1, authenticated)
+
+	req = newRequest("wrong")
+	_, _, err = ValidateRequest(req.Context(), req, router, options)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, err.(*RequestValidationError).StatusCode)
+	assert.Equal(t, 2, decoded)
+	assert.Equal(t, 2, authenticated)
+}
@@ -0,0 +1,216 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// ResponseValidationMode selects what the response validator does with
+// responses which don't conform to the spec.
+type ResponseValidationMode int
+
+const (
+	// ResponseValidationLog logs invalid responses to the logger of the echo
+	// context, and sends them unchanged.
+	ResponseValidationLog ResponseValidationMode = iota
+
+	// ResponseValidationReplace replaces invalid responses with a 500, which
+	// is sent by the HTTP error handler of echo.
+	ResponseValidationReplace
+
+	// ResponseValidationPassThrough sends invalid responses unchanged, only
+	// reporting them to the ErrorHandler of the options.
+	ResponseValidationPassThrough
+)
+
+// Options to customize response validation.
+type ResponseValidatorOptions struct {
+	// Options are passed through to openapi3filter. IncludeResponseStatus
+	// makes responses with statuses which the operation doesn't declare
+	// invalid, and ExcludeResponseBody skips validating bodies.
+	Options openapi3filter.Options
+
+	Mode ResponseValidationMode
+
+	// SampleRate is the fraction of requests whose responses are validated,
+	// between 0 and 1. Zero, like 1, validates every response.
+	SampleRate float64
+
+	Skipper echomiddleware.Skipper
+
+	// ErrorHandler, if set, is called with the error of every invalid
+	// response, whatever the mode.
+	ErrorHandler func(c echo.Context, err error)
+}
+
+// sampleFloat64 returns the number which is compared with the sample rate to
+// decide whether to validate a response.
+var sampleFloat64 = rand.Float64
+
+// Create a response validator from a swagger object, which logs invalid
+// responses.
+func OapiResponseValidator(swagger *openapi3.Swagger) echo.MiddlewareFunc {
+	return OapiResponseValidatorWithOptions(swagger, nil)
+}
+
+// Create a response validator from a swagger object, with validation
+// options. Validated responses are buffered until the handler returns, and
+// then validated against the operation which the request matches in the
+// spec, so that they can be replaced when they're invalid. Responses to
+// requests which don't match an operation aren't validated.
+func OapiResponseValidatorWithOptions(swagger *openapi3.Swagger, options *ResponseValidatorOptions) echo.MiddlewareFunc {
+	router := mustNewRouter(swagger)
+	if options == nil {
+		options = &ResponseValidatorOptions{}
+	}
+	skipper := options.Skipper
+	if skipper == nil {
+		skipper = echomiddleware.DefaultSkipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper(c) {
+				return next(c)
+			}
+			if options.SampleRate > 0 && options.SampleRate < 1 && sampleFloat64() >= options.SampleRate {
+				return next(c)
+			}
+
+			req := c.Request()
+			route, pathParams, routeErr := router.FindRoute(req.Method, req.URL)
+			if routeErr != nil {
+				return next(c)
+			}
+
+			res := c.Response()
+			writer := newBufferedResponseWriter(res.Writer)
+			res.Writer = writer
+			defer func() {
+				res.Writer = writer.ResponseWriter
+			}()
+
+			// Errors are sent by the error handler here, into the buffer, so
+			// that the response they produce is validated too. They aren't
+			// returned, since that would send them a second time.
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			input := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{
+					Request:    req,
+					PathParams: pathParams,
+					Route:      route,
+				},
+				Status:  writer.status,
+				Header:  writer.header,
+				Body:    ioutil.NopCloser(bytes.NewReader(writer.body.Bytes())),
+				Options: &options.Options,
+			}
+			requestContext := context.WithValue(context.Background(), EchoContextKey, c)
+			validationErr := openapi3filter.ValidateResponse(requestContext, input)
+			if validationErr == nil {
+				writer.flush()
+				return nil
+			}
+
+			if options.ErrorHandler != nil {
+				options.ErrorHandler(c, validationErr)
+			}
+			switch options.Mode {
+			case ResponseValidationReplace:
+				// Nothing has reached the client yet, so the response is
+				// marked uncommitted for the error handler to send the 500.
+				res.Writer = writer.ResponseWriter
+				res.Committed = false
+				res.Status = 0
+				res.Size = 0
+				c.Error(&echo.HTTPError{
+					Code:     http.StatusInternalServerError,
+					Message:  http.StatusText(http.StatusInternalServerError),
+					Internal: validationErr,
+				})
+				return nil
+			case ResponseValidationLog:
+				c.Logger().Warnf("invalid response to %s %s: %s", req.Method, req.URL.Path, validationErr)
+			}
+			writer.flush()
+			return nil
+		}
+	}
+}
+
+// bufferedResponseWriter holds a response until it's flushed to the
+// underlying writer. The headers start out as a copy of those of the
+// underlying writer, which are left untouched until then.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponseWriter(w http.ResponseWriter) *bufferedResponseWriter {
+	header := make(http.Header)
+	for name, values := range w.Header() {
+		header[name] = append([]string(nil), values...)
+	}
+	return &bufferedResponseWriter{
+		ResponseWriter: w,
+		header:         header,
+		status:         http.StatusOK,
+	}
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// Flush does nothing, since the whole response is held until it's validated.
+func (w *bufferedResponseWriter) Flush() {}
+
+// flush sends the buffered response to the underlying writer.
+func (w *bufferedResponseWriter) flush() {
+	header := w.ResponseWriter.Header()
+	for name := range header {
+		if _, ok := w.header[name]; !ok {
+			delete(header, name)
+		}
+	}
+	for name, values := range w.header {
+		header[name] = values
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
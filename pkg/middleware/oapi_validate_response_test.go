@@ -0,0 +1,151 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponseValidatorEcho(t *testing.T, options *ResponseValidatorOptions) *echo.Echo {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testSchema))
+	require.NoError(t, err, "Error initializing swagger")
+
+	e := echo.New()
+	e.Use(OapiResponseValidatorWithOptions(swagger, options))
+	e.GET("/resource", func(c echo.Context) error {
+		c.Response().Header().Set("X-Handler", "called")
+		if c.QueryParam("id") == "bad" {
+			return c.JSON(http.StatusOK, map[string]interface{}{"id": "not a number"})
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{"id": 42, "name": "Marcin"})
+	})
+	e.POST("/resource", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusTeapot, "short and stout")
+	})
+	return e
+}
+
+func TestOapiResponseValidator(t *testing.T) {
+	var validationErrors []error
+	options := &ResponseValidatorOptions{
+		ErrorHandler: func(c echo.Context, err error) {
+			validationErrors = append(validationErrors, err)
+		},
+	}
+
+	for _, mode := range []ResponseValidationMode{ResponseValidationLog, ResponseValidationPassThrough} {
+		options.Mode = mode
+		validationErrors = nil
+		e := newResponseValidatorEcho(t, options)
+
+		// Valid responses are sent as they are.
+		rec := doGet(t, e, "http://deepmap.ai/resource")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"id": 42, "name": "Marcin"}`, rec.Body.String())
+		assert.Equal(t, "called", rec.Header().Get("X-Handler"))
+		assert.Empty(t, validationErrors)
+
+		// Invalid ones are reported, and sent unchanged.
+		rec = doGet(t, e, "http://deepmap.ai/resource?id=bad")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"id": "not a number"}`, rec.Body.String())
+		assert.Len(t, validationErrors, 1)
+	}
+
+	options.Mode = ResponseValidationReplace
+	validationErrors = nil
+	e := newResponseValidatorEcho(t, options)
+
+	rec := doGet(t, e, "http://deepmap.ai/resource")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id": 42, "name": "Marcin"}`, rec.Body.String())
+
+	// Invalid responses are replaced by a 500, without the headers set by the
+	// handler.
+	rec = doGet(t, e, "http://deepmap.ai/resource?id=bad")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"message": "Internal Server Error"}`, rec.Body.String())
+	assert.Empty(t, rec.Header().Get("X-Handler"))
+	assert.Len(t, validationErrors, 1)
+
+	// Errors returned by handlers are validated as the responses they're
+	// turned into. Undeclared statuses are allowed by default.
+	rec = doPost(t, e, "http://deepmap.ai/resource", map[string]string{"name": "Marcin"})
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Len(t, validationErrors, 1)
+
+	options.Options.IncludeResponseStatus = true
+	e = newResponseValidatorEcho(t, options)
+	rec = doPost(t, e, "http://deepmap.ai/resource", map[string]string{"name": "Marcin"})
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Len(t, validationErrors, 2)
+}
+
+func TestOapiResponseValidatorErrorHandledOnce(t *testing.T) {
+	for _, mode := range []ResponseValidationMode{ResponseValidationLog, ResponseValidationReplace} {
+		e := newResponseValidatorEcho(t, &ResponseValidatorOptions{
+			Mode:    mode,
+			Options: openapi3filter.Options{IncludeResponseStatus: true},
+		})
+		handled := 0
+		defaultHandler := e.HTTPErrorHandler
+		e.HTTPErrorHandler = func(err error, c echo.Context) {
+			handled++
+			defaultHandler(err, c)
+		}
+
+		// The error of the handler is sent once, and so is the 500 replacing
+		// it, without the first response being sent again.
+		rec := doPost(t, e, "http://deepmap.ai/resource", map[string]string{"name": "Marcin"})
+		if mode == ResponseValidationReplace {
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assert.JSONEq(t, `{"message": "Internal Server Error"}`, rec.Body.String())
+			assert.Equal(t, 2, handled)
+		} else {
+			assert.Equal(t, http.StatusTeapot, rec.Code)
+			assert.JSONEq(t, `{"message": "short and stout"}`, rec.Body.String())
+			assert.Equal(t, 1, handled)
+		}
+	}
+}
+
+func TestOapiResponseValidatorSampling(t *testing.T) {
+	defer func(f func() float64) { sampleFloat64 = f }(sampleFloat64)
+	sample := 0.0
+	sampleFloat64 = func() float64 { return sample }
+
+	validated := 0
+	e := newResponseValidatorEcho(t, &ResponseValidatorOptions{
+		SampleRate: 0.25,
+		ErrorHandler: func(c echo.Context, err error) {
+			validated++
+		},
+	})
+
+	doGet(t, e, "http://deepmap.ai/resource?id=bad")
+	assert.Equal(t, 1, validated)
+
+	sample = 0.5
+	rec := doGet(t, e, "http://deepmap.ai/resource?id=bad")
+	assert.Equal(t, 1, validated)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,484 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// RouteFinder finds the route of a spec which a request matches, along with
+// the values of its path parameters. Both openapi3filter.Router and Router
+// are RouteFinders.
+type RouteFinder interface {
+	FindRoute(method string, url *url.URL) (*openapi3filter.Route, map[string]string, error)
+}
+
+// Router finds routes like openapi3filter.Router, with the same semantics for
+// servers and path templates, but compiles the spec once, so that finding a
+// route takes time proportional to the length of the path rather than to the
+// number of operations. Changes to the spec after it's compiled aren't seen
+// by the router.
+type Router struct {
+	swagger *openapi3.Swagger
+	servers []*serverPattern
+
+	// The root of the routes of each method
+	roots map[string]*routeNode
+}
+
+// NewRouter validates swagger, along with the x-validation extensions of its
+// operations, and compiles its servers and paths.
+func NewRouter(swagger *openapi3.Swagger) (*Router, error) {
+	if err := swagger.Validate(context.TODO()); err != nil {
+		return nil, fmt.Errorf("Validating Swagger failed: %v", err)
+	}
+
+	router := &Router{
+		swagger: swagger,
+		roots:   make(map[string]*routeNode),
+	}
+	for _, server := range swagger.Servers {
+		router.servers = append(router.servers, compileServer(server))
+	}
+	for path, pathItem := range swagger.Paths {
+		for method, operation := range pathItem.Operations() {
+			method = strings.ToUpper(method)
+			route := &openapi3filter.Route{
+				Swagger:   swagger,
+				Path:      path,
+				PathItem:  pathItem,
+				Method:    method,
+				Operation: operation,
+			}
+			if _, err := routeValidationControl(route); err != nil {
+				return nil, fmt.Errorf("%s %s: %s", method, path, err)
+			}
+			root := router.roots[method]
+			if root == nil {
+				root = &routeNode{}
+				router.roots[method] = root
+			}
+			if err := root.add(path, route); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return router, nil
+}
+
+// mustNewRouter returns the router of swagger, panicking when it's invalid,
+// as openapi3filter.Router.WithSwagger does.
+func mustNewRouter(swagger *openapi3.Swagger) *Router {
+	router, err := NewRouter(swagger)
+	if err != nil {
+		panic(err)
+	}
+	return router
+}
+
+// FindRoute returns the route which a request with the given method and URL
+// matches, and the values of the path parameters of its server and path.
+// Requests which match no route get an *openapi3filter.RouteError.
+func (router *Router) FindRoute(method string, url *url.URL) (*openapi3filter.Route, map[string]string, error) {
+	var server *openapi3.Server
+	var serverPattern *serverPattern
+	var serverValues []string
+	remainingPath := url.Path
+	if len(router.servers) > 0 {
+		rawURL := rawURLWithoutQuery(url)
+		for _, pattern := range router.servers {
+			var ok bool
+			if serverValues, remainingPath, ok = pattern.match(rawURL); ok {
+				server = pattern.server
+				serverPattern = pattern
+				break
+			}
+		}
+		if server == nil {
+			return nil, nil, &openapi3filter.RouteError{
+				Route: openapi3filter.Route{
+					Swagger: router.swagger,
+				},
+				Reason: "Does not match any server",
+			}
+		}
+	}
+
+	var node *routeNode
+	var paramValues []string
+	if root := router.roots[method]; root != nil {
+		node, paramValues = root.match(strings.TrimRight(remainingPath, "/"), make([]string, 0, 8))
+	}
+	if node == nil {
+		reason := "Path was not found"
+		if pathItem := router.swagger.Paths[remainingPath]; pathItem != nil && pathItem.Operations()[method] == nil {
+			reason = "Path doesn't support the HTTP method"
+		}
+		return nil, nil, &openapi3filter.RouteError{
+			Route: openapi3filter.Route{
+				Swagger: router.swagger,
+				Server:  server,
+			},
+			Reason: reason,
+		}
+	}
+
+	pathParams := make(map[string]string, len(serverValues)+len(paramValues))
+	for i, value := range serverValues {
+		pathParams[serverPattern.names[i]] = value
+	}
+	for i, value := range paramValues {
+		pathParams[node.variableNames[i]] = value
+	}
+	return node.route, pathParams, nil
+}
+
+// rawURLWithoutQuery returns the string of url up to its query, which is
+// matched with the URLs of the servers. It's just the escaped path of the
+// URLs of most requests to servers.
+func rawURLWithoutQuery(url *url.URL) string {
+	if url.Scheme == "" && url.Opaque == "" && url.User == nil && url.Host == "" &&
+		strings.HasPrefix(url.Path, "/") && url.Fragment == "" {
+		return url.EscapedPath()
+	}
+	rawURL := url.String()
+	if i := strings.IndexByte(rawURL, '?'); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+	return rawURL
+}
+
+// serverPattern is the URL of a server, split up into its constant parts and
+// variables.
+type serverPattern struct {
+	server *openapi3.Server
+	tokens []serverToken
+	names  []string
+
+	// Whether the URL is malformed, so that it matches nothing
+	invalid bool
+}
+
+type serverToken struct {
+	// The constant text, for constant tokens
+	constant string
+
+	variable bool
+
+	// The character which follows a variable in the URL, if any, which ends
+	// its value
+	next    byte
+	hasNext bool
+}
+
+func compileServer(server *openapi3.Server) *serverPattern {
+	pattern := &serverPattern{server: server}
+	names, err := server.ParameterNames()
+	if err != nil {
+		pattern.invalid = true
+		return pattern
+	}
+	pattern.names = names
+
+	url := server.URL
+	for len(url) > 0 {
+		i := strings.IndexByte(url, '{')
+		if i < 0 {
+			i = len(url)
+		}
+		if i > 0 {
+			constant := url[:i]
+			url = url[i:]
+			// A slash ending the URL is left to the path.
+			if url == "" && strings.HasSuffix(constant, "/") {
+				constant = constant[:len(constant)-1]
+			}
+			pattern.tokens = append(pattern.tokens, serverToken{constant: constant})
+			continue
+		}
+		end := strings.IndexByte(url, '}')
+		url = url[end+1:]
+		token := serverToken{variable: true}
+		if len(url) > 0 {
+			token.next = url[0]
+			token.hasNext = true
+		}
+		pattern.tokens = append(pattern.tokens, token)
+	}
+	return pattern
+}
+
+// match matches the start of rawURL with the URL of the server, as
+// openapi3.Server.MatchRawURL does, returning the values of its variables
+// and the rest of rawURL, which is the path.
+func (pattern *serverPattern) match(input string) ([]string, string, bool) {
+	if pattern.invalid {
+		return nil, "", false
+	}
+	var values []string
+	for _, token := range pattern.tokens {
+		if !token.variable {
+			if !strings.HasPrefix(input, token.constant) {
+				return nil, "", false
+			}
+			input = input[len(token.constant):]
+			continue
+		}
+		// A variable ends at the next character of the URL, or at a slash,
+		// whichever comes first.
+		i := strings.IndexByte(input, '/')
+		if token.hasNext {
+			if j := strings.IndexByte(input, token.next); j >= 0 && (i < 0 || j < i) {
+				i = j
+			}
+		}
+		if i < 0 {
+			i = len(input)
+		}
+		values = append(values, input[:i])
+		input = input[i:]
+	}
+	if input == "" {
+		input = "/"
+	}
+	if input[0] != '/' {
+		return nil, "", false
+	}
+	return values, input, true
+}
+
+// routeNode is a node of the tree of the path templates of a method, which
+// are split up at slashes. Children of constant segments are found by
+// looking up the segment, and the others are tried in turn.
+type routeNode struct {
+	// The route of the path template which ends at the node, if any, and
+	// the names of its path parameters
+	route         *openapi3filter.Route
+	variableNames []string
+
+	constants map[string]*routeNode
+	patterns  []*segmentPattern
+}
+
+type segmentTokenKind int
+
+// The order of the kinds is the order in which they're tried.
+const (
+	segmentConstant segmentTokenKind = iota
+	segmentVariable
+	segmentEverything
+)
+
+type segmentToken struct {
+	kind     segmentTokenKind
+	constant string
+}
+
+// segmentPattern is a segment of a path template holding a variable, split
+// up into constants and variables.
+type segmentPattern struct {
+	tokens []segmentToken
+	node   *routeNode
+}
+
+// less orders patterns as openapi3filter.Router tries them: constant text
+// first, longer or later in the alphabet first, then variables, and then
+// variables matching everything.
+func (pattern *segmentPattern) less(other *segmentPattern) bool {
+	for i, token := range pattern.tokens {
+		if i >= len(other.tokens) {
+			return false
+		}
+		otherToken := other.tokens[i]
+		if token.kind != otherToken.kind {
+			return token.kind < otherToken.kind
+		}
+		if token.constant != otherToken.constant {
+			return token.constant > otherToken.constant
+		}
+	}
+	return len(pattern.tokens) < len(other.tokens)
+}
+
+func (pattern *segmentPattern) equal(tokens []segmentToken) bool {
+	if len(pattern.tokens) != len(tokens) {
+		return false
+	}
+	for i, token := range tokens {
+		if pattern.tokens[i] != token {
+			return false
+		}
+	}
+	return true
+}
+
+// add adds the route of a path template to the tree.
+func (node *routeNode) add(path string, route *openapi3filter.Route) error {
+	var variableNames []string
+	remaining := strings.TrimRight(path, "/")
+	for remaining != "" {
+		remaining = strings.TrimPrefix(remaining, "/")
+		segment := remaining
+		if i := strings.IndexByte(remaining, '/'); i >= 0 {
+			segment, remaining = remaining[:i], remaining[i:]
+		} else {
+			remaining = ""
+		}
+		if strings.IndexByte(segment, '{') < 0 {
+			if node.constants == nil {
+				node.constants = make(map[string]*routeNode)
+			}
+			child := node.constants[segment]
+			if child == nil {
+				child = &routeNode{}
+				node.constants[segment] = child
+			}
+			node = child
+			continue
+		}
+
+		// A variable ends at a slash, so the braces of a variable may hold
+		// one, and the segment goes on to the closing brace.
+		for strings.Count(segment, "{") > strings.Count(segment, "}") && remaining != "" {
+			i := strings.IndexByte(remaining[1:], '/')
+			if i < 0 {
+				segment, remaining = segment+remaining, ""
+			} else {
+				segment, remaining = segment+remaining[:i+1], remaining[i+1:]
+			}
+		}
+		tokens, names, err := compileSegment(segment)
+		if err != nil {
+			return fmt.Errorf("%s in: %s", err, path)
+		}
+		variableNames = append(variableNames, names...)
+		node = node.patternNode(tokens)
+	}
+	node.route = route
+	node.variableNames = variableNames
+	return nil
+}
+
+// patternNode returns the child of node for the given pattern, adding it if
+// it's missing.
+func (node *routeNode) patternNode(tokens []segmentToken) *routeNode {
+	for _, pattern := range node.patterns {
+		if pattern.equal(tokens) {
+			return pattern.node
+		}
+	}
+	pattern := &segmentPattern{tokens: tokens, node: &routeNode{}}
+	node.patterns = append(node.patterns, pattern)
+	sort.SliceStable(node.patterns, func(i, j int) bool {
+		return node.patterns[i].less(node.patterns[j])
+	})
+	return pattern.node
+}
+
+// compileSegment splits a segment of a path template into constants and
+// variables, returning the names of the variables.
+func compileSegment(segment string) ([]segmentToken, []string, error) {
+	var tokens []segmentToken
+	var names []string
+	for segment != "" {
+		if segment[0] != '{' {
+			i := strings.IndexByte(segment, '{')
+			if i < 0 {
+				i = len(segment)
+			}
+			tokens = append(tokens, segmentToken{kind: segmentConstant, constant: segment[:i]})
+			segment = segment[i:]
+			continue
+		}
+		i := strings.IndexByte(segment, '}')
+		if i < 0 {
+			return nil, nil, fmt.Errorf("Missing '}'")
+		}
+		name := strings.TrimSpace(segment[1:i])
+		segment = segment[i+1:]
+		kind := segmentVariable
+		if strings.HasSuffix(name, "*") {
+			kind = segmentEverything
+			name = name[:len(name)-1]
+		}
+		tokens = append(tokens, segmentToken{kind: kind})
+		names = append(names, name)
+	}
+	return tokens, names, nil
+}
+
+// match finds the route matching the rest of a path, following node, with
+// its trailing slashes removed. Like openapi3filter.Router, it lets the end
+// of the path match empty segments, so that variables ending a path
+// template may be empty.
+func (node *routeNode) match(remaining string, values []string) (*routeNode, []string) {
+	if remaining == "" && node.route != nil {
+		return node, values
+	}
+
+	if remaining != "" {
+		if remaining[0] != '/' {
+			return nil, nil
+		}
+		remaining = remaining[1:]
+	}
+	segment, rest := remaining, ""
+	if i := strings.IndexByte(remaining, '/'); i >= 0 {
+		segment, rest = remaining[:i], remaining[i:]
+	}
+
+	if child := node.constants[segment]; child != nil {
+		if found, foundValues := child.match(rest, values); found != nil {
+			return found, foundValues
+		}
+	}
+	for _, pattern := range node.patterns {
+		if found, foundValues := pattern.match(segment, rest, values); found != nil {
+			return found, foundValues
+		}
+	}
+	return nil, nil
+}
+
+func (pattern *segmentPattern) match(segment, rest string, values []string) (*routeNode, []string) {
+	for _, token := range pattern.tokens {
+		switch token.kind {
+		case segmentConstant:
+			if !strings.HasPrefix(segment, token.constant) {
+				return nil, nil
+			}
+			segment = segment[len(token.constant):]
+		case segmentVariable:
+			values = append(values, segment)
+			segment = ""
+		case segmentEverything:
+			if pattern.node.route == nil {
+				return nil, nil
+			}
+			return pattern.node, append(values, segment+rest)
+		}
+	}
+	if segment != "" {
+		return nil, nil
+	}
+	return pattern.node.match(rest, values)
+}
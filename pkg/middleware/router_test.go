@@ -0,0 +1,231 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRouterSchema = `openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: TestServer
+servers:
+  - url: https://{region}.example.com/v1/
+    variables:
+      region:
+        default: us
+  - url: /api
+paths:
+  /:
+    get:
+      responses: {default: {description: ok}}
+  /pets:
+    get:
+      responses: {default: {description: ok}}
+    post:
+      responses: {default: {description: ok}}
+  /pets/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses: {default: {description: ok}}
+    delete:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses: {default: {description: ok}}
+  /pets/mine:
+    get:
+      responses: {default: {description: ok}}
+  /pets/{id}/toys/{toy}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+        - {name: toy, in: path, required: true, schema: {type: string}}
+      responses: {default: {description: ok}}
+  /pet{suffix}:
+    get:
+      parameters:
+        - {name: suffix, in: path, required: true, schema: {type: string}}
+      responses: {default: {description: ok}}
+  /files/{path*}:
+    get:
+      parameters:
+        - {name: path, in: path, required: true, schema: {type: string}}
+      responses: {default: {description: ok}}
+  /files/readme:
+    get:
+      responses: {default: {description: ok}}
+  /reports/{id}.json:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses: {default: {description: ok}}
+`
+
+// The requests which the routers are compared on, including some exposing
+// the quirks of openapi3filter.Router.
+var testRouterRequests = []struct {
+	method string
+	url    string
+}{
+	{"GET", "/api"},
+	{"GET", "/api/"},
+	{"GET", "/api/pets"},
+	{"GET", "/api/pets/"},
+	{"POST", "/api/pets"},
+	{"PUT", "/api/pets"},
+	{"get", "/api/pets/1"},
+	{"GET", "/api/pets/1"},
+	{"DELETE", "/api/pets/1"},
+	{"PATCH", "/api/pets/1"},
+	{"GET", "/api/pets/mine"},
+	{"DELETE", "/api/pets/mine"},
+	{"GET", "/api/pets/1/toys/ball"},
+	{"GET", "/api/pets/1/toys"},
+	{"GET", "/api/pets/1/toys/ball/red"},
+	{"GET", "/api/pets//toys/ball"},
+	{"GET", "/api/petsy"},
+	{"GET", "/api/pet"},
+	{"GET", "/api/files/readme"},
+	{"GET", "/api/files/a/b/c"},
+	{"GET", "/api/files"},
+	{"GET", "/api/reports/1.json"},
+	{"GET", "/api/reports/{id}.json"},
+	{"GET", "/apipets"},
+	{"GET", "/other/pets"},
+	{"GET", "https://eu.example.com/v1/pets/2?q=1"},
+	{"GET", "https://eu.example.com/v1"},
+	{"GET", "https://eu.example.com/v2/pets"},
+	{"GET", "https://example.com/v1/pets"},
+	{"GET", "/api/pets%2Fmine"},
+}
+
+func assertSameRoutes(t *testing.T, swagger *openapi3.Swagger) {
+	expected := openapi3filter.NewRouter().WithSwagger(swagger)
+	actual, err := NewRouter(swagger)
+	require.NoError(t, err)
+
+	for _, r := range testRouterRequests {
+		u, err := url.Parse(r.url)
+		require.NoError(t, err)
+		name := r.method + " " + r.url
+
+		actualRoute, actualParams, actualErr := actual.FindRoute(r.method, u)
+		var expectedRoute *openapi3filter.Route
+		var expectedParams map[string]string
+		var expectedErr error
+		panicked := func() (panicked bool) {
+			defer func() {
+				panicked = recover() != nil
+			}()
+			expectedRoute, expectedParams, expectedErr = expected.FindRoute(r.method, u)
+			return false
+		}()
+		if panicked {
+			// openapi3filter.Router crashes on some quirky paths, where the
+			// compiled router finds no route.
+			assert.IsType(t, &openapi3filter.RouteError{}, actualErr, name)
+			continue
+		}
+		assert.Equal(t, expectedErr, actualErr, name)
+		assert.Equal(t, expectedParams, actualParams, name)
+		if expectedRoute == nil {
+			assert.Nil(t, actualRoute, name)
+			continue
+		}
+		if assert.NotNil(t, actualRoute, name) {
+			assert.Equal(t, expectedRoute.Method, actualRoute.Method, name)
+			assert.Equal(t, expectedRoute.Path, actualRoute.Path, name)
+			assert.Equal(t, expectedRoute.Operation, actualRoute.Operation, name)
+			assert.Equal(t, expectedRoute.PathItem, actualRoute.PathItem, name)
+		}
+	}
+}
+
+func TestRouter(t *testing.T) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testRouterSchema))
+	require.NoError(t, err, "Error initializing swagger")
+	assertSameRoutes(t, swagger)
+
+	// Without servers, the path of the URL is matched as it is.
+	swagger.Servers = nil
+	for i, r := range testRouterRequests {
+		testRouterRequests[i].url = strings.Replace(r.url, "/api", "", 1)
+	}
+	assertSameRoutes(t, swagger)
+
+	router, err := NewRouter(swagger)
+	require.NoError(t, err)
+	route, params, err := router.FindRoute("GET", &url.URL{Path: "/pets/7/toys/ball"})
+	require.NoError(t, err)
+	assert.Equal(t, "/pets/{id}/toys/{toy}", route.Path)
+	assert.Equal(t, map[string]string{"id": "7", "toy": "ball"}, params)
+}
+
+// newBenchmarkSwagger returns a spec with the given number of resources,
+// each of which has 4 operations, on 2 paths.
+func newBenchmarkSwagger(b *testing.B, resources int) *openapi3.Swagger {
+	var spec strings.Builder
+	spec.WriteString("openapi: \"3.0.0\"\ninfo: {version: 1.0.0, title: Bench}\nservers:\n  - url: /api\npaths:\n")
+	for i := 0; i < resources; i++ {
+		fmt.Fprintf(&spec, "  /resources%d:\n", i)
+		spec.WriteString("    get: {responses: {default: {description: ok}}}\n")
+		spec.WriteString("    post: {responses: {default: {description: ok}}}\n")
+		fmt.Fprintf(&spec, "  /resources%d/{id}:\n", i)
+		spec.WriteString("    parameters: [{name: id, in: path, required: true, schema: {type: string}}]\n")
+		spec.WriteString("    get: {responses: {default: {description: ok}}}\n")
+		spec.WriteString("    delete: {responses: {default: {description: ok}}}\n")
+	}
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(spec.String()))
+	require.NoError(b, err)
+	return swagger
+}
+
+func benchmarkFindRoute(b *testing.B, router RouteFinder, resources int) {
+	urls := make([]*url.URL, resources)
+	for i := range urls {
+		urls[i] = &url.URL{Path: fmt.Sprintf("/api/resources%d/%d", i, i)}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := router.FindRoute("DELETE", urls[i%len(urls)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindRoute(b *testing.B) {
+	for _, resources := range []int{10, 100, 1000} {
+		swagger := newBenchmarkSwagger(b, resources)
+		operations := fmt.Sprintf("%dOperations", 4*resources)
+		b.Run(operations+"/openapi3filter", func(b *testing.B) {
+			benchmarkFindRoute(b, openapi3filter.NewRouter().WithSwagger(swagger), resources)
+		})
+		b.Run(operations+"/compiled", func(b *testing.B) {
+			router, err := NewRouter(swagger)
+			require.NoError(b, err)
+			benchmarkFindRoute(b, router, resources)
+		})
+	}
+}
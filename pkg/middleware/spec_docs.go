@@ -0,0 +1,460 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"encoding/json"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// The HTML reference page of a spec is rendered from these descriptions of
+// its parts.
+
+type specDocs struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     openapi3.Servers
+	Tags        []*specDocsTag
+	Schemas     []*specDocsSchema
+}
+
+type specDocsTag struct {
+	Name        string
+	Description string
+	Operations  []*specDocsOperation
+}
+
+type specDocsOperation struct {
+	Anchor      string
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	Deprecated  bool
+	Security    []string
+	Parameters  []*specDocsParameter
+	RequestBody *specDocsBody
+	Responses   []*specDocsBody
+}
+
+type specDocsParameter struct {
+	Name        string
+	In          string
+	Required    bool
+	Description string
+	Schema      *specDocsSchema
+}
+
+// specDocsBody describes a request body, or a response with its status.
+type specDocsBody struct {
+	Status      string
+	Description string
+	Required    bool
+	Content     []*specDocsContent
+}
+
+type specDocsContent struct {
+	MediaType string
+	Schema    *specDocsSchema
+}
+
+// specDocsSchema describes a schema, which is either a reference to a
+// component schema, by its Name, or a schema of its own.
+type specDocsSchema struct {
+	Name                 string
+	Type                 string
+	Description          string
+	Enum                 []string
+	Default              string
+	Items                *specDocsSchema
+	Properties           []*specDocsProperty
+	AdditionalProperties *specDocsSchema
+	Compositions         []*specDocsComposition
+}
+
+// specDocsComposition describes the allOf, oneOf or anyOf schemas of a
+// schema.
+type specDocsComposition struct {
+	Kind     string
+	Variants []*specDocsSchema
+}
+
+type specDocsProperty struct {
+	Name     string
+	Required bool
+	Schema   *specDocsSchema
+}
+
+const componentSchemaPrefix = "#/components/schemas/"
+
+// specDocsMaxDepth bounds the nesting of inline schemas.
+const specDocsMaxDepth = 16
+
+var specDocsMethods = []string{
+	"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE", "CONNECT",
+}
+
+// describeSpecDocs describes the parts of a spec which its reference page
+// shows: its operations, grouped by their first tag in the order of the tags
+// of the spec, and its component schemas.
+func describeSpecDocs(swagger *openapi3.Swagger) *specDocs {
+	docs := &specDocs{Servers: swagger.Servers}
+	if swagger.Info != nil {
+		docs.Title = swagger.Info.Title
+		docs.Version = swagger.Info.Version
+		docs.Description = swagger.Info.Description
+	}
+
+	tags := map[string]*specDocsTag{}
+	for _, tag := range swagger.Tags {
+		tags[tag.Name] = &specDocsTag{Name: tag.Name, Description: tag.Description}
+		docs.Tags = append(docs.Tags, tags[tag.Name])
+	}
+	var undeclared []*specDocsTag
+
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		for _, method := range specDocsMethods {
+			operation := pathItem.GetOperation(method)
+			if operation == nil {
+				continue
+			}
+			name := "default"
+			if len(operation.Tags) > 0 {
+				name = operation.Tags[0]
+			}
+			tag := tags[name]
+			if tag == nil {
+				tag = &specDocsTag{Name: name}
+				tags[name] = tag
+				undeclared = append(undeclared, tag)
+			}
+			tag.Operations = append(tag.Operations, describeOperationDocs(swagger, path, pathItem, method, operation))
+		}
+	}
+	sort.Slice(undeclared, func(i, j int) bool { return undeclared[i].Name < undeclared[j].Name })
+	docs.Tags = append(docs.Tags, undeclared...)
+
+	names := make([]string, 0, len(swagger.Components.Schemas))
+	for name := range swagger.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		schema := describeSchemaDocs(swagger.Components.Schemas[name].Value, 0)
+		schema.Name = name
+		docs.Schemas = append(docs.Schemas, schema)
+	}
+	return docs
+}
+
+func describeOperationDocs(swagger *openapi3.Swagger, path string, pathItem *openapi3.PathItem, method string, operation *openapi3.Operation) *specDocsOperation {
+	docs := &specDocsOperation{
+		Anchor:      "operation-" + strings.ToLower(method) + "-" + path,
+		Method:      method,
+		Path:        path,
+		OperationID: operation.OperationID,
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		Deprecated:  operation.Deprecated,
+	}
+	if operation.OperationID != "" {
+		docs.Anchor = "operation-" + operation.OperationID
+	}
+
+	security := operation.Security
+	if security == nil {
+		security = &swagger.Security
+	}
+	for _, requirement := range *security {
+		var schemes []string
+		for name, scopes := range requirement {
+			if len(scopes) > 0 {
+				name += " (" + strings.Join(scopes, ", ") + ")"
+			}
+			schemes = append(schemes, name)
+		}
+		sort.Strings(schemes)
+		docs.Security = append(docs.Security, strings.Join(schemes, " and "))
+	}
+
+	var parameters openapi3.Parameters
+	for _, parameterRef := range pathItem.Parameters {
+		parameter := parameterRef.Value
+		if operation.Parameters.GetByInAndName(parameter.In, parameter.Name) == nil {
+			parameters = append(parameters, parameterRef)
+		}
+	}
+	parameters = append(parameters, operation.Parameters...)
+	for _, parameterRef := range parameters {
+		parameter := parameterRef.Value
+		parameterDocs := &specDocsParameter{
+			Name:        parameter.Name,
+			In:          parameter.In,
+			Required:    parameter.Required,
+			Description: parameter.Description,
+		}
+		if parameter.Schema != nil {
+			parameterDocs.Schema = describeSchemaRefDocs(parameter.Schema, 0)
+		} else {
+			for _, content := range describeContentDocs(parameter.Content) {
+				parameterDocs.Schema = content.Schema
+			}
+		}
+		docs.Parameters = append(docs.Parameters, parameterDocs)
+	}
+
+	if operation.RequestBody != nil && operation.RequestBody.Value != nil {
+		requestBody := operation.RequestBody.Value
+		docs.RequestBody = &specDocsBody{
+			Description: requestBody.Description,
+			Required:    requestBody.Required,
+			Content:     describeContentDocs(requestBody.Content),
+		}
+	}
+
+	statuses := make([]string, 0, len(operation.Responses))
+	for status := range operation.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		response := operation.Responses[status].Value
+		if response == nil {
+			continue
+		}
+		responseDocs := &specDocsBody{Status: status, Content: describeContentDocs(response.Content)}
+		if response.Description != nil {
+			responseDocs.Description = *response.Description
+		}
+		docs.Responses = append(docs.Responses, responseDocs)
+	}
+	return docs
+}
+
+func describeContentDocs(content openapi3.Content) []*specDocsContent {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+
+	var docs []*specDocsContent
+	for _, mediaType := range mediaTypes {
+		contentDocs := &specDocsContent{MediaType: mediaType}
+		if schema := content[mediaType].Schema; schema != nil {
+			contentDocs.Schema = describeSchemaRefDocs(schema, 0)
+		}
+		docs = append(docs, contentDocs)
+	}
+	return docs
+}
+
+// describeSchemaRefDocs describes a schema, which is only named when it
+// refers to a component schema, since those are described on their own.
+func describeSchemaRefDocs(schemaRef *openapi3.SchemaRef, depth int) *specDocsSchema {
+	if strings.HasPrefix(schemaRef.Ref, componentSchemaPrefix) {
+		return &specDocsSchema{Name: strings.TrimPrefix(schemaRef.Ref, componentSchemaPrefix)}
+	}
+	if schemaRef.Value == nil || depth > specDocsMaxDepth {
+		return &specDocsSchema{Type: "any"}
+	}
+	return describeSchemaDocs(schemaRef.Value, depth)
+}
+
+func describeSchemaDocs(schema *openapi3.Schema, depth int) *specDocsSchema {
+	docs := &specDocsSchema{
+		Type:        schema.Type,
+		Description: schema.Description,
+	}
+	if docs.Type == "" {
+		switch {
+		case len(schema.Properties) > 0:
+			docs.Type = "object"
+		case len(schema.AllOf) == 0 && len(schema.OneOf) == 0 && len(schema.AnyOf) == 0:
+			docs.Type = "any"
+		}
+	}
+	if schema.Format != "" {
+		docs.Type += " (" + schema.Format + ")"
+	}
+	if schema.Nullable {
+		docs.Type += ", nullable"
+	}
+	for _, value := range schema.Enum {
+		docs.Enum = append(docs.Enum, specDocsValue(value))
+	}
+	if schema.Default != nil {
+		docs.Default = specDocsValue(schema.Default)
+	}
+
+	if schema.Items != nil {
+		docs.Items = describeSchemaRefDocs(schema.Items, depth+1)
+	}
+
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		docs.Properties = append(docs.Properties, &specDocsProperty{
+			Name:     name,
+			Required: required[name],
+			Schema:   describeSchemaRefDocs(schema.Properties[name], depth+1),
+		})
+	}
+	if schema.AdditionalProperties != nil {
+		docs.AdditionalProperties = describeSchemaRefDocs(schema.AdditionalProperties, depth+1)
+	}
+
+	for _, composition := range []struct {
+		kind     string
+		variants []*openapi3.SchemaRef
+	}{
+		{"all of", schema.AllOf},
+		{"one of", schema.OneOf},
+		{"any of", schema.AnyOf},
+	} {
+		if len(composition.variants) == 0 {
+			continue
+		}
+		compositionDocs := &specDocsComposition{Kind: composition.kind}
+		for _, variant := range composition.variants {
+			compositionDocs.Variants = append(compositionDocs.Variants, describeSchemaRefDocs(variant, depth+1))
+		}
+		docs.Compositions = append(docs.Compositions, compositionDocs)
+	}
+	return docs
+}
+
+// specDocsValue formats an enum or default value of a schema as JSON.
+func specDocsValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+var specDocsTemplate = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} {{.Version}}</title>
+<style>
+body { margin: 0; font: 15px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; }
+nav { position: fixed; top: 0; bottom: 0; left: 0; width: 260px; overflow-y: auto; padding: 16px; background: #f6f8fa; border-right: 1px solid #d0d7de; box-sizing: border-box; }
+nav ul { list-style: none; margin: 0 0 12px; padding: 0; }
+nav a { color: inherit; text-decoration: none; }
+nav a:hover { text-decoration: underline; }
+main { margin-left: 260px; padding: 16px 32px 64px; max-width: 960px; }
+h1 small { color: #656d76; font-weight: normal; font-size: 60%; }
+section.operation { border: 1px solid #d0d7de; border-radius: 6px; padding: 8px 16px; margin: 16px 0; }
+section.deprecated h3 code.path { text-decoration: line-through; }
+.method { display: inline-block; min-width: 60px; padding: 0 6px; border-radius: 4px; color: #fff; font: bold 12px/20px monospace; text-align: center; background: #6e7781; }
+.method.get { background: #0969da; } .method.post { background: #1a7f37; } .method.put { background: #9a6700; }
+.method.patch { background: #8250df; } .method.delete { background: #cf222e; }
+.description { white-space: pre-wrap; }
+.muted { color: #656d76; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code { font-family: SFMono-Regular, Consolas, monospace; font-size: 90%; }
+</style>
+</head>
+<body>
+<nav>
+<strong>{{.Title}}</strong>
+{{range .Tags}}<p>{{.Name}}</p>
+<ul>{{range .Operations}}
+<li><a href="#{{.Anchor}}"><span class="method {{lower .Method}}">{{.Method}}</span> {{.Path}}</a></li>{{end}}
+</ul>{{end}}
+{{if .Schemas}}<p>Schemas</p>
+<ul>{{range .Schemas}}
+<li><a href="#schema-{{.Name}}">{{.Name}}</a></li>{{end}}
+</ul>{{end}}
+</nav>
+<main>
+<h1>{{.Title}} <small>{{.Version}}</small></h1>
+{{if .Description}}<p class="description">{{.Description}}</p>{{end}}
+{{if .Servers}}<h2>Servers</h2>
+<ul>{{range .Servers}}
+<li><code>{{.URL}}</code>{{if .Description}} {{.Description}}{{end}}</li>{{end}}
+</ul>{{end}}
+{{range .Tags}}<h2>{{.Name}}</h2>
+{{if .Description}}<p class="description">{{.Description}}</p>{{end}}
+{{range .Operations}}<section id="{{.Anchor}}" class="operation{{if .Deprecated}} deprecated{{end}}">
+<h3><span class="method {{lower .Method}}">{{.Method}}</span> <code class="path">{{.Path}}</code></h3>
+{{if .OperationID}}<p class="muted"><code>{{.OperationID}}</code>{{if .Deprecated}}, deprecated{{end}}</p>{{else if .Deprecated}}<p class="muted">Deprecated</p>{{end}}
+{{if .Summary}}<p><strong>{{.Summary}}</strong></p>{{end}}
+{{if .Description}}<p class="description">{{.Description}}</p>{{end}}
+{{if .Security}}<p>Security: {{range $i, $requirement := .Security}}{{if $i}} or {{end}}<code>{{$requirement}}</code>{{end}}</p>{{end}}
+{{if .Parameters}}<h4>Parameters</h4>
+<table>
+<tr><th>Name</th><th>In</th><th>Schema</th><th>Description</th></tr>{{range .Parameters}}
+<tr><td><code>{{.Name}}</code>{{if .Required}} <span class="muted">required</span>{{end}}</td><td>{{.In}}</td><td>{{with .Schema}}{{template "schema" .}}{{end}}</td><td class="description">{{.Description}}</td></tr>{{end}}
+</table>{{end}}
+{{with .RequestBody}}<h4>Request body{{if .Required}} <span class="muted">required</span>{{end}}</h4>
+{{if .Description}}<p class="description">{{.Description}}</p>{{end}}
+{{template "content" .Content}}{{end}}
+{{if .Responses}}<h4>Responses</h4>
+<table>
+<tr><th>Status</th><th>Description</th></tr>{{range .Responses}}
+<tr><td><code>{{.Status}}</code></td><td><span class="description">{{.Description}}</span>{{template "content" .Content}}</td></tr>{{end}}
+</table>{{end}}
+</section>
+{{end}}{{end}}
+{{if .Schemas}}<h2>Schemas</h2>
+{{range .Schemas}}<section id="schema-{{.Name}}" class="operation">
+<h3>{{.Name}}</h3>
+{{template "schema-body" .}}
+</section>
+{{end}}{{end}}
+</main>
+</body>
+</html>
+{{define "content"}}{{range .}}
+<p><code>{{.MediaType}}</code>{{with .Schema}}: {{template "schema" .}}{{end}}</p>{{end}}{{end}}
+{{define "schema"}}{{if .Name}}<a href="#schema-{{.Name}}"><code>{{.Name}}</code></a>{{else}}{{template "schema-body" .}}{{end}}{{end}}
+{{define "schema-body"}}{{if .Type}}<code>{{.Type}}</code>{{end}}{{with .Items}} of {{template "schema" .}}{{end}}
+{{- if .Enum}} <span class="muted">one of</span> {{range $i, $value := .Enum}}{{if $i}}, {{end}}<code>{{$value}}</code>{{end}}{{end}}
+{{- if .Default}} <span class="muted">default</span> <code>{{.Default}}</code>{{end}}
+{{- if .Description}}<div class="description">{{.Description}}</div>{{end}}
+{{- range .Compositions}}<div>{{.Kind}}:<ul>{{range .Variants}}<li>{{template "schema" .}}</li>{{end}}</ul></div>{{end}}
+{{- if .Properties}}<table>
+<tr><th>Property</th><th>Schema</th></tr>{{range .Properties}}
+<tr><td><code>{{.Name}}</code>{{if .Required}} <span class="muted">required</span>{{end}}</td><td>{{template "schema" .Schema}}</td></tr>{{end}}
+</table>{{end}}
+{{- with .AdditionalProperties}}<div>Additional properties: {{template "schema" .}}</div>{{end}}{{end}}
+`))
@@ -0,0 +1,106 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v2"
+)
+
+// specDocument is a representation of the spec served by a SpecHandler.
+type specDocument struct {
+	contentType string
+	data        []byte
+	etag        string
+}
+
+func newSpecDocument(contentType string, data []byte) *specDocument {
+	sum := sha256.Sum256(data)
+	return &specDocument{
+		contentType: contentType,
+		data:        data,
+		etag:        fmt.Sprintf(`"%x"`, sum[:16]),
+	}
+}
+
+// SpecHandler serves a spec as JSON to requests for paths ending in .json,
+// as YAML to those ending in .yaml or .yml, and as an HTML reference page,
+// which needs nothing but itself, to the others. Responses carry an ETag,
+// so that clients can revalidate their copies with If-None-Match.
+type SpecHandler struct {
+	json *specDocument
+	yaml *specDocument
+	html *specDocument
+}
+
+// NewSpecHandler returns the handler which serves the given spec, in JSON,
+// rendering its YAML and HTML representations once and for all.
+func NewSpecHandler(spec []byte) (*SpecHandler, error) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("error loading Swagger: %s", err)
+	}
+
+	// JSON is YAML, and decoding it into a MapSlice keeps the order of its
+	// keys.
+	var document yaml.MapSlice
+	if err := yaml.Unmarshal(spec, &document); err != nil {
+		return nil, fmt.Errorf("error converting spec to YAML: %s", err)
+	}
+	yamlSpec, err := yaml.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("error converting spec to YAML: %s", err)
+	}
+
+	var page bytes.Buffer
+	if err := specDocsTemplate.Execute(&page, describeSpecDocs(swagger)); err != nil {
+		return nil, fmt.Errorf("error rendering spec reference: %s", err)
+	}
+
+	return &SpecHandler{
+		json: newSpecDocument("application/json", spec),
+		yaml: newSpecDocument("application/yaml", yamlSpec),
+		html: newSpecDocument("text/html; charset=utf-8", page.Bytes()),
+	}, nil
+}
+
+func (h *SpecHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	document := h.html
+	switch {
+	case strings.HasSuffix(r.URL.Path, ".json"):
+		document = h.json
+	case strings.HasSuffix(r.URL.Path, ".yaml"), strings.HasSuffix(r.URL.Path, ".yml"):
+		document = h.yaml
+	}
+
+	// ServeContent answers conditional requests against the ETag.
+	w.Header().Set("Content-Type", document.contentType)
+	w.Header().Set("ETag", document.etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(document.data))
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+var testSpecHandlerSchema = `openapi: "3.0.0"
+info:
+  version: 1.0.0
+  title: Things <API>
+  description: Manages things & their owners.
+tags:
+  - name: things
+    description: Things
+paths:
+  /things/{id}:
+    get:
+      operationId: getThing
+      tags: [things]
+      summary: Gets a thing
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+            format: int64
+        - name: view
+          in: query
+          schema:
+            type: string
+            enum: [full, brief]
+            default: brief
+      responses:
+        '200':
+          description: The thing
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Thing'
+    delete:
+      operationId: deleteThing
+      deprecated: true
+      responses:
+        '204':
+          description: Deleted
+components:
+  schemas:
+    Thing:
+      required: [name]
+      properties:
+        name:
+          type: string
+          description: The name of the thing, eg, <script>alert(1)</script>
+        owner:
+          oneOf:
+            - type: string
+            - $ref: '#/components/schemas/Thing'
+`
+
+func newTestSpecHandler(t *testing.T) (*SpecHandler, []byte) {
+	swagger, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testSpecHandlerSchema))
+	require.NoError(t, err)
+	spec, err := swagger.MarshalJSON()
+	require.NoError(t, err)
+	handler, err := NewSpecHandler(spec)
+	require.NoError(t, err)
+	return handler, spec
+}
+
+func serveSpec(handler http.Handler, method, path, etag string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSpecHandler(t *testing.T) {
+	handler, spec := newTestSpecHandler(t)
+
+	rec := serveSpec(handler, http.MethodGet, "/openapi.json", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, spec, rec.Body.Bytes())
+	jsonETag := rec.Header().Get("ETag")
+	assert.NotEmpty(t, jsonETag)
+
+	// The YAML is the same document, with its keys in the same order.
+	rec = serveSpec(handler, http.MethodGet, "/api/openapi.yaml", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+	var fromYAML, fromJSON map[string]interface{}
+	var document yaml.MapSlice
+	require.NoError(t, yaml.Unmarshal(rec.Body.Bytes(), &document))
+	assert.Equal(t, "components", document[0].Key)
+	fromYAMLSpec, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData(rec.Body.Bytes())
+	require.NoError(t, err)
+	fromYAMLJSON, err := fromYAMLSpec.MarshalJSON()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(fromYAMLJSON, &fromYAML))
+	require.NoError(t, json.Unmarshal(spec, &fromJSON))
+	assert.Equal(t, fromJSON, fromYAML)
+	assert.NotEqual(t, jsonETag, rec.Header().Get("ETag"))
+
+	// Clients revalidate their copies with the ETag.
+	rec = serveSpec(handler, http.MethodGet, "/openapi.json", jsonETag)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.Bytes())
+	rec = serveSpec(handler, http.MethodGet, "/openapi.json", `"stale"`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serveSpec(handler, http.MethodHead, "/openapi.json", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.Bytes())
+
+	rec = serveSpec(handler, http.MethodPost, "/openapi.json", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+}
+
+func TestSpecHandlerDocs(t *testing.T) {
+	handler, _ := newTestSpecHandler(t)
+
+	rec := serveSpec(handler, http.MethodGet, "/docs", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	page := rec.Body.String()
+
+	assert.Contains(t, page, "<title>Things &lt;API&gt; 1.0.0</title>")
+	assert.Contains(t, page, "Manages things &amp; their owners.")
+	assert.Contains(t, page, `<section id="operation-getThing" class="operation">`)
+	assert.Contains(t, page, `<section id="operation-deleteThing" class="operation deprecated">`)
+	assert.Contains(t, page, "<code>integer (int64)</code>")
+	assert.Contains(t, page, `<span class="muted">one of</span> <code>&#34;full&#34;</code>, <code>&#34;brief&#34;</code>`)
+	assert.Contains(t, page, `<a href="#schema-Thing"><code>Thing</code></a>`)
+	assert.Contains(t, page, `<section id="schema-Thing" class="operation">`)
+	assert.Contains(t, page, "one of:<ul><li><code>string</code></li>")
+
+	// Operations are grouped by tag, in the order of the tags of the spec.
+	assert.Regexp(t, `(?s)<h2>things</h2>.*operation-getThing.*<h2>default</h2>.*operation-deleteThing`, page)
+
+	// Descriptions are escaped, and the page needs nothing but itself.
+	assert.NotContains(t, page, "<script")
+	assert.NotContains(t, page, "src=")
+	assert.NotContains(t, page, "<link")
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/types"
+)
+
+// MarshalForm turns a struct, or a pointer to one, into form values suitable
+// for an application/x-www-form-urlencoded request body. Fields are named
+// after their json tags. Nil pointers are omitted, arrays produce one value
+// per element, and nested objects are encoded as JSON, which is the default
+// content type for object properties of form bodies in the OpenAPI spec.
+func MarshalForm(v interface{}) (url.Values, error) {
+	sv := reflect.Indirect(reflect.ValueOf(v))
+	if sv.Kind() != reflect.Struct {
+		return nil, errors.New("form bodies can only be marshaled from structs")
+	}
+	t := sv.Type()
+
+	form := url.Values{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !isFormField(field) {
+			continue
+		}
+		name := getFieldName(field)
+		fv := sv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if fv.Kind() == reflect.Slice {
+			for j := 0; j < fv.Len(); j++ {
+				s, err := formValueToString(fv.Index(j))
+				if err != nil {
+					return nil, fmt.Errorf("error marshaling form field '%s': %s", name, err)
+				}
+				form.Add(name, s)
+			}
+			continue
+		}
+		s, err := formValueToString(fv)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling form field '%s': %s", name, err)
+		}
+		form.Add(name, s)
+	}
+	return form, nil
+}
+
+// BindForm binds form values, such as those from a parsed
+// application/x-www-form-urlencoded request body, to the struct which dst
+// points to. It is the inverse of MarshalForm. Fields with no value in the
+// form are left untouched.
+func BindForm(form url.Values, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("form bodies can only be bound to a pointer to a struct")
+	}
+	sv := v.Elem()
+	t := sv.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !isFormField(field) {
+			continue
+		}
+		name := getFieldName(field)
+		values, found := form[name]
+		if !found || len(values) == 0 {
+			continue
+		}
+
+		err := bindFormField(sv.Field(i), len(values), func(j int, v reflect.Value) error {
+			return bindFormValue(values[j], v)
+		})
+		if err != nil {
+			return fmt.Errorf("error binding form field '%s': %s", name, err)
+		}
+	}
+	return nil
+}
+
+// bindFormField assigns n values to the struct field fv, using bind to set
+// each one. Slice fields receive all values, other fields only the first one,
+// and pointer fields are allocated as needed.
+func bindFormField(fv reflect.Value, n int, bind func(i int, v reflect.Value) error) error {
+	target := fv
+	if fv.Kind() == reflect.Ptr {
+		target = reflect.New(fv.Type().Elem()).Elem()
+	}
+
+	if target.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(target.Type(), n, n)
+		for i := 0; i < n; i++ {
+			if err := bind(i, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+	} else if err := bind(0, target); err != nil {
+		return err
+	}
+
+	if fv.Kind() == reflect.Ptr {
+		fv.Set(target.Addr())
+	}
+	return nil
+}
+
+// isFormField returns whether a struct field takes part in form encoding.
+// Unexported fields are skipped, as are those tagged with json:"-", such as
+// AdditionalProperties.
+func isFormField(field reflect.StructField) bool {
+	if field.PkgPath != "" {
+		return false
+	}
+	return field.Tag.Get("json") != "-"
+}
+
+// formValueToString converts a single value to its form representation.
+func formValueToString(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339Nano), nil
+	case types.Date:
+		return value.Format(types.DateFormat), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(v.Uint()), nil
+	case reflect.Int16:
+		return fmt.Sprint(v.Int()), nil
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Interface:
+		buf, err := json.Marshal(v.Interface())
+		if err != nil {
+			return "", err
+		}
+		return string(buf), nil
+	}
+	return primitiveToString(v.Interface())
+}
+
+// bindFormValue binds a single form value to v, which must be settable.
+func bindFormValue(value string, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := bindFormValue(value, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	ptr := v.Addr().Interface()
+	switch ptr.(type) {
+	case *time.Time, *types.Date:
+		return BindStringToObject(value, ptr)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Interface:
+		return json.Unmarshal([]byte(value), ptr)
+	}
+	return BindStringToObject(value, ptr)
+}
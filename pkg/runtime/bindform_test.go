@@ -0,0 +1,107 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/types"
+)
+
+type formOwner struct {
+	Name string `json:"name"`
+}
+
+type formBody struct {
+	Name     string            `json:"name"`
+	Age      *int              `json:"age,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Born     *types.Date       `json:"born,omitempty"`
+	Seen     *time.Time        `json:"seen,omitempty"`
+	Owner    *formOwner        `json:"owner,omitempty"`
+	Missing  *string           `json:"missing,omitempty"`
+	Extra    map[string]string `json:"-"`
+	internal string
+}
+
+func TestMarshalForm(t *testing.T) {
+	age := 3
+	seen := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	body := formBody{
+		Name:  "Fido",
+		Age:   &age,
+		Tags:  []string{"dog", "good"},
+		Born:  &types.Date{Time: time.Date(2017, 5, 6, 0, 0, 0, 0, time.UTC)},
+		Seen:  &seen,
+		Owner: &formOwner{Name: "Alice"},
+		Extra: map[string]string{"ignored": "true"},
+	}
+
+	form, err := MarshalForm(body)
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"name":  {"Fido"},
+		"age":   {"3"},
+		"tags":  {"dog", "good"},
+		"born":  {"2017-05-06"},
+		"seen":  {"2020-01-02T03:04:05Z"},
+		"owner": {`{"name":"Alice"}`},
+	}, form)
+
+	// Pointers to structs work the same way.
+	form2, err := MarshalForm(&body)
+	require.NoError(t, err)
+	assert.Equal(t, form, form2)
+
+	_, err = MarshalForm("not a struct")
+	assert.Error(t, err)
+}
+
+func TestBindForm(t *testing.T) {
+	form := url.Values{
+		"name":  {"Fido"},
+		"age":   {"3"},
+		"tags":  {"dog", "good"},
+		"born":  {"2017-05-06"},
+		"seen":  {"2020-01-02T03:04:05Z"},
+		"owner": {`{"name":"Alice"}`},
+	}
+
+	var body formBody
+	require.NoError(t, BindForm(form, &body))
+	assert.Equal(t, "Fido", body.Name)
+	require.NotNil(t, body.Age)
+	assert.Equal(t, 3, *body.Age)
+	assert.Equal(t, []string{"dog", "good"}, body.Tags)
+	require.NotNil(t, body.Born)
+	assert.Equal(t, "2017-05-06", body.Born.Format(types.DateFormat))
+	require.NotNil(t, body.Seen)
+	assert.True(t, body.Seen.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+	require.NotNil(t, body.Owner)
+	assert.Equal(t, "Alice", body.Owner.Name)
+	assert.Nil(t, body.Missing)
+
+	// Round trip through MarshalForm
+	roundTrip, err := MarshalForm(body)
+	require.NoError(t, err)
+	assert.Equal(t, form, roundTrip)
+
+	assert.Error(t, BindForm(url.Values{"age": {"old"}}, &body))
+	assert.Error(t, BindForm(form, body))
+}
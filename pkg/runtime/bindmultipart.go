@@ -0,0 +1,344 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/types"
+)
+
+// MaxMultipartMemory is the number of bytes of a multipart/form-data body
+// which generated servers keep in memory while parsing it. Servers parse the
+// whole body of operations without file parts before calling the handler,
+// and store any parts which don't fit in temporary files on disk. Bodies
+// with file parts are streamed to the handler with a MultipartReader.
+var MaxMultipartMemory int64 = 32 << 20
+
+// MultipartEncoding describes how a part of a multipart/form-data body is
+// encoded, as given by the encoding object for the property in the spec.
+type MultipartEncoding struct {
+	// ContentType is a comma separated list of the media types allowed for
+	// the part, which may contain wildcards such as image/*.
+	ContentType string
+
+	// Headers maps the names of the headers described for the part to
+	// whether they are required.
+	Headers map[string]bool
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// NewMultipartBody returns a multipart/form-data body for the struct v,
+// along with its content type, which includes the boundary. The body is
+// written by a separate goroutine as it is read, so that file parts are
+// streamed rather than buffered in memory. The goroutine is started by the
+// first Read, so a body which is never read doesn't leak it, and stopped by
+// Close. Any error writing the body is returned by its Read method.
+func NewMultipartBody(v interface{}, encodings map[string]MultipartEncoding) (string, io.ReadCloser) {
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	body := &multipartBody{
+		PipeReader: pr,
+		write: func() {
+			err := WriteMultipart(w, v, encodings)
+			if err == nil {
+				err = w.Close()
+			}
+			pw.CloseWithError(err)
+		},
+	}
+	return w.FormDataContentType(), body
+}
+
+// multipartBody is the reading end of the pipe which a multipart body is
+// written to, starting the writer on the first Read.
+type multipartBody struct {
+	*io.PipeReader
+	write func()
+	once  sync.Once
+}
+
+func (b *multipartBody) Read(p []byte) (int, error) {
+	b.once.Do(func() { go b.write() })
+	return b.PipeReader.Read(p)
+}
+
+// WriteMultipart writes the fields of the struct v as the parts of a
+// multipart/form-data body, naming them after their json tags. Fields of
+// type types.File become file parts, nested objects are encoded as JSON, and
+// arrays produce a part per element. It doesn't close w.
+func WriteMultipart(w *multipart.Writer, v interface{}, encodings map[string]MultipartEncoding) error {
+	sv := reflect.Indirect(reflect.ValueOf(v))
+	if sv.Kind() != reflect.Struct {
+		return errors.New("multipart bodies can only be written from structs")
+	}
+	t := sv.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !isFormField(field) {
+			continue
+		}
+		name := getFieldName(field)
+		fv := sv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+			for j := 0; j < fv.Len(); j++ {
+				if err := writeMultipartPart(w, name, fv.Index(j), encodings[name]); err != nil {
+					return fmt.Errorf("error writing part '%s': %s", name, err)
+				}
+			}
+			continue
+		}
+		if err := writeMultipartPart(w, name, fv, encodings[name]); err != nil {
+			return fmt.Errorf("error writing part '%s': %s", name, err)
+		}
+	}
+	return nil
+}
+
+func writeMultipartPart(w *multipart.Writer, name string, v reflect.Value, encoding MultipartEncoding) error {
+	header := make(textproto.MIMEHeader)
+
+	if file, ok := v.Interface().(types.File); ok {
+		for k, values := range file.Header {
+			header[k] = values
+		}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(name), quoteEscaper.Replace(file.Filename)))
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = defaultPartContentType(encoding, "application/octet-stream")
+		}
+		header.Set("Content-Type", contentType)
+
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(part, file)
+		return err
+	}
+
+	var content string
+	contentType := defaultPartContentType(encoding, "")
+	if contentType == "application/json" {
+		buf, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		content = string(buf)
+	} else {
+		var err error
+		content, err = formValueToString(v)
+		if err != nil {
+			return err
+		}
+		switch reflect.Indirect(v).Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Interface:
+			if contentType == "" {
+				contentType = "application/json"
+			}
+		}
+	}
+
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, quoteEscaper.Replace(name)))
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, content)
+	return err
+}
+
+// defaultPartContentType returns the content type to send for a part, which
+// is the first one the encoding allows, unless that's a wildcard.
+func defaultPartContentType(encoding MultipartEncoding, fallback string) string {
+	if encoding.ContentType == "" {
+		return fallback
+	}
+	contentType := strings.TrimSpace(strings.Split(encoding.ContentType, ",")[0])
+	if strings.Contains(contentType, "*") {
+		return fallback
+	}
+	return contentType
+}
+
+// BindMultipart binds a parsed multipart/form-data body to the struct which
+// dst points to, matching parts to fields by their json tags. Fields of type
+// types.File receive the file parts, reading from the files stored by
+// multipart.Form, which should be closed when no longer needed. The content
+// types and headers of file parts are checked against their encodings.
+func BindMultipart(form *multipart.Form, dst interface{}, encodings map[string]MultipartEncoding) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("multipart bodies can only be bound to a pointer to a struct")
+	}
+	sv := v.Elem()
+	t := sv.Type()
+
+	fileType := reflect.TypeOf(types.File{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !isFormField(field) {
+			continue
+		}
+		name := getFieldName(field)
+
+		elemType := field.Type
+		for elemType.Kind() == reflect.Ptr || elemType.Kind() == reflect.Slice {
+			elemType = elemType.Elem()
+		}
+
+		var err error
+		if elemType == fileType {
+			files := form.File[name]
+			if len(files) == 0 {
+				continue
+			}
+			err = bindFormField(sv.Field(i), len(files), func(j int, v reflect.Value) error {
+				file, err := openMultipartFile(files[j], encodings[name])
+				if err != nil {
+					return err
+				}
+				if v.Kind() == reflect.Ptr {
+					v.Set(reflect.ValueOf(&file))
+				} else {
+					v.Set(reflect.ValueOf(file))
+				}
+				return nil
+			})
+		} else {
+			values := form.Value[name]
+			if len(values) == 0 {
+				continue
+			}
+			err = bindFormField(sv.Field(i), len(values), func(j int, v reflect.Value) error {
+				return bindFormValue(values[j], v)
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("error binding part '%s': %s", name, err)
+		}
+	}
+	return nil
+}
+
+func openMultipartFile(fh *multipart.FileHeader, encoding MultipartEncoding) (types.File, error) {
+	if err := checkPartEncoding(fh.Header, encoding); err != nil {
+		return types.File{}, err
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return types.File{}, err
+	}
+	return types.File{
+		Filename:    fh.Filename,
+		ContentType: fh.Header.Get("Content-Type"),
+		Header:      fh.Header,
+		Reader:      f,
+	}, nil
+}
+
+// checkPartEncoding checks the content type and headers of a file part
+// against its encoding.
+func checkPartEncoding(header textproto.MIMEHeader, encoding MultipartEncoding) error {
+	contentType := header.Get("Content-Type")
+	if encoding.ContentType != "" && !contentTypeAllowed(contentType, encoding.ContentType) {
+		return fmt.Errorf("content type '%s' is not one of '%s'", contentType, encoding.ContentType)
+	}
+	for name, required := range encoding.Headers {
+		if required && header.Get(name) == "" {
+			return fmt.Errorf("header '%s' is required", name)
+		}
+	}
+	return nil
+}
+
+// MultipartReader streams the parts of a multipart/form-data request body.
+// Generated servers pass one to the handlers of operations whose bodies have
+// file parts, rather than parsing the whole body first, so that handlers can
+// process large files as they arrive. Parts are read in the order the client
+// sent them with NextPart, which checks file parts against their encodings.
+type MultipartReader struct {
+	*multipart.Reader
+	encodings map[string]MultipartEncoding
+}
+
+// NewMultipartReader returns a MultipartReader for the body of r, which must
+// be a multipart/form-data request whose body hasn't been read yet.
+func NewMultipartReader(r *http.Request, encodings map[string]MultipartEncoding) (*MultipartReader, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	return &MultipartReader{Reader: mr, encodings: encodings}, nil
+}
+
+// NextPart returns the next part of the body, or io.EOF when there are no
+// more parts. It returns an error for file parts whose content type or
+// headers don't match their encoding.
+func (m *MultipartReader) NextPart() (*multipart.Part, error) {
+	part, err := m.Reader.NextPart()
+	if err != nil {
+		return nil, err
+	}
+	if part.FileName() != "" {
+		if err := checkPartEncoding(part.Header, m.encodings[part.FormName()]); err != nil {
+			return nil, fmt.Errorf("error reading part '%s': %s", part.FormName(), err)
+		}
+	}
+	return part, nil
+}
+
+// contentTypeAllowed returns whether contentType matches one of the comma
+// separated media ranges in allowed, such as "image/png, image/*".
+func contentTypeAllowed(contentType, allowed string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	mediaType = strings.ToLower(mediaType)
+	for _, pattern := range strings.Split(allowed, ",") {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "*/*" || pattern == mediaType {
+			return true
+		}
+		if strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
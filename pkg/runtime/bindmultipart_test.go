@@ -0,0 +1,182 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	goruntime "runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/leslie-wang/oapi-codegen/pkg/types"
+)
+
+type uploadBody struct {
+	Name        string       `json:"name"`
+	Photo       types.File   `json:"photo"`
+	Attachments []types.File `json:"attachments,omitempty"`
+	Thumbnail   *types.File  `json:"thumbnail,omitempty"`
+	Owner       *formOwner   `json:"owner,omitempty"`
+}
+
+// readMultipart reads a body produced by NewMultipartBody back into a form.
+func readMultipart(t *testing.T, contentType string, body string) *multipart.Form {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	require.Equal(t, "multipart/form-data", mediaType)
+
+	form, err := multipart.NewReader(strings.NewReader(body), params["boundary"]).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form
+}
+
+func TestMultipartRoundTrip(t *testing.T) {
+	encodings := map[string]MultipartEncoding{
+		"photo": {ContentType: "image/png, image/jpeg", Headers: map[string]bool{"X-Checksum": true}},
+	}
+	body := uploadBody{
+		Name: "Fido",
+		Photo: types.File{
+			Filename: "fido.png",
+			Header:   textproto.MIMEHeader{"X-Checksum": {"abc"}},
+			Reader:   strings.NewReader("png data"),
+		},
+		Attachments: []types.File{
+			types.NewFile("a.txt", "text/plain", strings.NewReader("first")),
+			types.NewFile("b.txt", "text/plain", strings.NewReader("second")),
+		},
+		Owner: &formOwner{Name: "Alice"},
+	}
+
+	contentType, reader := NewMultipartBody(body, encodings)
+	buf, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	form := readMultipart(t, contentType, string(buf))
+
+	// The photo has no content type of its own, so it gets the first one
+	// allowed by its encoding.
+	require.Len(t, form.File["photo"], 1)
+	assert.Equal(t, "image/png", form.File["photo"][0].Header.Get("Content-Type"))
+	assert.Equal(t, []string{`{"name":"Alice"}`}, form.Value["owner"])
+
+	var bound uploadBody
+	require.NoError(t, BindMultipart(form, &bound, encodings))
+	assert.Equal(t, "Fido", bound.Name)
+	assert.Equal(t, "fido.png", bound.Photo.Filename)
+	assert.Equal(t, "abc", bound.Photo.Header.Get("X-Checksum"))
+	content, err := bound.Photo.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, "png data", string(content))
+	require.Len(t, bound.Attachments, 2)
+	content, err = bound.Attachments[1].Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+	assert.Equal(t, "text/plain", bound.Attachments[1].ContentType)
+	assert.Nil(t, bound.Thumbnail)
+	require.NotNil(t, bound.Owner)
+	assert.Equal(t, "Alice", bound.Owner.Name)
+}
+
+func TestBindMultipartEncoding(t *testing.T) {
+	body := uploadBody{
+		Photo: types.NewFile("fido.gif", "image/gif", strings.NewReader("gif data")),
+	}
+	contentType, reader := NewMultipartBody(body, nil)
+	buf, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+
+	var bound uploadBody
+	form := readMultipart(t, contentType, string(buf))
+	assert.NoError(t, BindMultipart(form, &bound, map[string]MultipartEncoding{
+		"photo": {ContentType: "image/*"},
+	}))
+	assert.Error(t, BindMultipart(form, &bound, map[string]MultipartEncoding{
+		"photo": {ContentType: "image/png, image/jpeg"},
+	}))
+	assert.Error(t, BindMultipart(form, &bound, map[string]MultipartEncoding{
+		"photo": {Headers: map[string]bool{"X-Checksum": true}},
+	}))
+}
+
+func TestMultipartReader(t *testing.T) {
+	body := uploadBody{
+		Name:  "Fido",
+		Photo: types.NewFile("fido.gif", "image/gif", strings.NewReader("gif data")),
+	}
+	newRequest := func() *http.Request {
+		contentType, reader := NewMultipartBody(body, nil)
+		r := httptest.NewRequest("POST", "/pets", reader)
+		r.Header.Set("Content-Type", contentType)
+		return r
+	}
+
+	mr, err := NewMultipartReader(newRequest(), map[string]MultipartEncoding{
+		"photo": {ContentType: "image/*"},
+	})
+	require.NoError(t, err)
+	part, err := mr.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "name", part.FormName())
+	part, err = mr.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "fido.gif", part.FileName())
+	content, err := ioutil.ReadAll(part)
+	require.NoError(t, err)
+	assert.Equal(t, "gif data", string(content))
+	_, err = mr.NextPart()
+	assert.Equal(t, io.EOF, err)
+
+	// File parts are checked against their encodings as they're read.
+	mr, err = NewMultipartReader(newRequest(), map[string]MultipartEncoding{
+		"photo": {ContentType: "image/png"},
+	})
+	require.NoError(t, err)
+	_, err = mr.NextPart()
+	require.NoError(t, err)
+	_, err = mr.NextPart()
+	assert.EqualError(t, err, "error reading part 'photo': content type 'image/gif' is not one of 'image/png'")
+
+	_, err = NewMultipartReader(httptest.NewRequest("POST", "/pets", strings.NewReader("name=Fido")), nil)
+	assert.Error(t, err)
+}
+
+func TestMultipartBodyUnread(t *testing.T) {
+	// The writer isn't started until the body is read, so a body which is
+	// never sent doesn't leak it.
+	goroutines := goruntime.NumGoroutine()
+	_, reader := NewMultipartBody(uploadBody{Name: "Fido"}, nil)
+	assert.Equal(t, goroutines, goruntime.NumGoroutine())
+
+	require.NoError(t, reader.Close())
+	_, err := reader.Read(make([]byte, 1))
+	assert.Equal(t, io.ErrClosedPipe, err)
+}
+
+func TestContentTypeAllowed(t *testing.T) {
+	assert.True(t, contentTypeAllowed("image/png", "image/png"))
+	assert.True(t, contentTypeAllowed("image/PNG; q=1", "image/jpeg, image/png"))
+	assert.True(t, contentTypeAllowed("image/png", "image/*"))
+	assert.True(t, contentTypeAllowed("text/plain", "*/*"))
+	assert.False(t, contentTypeAllowed("text/plain", "image/*"))
+	assert.False(t, contentTypeAllowed("", "image/png"))
+}
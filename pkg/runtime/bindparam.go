@@ -23,7 +23,7 @@ import (
 
 	"github.com/pkg/errors"
 
-	"github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/leslie-wang/oapi-codegen/pkg/types"
 )
 
 // This function binds a parameter as described in the Path Parameters
@@ -43,6 +43,12 @@ func BindStyledParameter(style string, explode bool, paramName string,
 	t := v.Type()
 
 	if t.Kind() == reflect.Struct {
+		// Times and dates are structs, but they're bound from a single value
+		switch dest.(type) {
+		case *time.Time, *types.Date:
+			return BindStringToObject(value, dest)
+		}
+
 		// We've got a destination object, we'll create a JSON representation
 		// of the input value, and let the json library deal with the unmarshaling
 		parts, err := splitStyledParameter(style, explode, true, paramName, value)
@@ -18,7 +18,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/leslie-wang/oapi-codegen/pkg/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -243,7 +243,7 @@ func TestBindQueryParameter(t *testing.T) {
 		expectedDeepObject := &ID{
 			FirstName: &expectedName,
 			Role:      "admin",
-			Birthday:  &types.Date{time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+			Birthday:  &types.Date{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
 		}
 
 		actual := new(ID)
@@ -261,7 +261,7 @@ func TestBindQueryParameter(t *testing.T) {
 	})
 
 	t.Run("form", func(t *testing.T) {
-		expected := &types.Date{time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+		expected := &types.Date{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
 		birthday := &types.Date{}
 		queryParams := url.Values{
 			"birthday": {"2020-01-01"},
@@ -271,3 +271,26 @@ func TestBindQueryParameter(t *testing.T) {
 		assert.Equal(t, expected, birthday)
 	})
 }
+
+func TestBindStyledParameter(t *testing.T) {
+	t.Run("time", func(t *testing.T) {
+		var value time.Time
+		err := BindStyledParameter("simple", false, "since", "2020-01-02T03:04:05Z", &value)
+		assert.NoError(t, err)
+		assert.True(t, value.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+	})
+
+	t.Run("date", func(t *testing.T) {
+		var value types.Date
+		err := BindStyledParameter("simple", false, "birthday", "2020-01-01", &value)
+		assert.NoError(t, err)
+		assert.Equal(t, types.Date{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}, value)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		var value []int
+		err := BindStyledParameter("simple", false, "ids", "3,4,5", &value)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3, 4, 5}, value)
+	})
+}
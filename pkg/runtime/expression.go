@@ -0,0 +1,175 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ExpressionSource holds the request which OpenAPI runtime expressions, such
+// as the {$request.body#/callbackUrl} in the URL of a callback, are evaluated
+// against.
+type ExpressionSource struct {
+	// The request, which gives $url, $method and the values of query
+	// parameters and headers.
+	Request *http.Request
+
+	// The values of the path parameters of the request, by name.
+	PathParams map[string]string
+
+	// The body of the request, either as its raw JSON in a []byte or
+	// json.RawMessage, or as a value which is marshaled to JSON, such as
+	// the decoded body type.
+	Body interface{}
+}
+
+// ExpandExpressions replaces each runtime expression enclosed in braces in
+// the given template, eg, http://example.com?id={$request.body#/id}, with
+// its value. A template which is a runtime expression by itself, without
+// braces, is evaluated as a whole.
+func ExpandExpressions(template string, source ExpressionSource) (string, error) {
+	if strings.HasPrefix(template, "$") {
+		return EvaluateExpression(template, source)
+	}
+
+	var buf strings.Builder
+	for {
+		start := strings.Index(template, "{$")
+		if start == -1 {
+			buf.WriteString(template)
+			break
+		}
+		end := strings.Index(template[start:], "}")
+		if end == -1 {
+			return "", fmt.Errorf("unterminated runtime expression in '%s'", template)
+		}
+		end += start
+		value, err := EvaluateExpression(template[start+1:end], source)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(template[:start])
+		buf.WriteString(value)
+		template = template[end+1:]
+	}
+	return buf.String(), nil
+}
+
+// EvaluateExpression returns the value of a runtime expression, such as
+// $request.query.id. The expressions which refer to the request are
+// supported: $url, $method, $request.path.<name>, $request.query.<name>,
+// $request.header.<name>, $request.body and $request.body#/<json pointer>.
+// Values which aren't strings are formatted as JSON.
+func EvaluateExpression(expression string, source ExpressionSource) (string, error) {
+	if source.Request == nil {
+		return "", fmt.Errorf("no request to evaluate runtime expression '%s' against", expression)
+	}
+	switch {
+	case expression == "$url":
+		return source.Request.URL.String(), nil
+	case expression == "$method":
+		return source.Request.Method, nil
+	case strings.HasPrefix(expression, "$request.path."):
+		name := strings.TrimPrefix(expression, "$request.path.")
+		value, found := source.PathParams[name]
+		if !found {
+			return "", fmt.Errorf("path parameter '%s' not found", name)
+		}
+		return value, nil
+	case strings.HasPrefix(expression, "$request.query."):
+		name := strings.TrimPrefix(expression, "$request.query.")
+		values, found := source.Request.URL.Query()[name]
+		if !found || len(values) == 0 {
+			return "", fmt.Errorf("query parameter '%s' not found", name)
+		}
+		return values[0], nil
+	case strings.HasPrefix(expression, "$request.header."):
+		name := strings.TrimPrefix(expression, "$request.header.")
+		values := source.Request.Header[http.CanonicalHeaderKey(name)]
+		if len(values) == 0 {
+			return "", fmt.Errorf("header '%s' not found", name)
+		}
+		return values[0], nil
+	case expression == "$request.body" || strings.HasPrefix(expression, "$request.body#"):
+		return evaluateBodyExpression(strings.TrimPrefix(expression, "$request.body"), source.Body)
+	}
+	return "", fmt.Errorf("unsupported runtime expression '%s'", expression)
+}
+
+// evaluateBodyExpression returns the value at the given fragment of the
+// body, which is empty for the whole body, or # followed by a JSON pointer.
+func evaluateBodyExpression(fragment string, body interface{}) (string, error) {
+	var raw []byte
+	switch b := body.(type) {
+	case nil:
+		return "", fmt.Errorf("no request body to evaluate '%s' against", fragment)
+	case []byte:
+		raw = b
+	case json.RawMessage:
+		raw = b
+	default:
+		var err error
+		raw, err = json.Marshal(b)
+		if err != nil {
+			return "", fmt.Errorf("error marshaling request body: %s", err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("error decoding request body: %s", err)
+	}
+
+	pointer := strings.TrimPrefix(fragment, "#")
+	if pointer != "" {
+		if !strings.HasPrefix(pointer, "/") {
+			return "", fmt.Errorf("invalid JSON pointer '%s'", pointer)
+		}
+		for _, token := range strings.Split(pointer[1:], "/") {
+			token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+			switch v := value.(type) {
+			case map[string]interface{}:
+				child, found := v[token]
+				if !found {
+					return "", fmt.Errorf("request body has no value at '%s'", pointer)
+				}
+				value = child
+			case []interface{}:
+				index, err := strconv.Atoi(token)
+				if err != nil || index < 0 || index >= len(v) {
+					return "", fmt.Errorf("request body has no value at '%s'", pointer)
+				}
+				value = v[index]
+			default:
+				return "", fmt.Errorf("request body has no value at '%s'", pointer)
+			}
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type subscription struct {
+	CallbackUrl string   `json:"callbackUrl"`
+	Events      []string `json:"events"`
+	Retries     int      `json:"retries"`
+}
+
+func TestEvaluateExpression(t *testing.T) {
+	req := httptest.NewRequest("POST", "http://example.com/subscriptions/7?topic=pets", nil)
+	req.Header.Set("X-Callback", "http://subscriber.com/header")
+	source := ExpressionSource{
+		Request:    req,
+		PathParams: map[string]string{"id": "7"},
+		Body: subscription{
+			CallbackUrl: "http://subscriber.com/hook",
+			Events:      []string{"created", "deleted"},
+			Retries:     3,
+		},
+	}
+
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{"$url", "http://example.com/subscriptions/7?topic=pets"},
+		{"$method", "POST"},
+		{"$request.path.id", "7"},
+		{"$request.query.topic", "pets"},
+		{"$request.header.x-callback", "http://subscriber.com/header"},
+		{"$request.body#/callbackUrl", "http://subscriber.com/hook"},
+		{"$request.body#/events/1", "deleted"},
+		{"$request.body#/retries", "3"},
+		{"$request.body#/events", `["created","deleted"]`},
+	}
+	for _, tt := range tests {
+		value, err := EvaluateExpression(tt.expression, source)
+		if assert.NoError(t, err, tt.expression) {
+			assert.Equal(t, tt.expected, value, tt.expression)
+		}
+	}
+
+	for _, expression := range []string{
+		"$request.path.name",
+		"$request.query.limit",
+		"$request.header.X-Missing",
+		"$request.body#/missing",
+		"$request.body#/events/2",
+		"$request.body#callbackUrl",
+		"$response.body#/id",
+		"$statusCode",
+	} {
+		_, err := EvaluateExpression(expression, source)
+		assert.Error(t, err, expression)
+	}
+
+	// Raw JSON bodies are evaluated as they are.
+	value, err := EvaluateExpression("$request.body#/a~1b", ExpressionSource{
+		Request: req,
+		Body:    []byte(`{"a/b": 1.50}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1.50", value)
+}
+
+func TestExpandExpressions(t *testing.T) {
+	req := httptest.NewRequest("POST", "http://example.com/subscriptions?topic=pets", nil)
+	source := ExpressionSource{
+		Request: req,
+		Body:    []byte(`{"callbackUrl": "http://subscriber.com/hook", "id": "42"}`),
+	}
+
+	value, err := ExpandExpressions("{$request.body#/callbackUrl}", source)
+	require.NoError(t, err)
+	assert.Equal(t, "http://subscriber.com/hook", value)
+
+	value, err = ExpandExpressions("http://notify.com/{$request.query.topic}?id={$request.body#/id}", source)
+	require.NoError(t, err)
+	assert.Equal(t, "http://notify.com/pets?id=42", value)
+
+	value, err = ExpandExpressions("$request.body#/callbackUrl", source)
+	require.NoError(t, err)
+	assert.Equal(t, "http://subscriber.com/hook", value)
+
+	value, err = ExpandExpressions("http://notify.com/fixed", source)
+	require.NoError(t, err)
+	assert.Equal(t, "http://notify.com/fixed", value)
+
+	_, err = ExpandExpressions("{$request.body#/callbackUrl", source)
+	assert.Error(t, err)
+	_, err = ExpandExpressions("{$request.body#/missing}", source)
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"sync"
+)
+
+// TestReporter is the part of testing.TB which the assertion helpers of
+// generated mocks report failures to.
+type TestReporter interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// MockCall is a call made to a method of a generated mock.
+type MockCall struct {
+	Method string
+
+	// Args holds the arguments of the call, in a struct with a field for
+	// each argument.
+	Args interface{}
+}
+
+// MockRecorder records the calls made to a generated mock. Its zero value is
+// ready to use, and it's safe for concurrent use.
+type MockRecorder struct {
+	mu    sync.RWMutex
+	calls []MockCall
+}
+
+// Record records a call to method.
+func (r *MockRecorder) Record(method string, args interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, MockCall{Method: method, Args: args})
+}
+
+// Calls returns the calls recorded for method, in order, or all of the
+// calls when method is empty.
+func (r *MockRecorder) Calls(method string) []MockCall {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var calls []MockCall
+	for _, call := range r.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets all the recorded calls.
+func (r *MockRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// AssertCalled reports an error to t unless method, which must be one of
+// methods, was called the given number of times. The name of the mock is
+// used in the error. It returns whether the assertion held.
+func (r *MockRecorder) AssertCalled(t TestReporter, mock string, methods []string, method string, times int) bool {
+	t.Helper()
+	known := false
+	for _, m := range methods {
+		if m == method {
+			known = true
+			break
+		}
+	}
+	if !known {
+		t.Errorf("%s has no method %s", mock, method)
+		return false
+	}
+	if n := len(r.Calls(method)); n != times {
+		t.Errorf("expected %s.%s to be called %d times, but it was called %d times", mock, method, times, n)
+		return false
+	}
+	return true
+}
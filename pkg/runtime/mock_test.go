@@ -0,0 +1,61 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testReporter struct {
+	errors []string
+}
+
+func (r *testReporter) Helper() {}
+
+func (r *testReporter) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMockRecorder(t *testing.T) {
+	var recorder MockRecorder
+	recorder.Record("GetPet", struct{ ID int }{1})
+	recorder.Record("AddPet", struct{ Name string }{"Fido"})
+	recorder.Record("GetPet", struct{ ID int }{2})
+
+	calls := recorder.Calls("GetPet")
+	require.Len(t, calls, 2)
+	assert.Equal(t, struct{ ID int }{2}, calls[1].Args)
+	assert.Len(t, recorder.Calls(""), 3)
+	assert.Equal(t, "AddPet", recorder.Calls("")[1].Method)
+
+	methods := []string{"AddPet", "DeletePet", "GetPet"}
+	reporter := &testReporter{}
+	assert.True(t, recorder.AssertCalled(reporter, "PetsMock", methods, "GetPet", 2))
+	assert.True(t, recorder.AssertCalled(reporter, "PetsMock", methods, "DeletePet", 0))
+	assert.Empty(t, reporter.errors)
+
+	assert.False(t, recorder.AssertCalled(reporter, "PetsMock", methods, "AddPet", 2))
+	assert.False(t, recorder.AssertCalled(reporter, "PetsMock", methods, "FindPets", 0))
+	assert.Equal(t, []string{
+		"expected PetsMock.AddPet to be called 2 times, but it was called 1 times",
+		"PetsMock has no method FindPets",
+	}, reporter.errors)
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Calls(""))
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object, which describes why a
+// request failed. Requests which fail validation list each failure in
+// Errors.
+type Problem struct {
+	Type     string              `json:"type,omitempty"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Errors   []ValidationFailure `json:"errors,omitempty"`
+}
+
+// ValidationFailure describes one of the ways in which a request fails
+// validation.
+type ValidationFailure struct {
+	// Where the failure is: path, query, header, cookie or body
+	Location string `json:"location"`
+
+	// A JSON pointer to the failing value, within the body or a parameter
+	// holding JSON, which is empty for the whole value
+	Pointer string `json:"pointer,omitempty"`
+
+	// The name of the failing parameter, which is empty for the body
+	Parameter string `json:"parameter,omitempty"`
+
+	Reason string `json:"reason"`
+}
+
+func (f ValidationFailure) String() string {
+	switch {
+	case f.Parameter != "" && f.Pointer != "":
+		return fmt.Sprintf("%s parameter %s at %s: %s", f.Location, f.Parameter, f.Pointer, f.Reason)
+	case f.Parameter != "":
+		return fmt.Sprintf("%s parameter %s: %s", f.Location, f.Parameter, f.Reason)
+	case f.Pointer != "":
+		return fmt.Sprintf("%s at %s: %s", f.Location, f.Pointer, f.Reason)
+	default:
+		return fmt.Sprintf("%s: %s", f.Location, f.Reason)
+	}
+}
+
+// NewValidationProblem returns the problem describing a request which failed
+// validation in the given ways.
+func NewValidationProblem(status int, failures ...ValidationFailure) *Problem {
+	detail := "The request failed validation"
+	switch len(failures) {
+	case 0:
+	case 1:
+		detail = "The request failed validation: " + failures[0].String()
+	default:
+		detail = fmt.Sprintf("The request failed validation in %d ways", len(failures))
+	}
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Errors: failures,
+	}
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// WriteProblem writes p as the response, with its status.
+func WriteProblem(w http.ResponseWriter, p *Problem) {
+	buf, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, p.Error(), p.Status)
+		return
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(buf)
+}
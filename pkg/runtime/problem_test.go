@@ -0,0 +1,49 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteProblem(t *testing.T) {
+	problem := NewValidationProblem(http.StatusBadRequest,
+		ValidationFailure{Location: "query", Parameter: "limit", Reason: "number must be at most 100"},
+		ValidationFailure{Location: "body", Pointer: "/name", Reason: "Field must be set to string or not be present"},
+	)
+	assert.EqualError(t, problem, "The request failed validation in 2 ways")
+
+	rec := httptest.NewRecorder()
+	WriteProblem(rec, problem)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "The request failed validation in 2 ways",
+		"errors": [
+			{"location": "query", "parameter": "limit", "reason": "number must be at most 100"},
+			{"location": "body", "pointer": "/name", "reason": "Field must be set to string or not be present"}
+		]
+	}`, rec.Body.String())
+
+	problem = NewValidationProblem(http.StatusBadRequest,
+		ValidationFailure{Location: "path", Parameter: "id", Reason: "invalid format"})
+	assert.EqualError(t, problem, "The request failed validation: path parameter id: invalid format")
+}
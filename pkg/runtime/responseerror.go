@@ -0,0 +1,85 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ResponseError is implemented by the errors which generated clients return
+// for responses with a status other than 2XX: the error types generated for
+// the error responses declared by operations, and UnexpectedResponseError.
+type ResponseError interface {
+	error
+
+	// Response returns the response which the error was returned for. Its
+	// body has been read already.
+	Response() *http.Response
+
+	// ResponseBody returns the body of the response.
+	ResponseBody() []byte
+}
+
+// UnexpectedResponseError is returned for responses with a status other than
+// 2XX, which the operation called doesn't declare.
+type UnexpectedResponseError struct {
+	OperationID  string
+	HTTPResponse *http.Response
+	Body         []byte
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	return ResponseErrorMessage(e.OperationID, e.HTTPResponse)
+}
+
+// Response returns the response which the error was returned for.
+func (e *UnexpectedResponseError) Response() *http.Response {
+	return e.HTTPResponse
+}
+
+// ResponseBody returns the body of the response.
+func (e *UnexpectedResponseError) ResponseBody() []byte {
+	return e.Body
+}
+
+// ResponseErrorMessage returns the message of the error returned for a
+// response with a non-success status to a call of the given operation.
+func ResponseErrorMessage(operationID string, rsp *http.Response) string {
+	if rsp == nil {
+		return fmt.Sprintf("%s: no response", operationID)
+	}
+	return fmt.Sprintf("%s: response status %s", operationID, rsp.Status)
+}
+
+// AsResponseError finds the first ResponseError in the chain of err, as
+// errors.As does.
+func AsResponseError(err error) (ResponseError, bool) {
+	var responseError ResponseError
+	if errors.As(err, &responseError) {
+		return responseError, true
+	}
+	return nil, false
+}
+
+// ResponseStatusCode returns the status code of the response which err was
+// returned for, or 0 if err isn't, and doesn't wrap, a ResponseError.
+func ResponseStatusCode(err error) int {
+	responseError, ok := AsResponseError(err)
+	if !ok || responseError.Response() == nil {
+		return 0
+	}
+	return responseError.Response().StatusCode
+}
@@ -0,0 +1,50 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnexpectedResponseError(t *testing.T) {
+	rsp := &http.Response{StatusCode: http.StatusTeapot, Status: "418 I'm a teapot"}
+	var err error = &UnexpectedResponseError{
+		OperationID:  "FindPets",
+		HTTPResponse: rsp,
+		Body:         []byte("short and stout"),
+	}
+	assert.EqualError(t, err, "FindPets: response status 418 I'm a teapot")
+
+	// The helpers see through wrapped errors.
+	wrapped := fmt.Errorf("listing pets: %w", err)
+	responseError, ok := AsResponseError(wrapped)
+	require.True(t, ok)
+	assert.Equal(t, rsp, responseError.Response())
+	assert.Equal(t, "short and stout", string(responseError.ResponseBody()))
+	assert.Equal(t, http.StatusTeapot, ResponseStatusCode(wrapped))
+
+	var unexpected *UnexpectedResponseError
+	assert.True(t, errors.As(wrapped, &unexpected))
+
+	_, ok = AsResponseError(errors.New("connection refused"))
+	assert.False(t, ok)
+	assert.Equal(t, 0, ResponseStatusCode(errors.New("connection refused")))
+	assert.Equal(t, 0, ResponseStatusCode(nil))
+}
@@ -0,0 +1,240 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Route maps the requests for an OpenAPI path template, such as
+// /pets/{id}, and a method to a handler.
+type Route struct {
+	Method  string
+	Path    string
+	Handler http.HandlerFunc
+}
+
+// pathSegment is a segment of a path template, split into the literal text
+// and the names of the parameters in it. Pieces alternate between literal
+// text and parameter names, starting with the literal text, which may be
+// empty.
+type pathSegment struct {
+	pieces []string
+}
+
+func parsePathSegment(segment string) (pathSegment, error) {
+	var pieces []string
+	for {
+		start := strings.Index(segment, "{")
+		if start == -1 {
+			pieces = append(pieces, segment)
+			break
+		}
+		end := strings.Index(segment[start:], "}")
+		if end == -1 {
+			return pathSegment{}, fmt.Errorf("unterminated parameter in '%s'", segment)
+		}
+		end += start
+		if end == start+1 {
+			return pathSegment{}, fmt.Errorf("empty parameter name in '%s'", segment)
+		}
+		pieces = append(pieces, segment[:start], segment[start+1:end])
+		segment = segment[end+1:]
+	}
+	return pathSegment{pieces: pieces}, nil
+}
+
+// rank orders segments from the most to the least specific: literal
+// segments, then parameters with literal text around them, then segments
+// which are a single parameter.
+func (s pathSegment) rank() int {
+	switch {
+	case len(s.pieces) == 1:
+		return 0
+	case len(s.pieces) == 3 && s.pieces[0] == "" && s.pieces[2] == "":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// match matches the escaped text of a request path segment, storing the
+// unescaped values of the parameters in params. Each parameter extends up
+// to the first occurrence of the literal text which follows it.
+func (s pathSegment) match(text string, params map[string]string) bool {
+	if !strings.HasPrefix(text, s.pieces[0]) {
+		return false
+	}
+	text = text[len(s.pieces[0]):]
+	for i := 1; i < len(s.pieces); i += 2 {
+		name, literal := s.pieces[i], s.pieces[i+1]
+		end := len(text)
+		if literal != "" {
+			end = strings.Index(text, literal)
+			if end == -1 {
+				return false
+			}
+		}
+		if end == 0 {
+			return false
+		}
+		value, err := url.PathUnescape(text[:end])
+		if err != nil {
+			return false
+		}
+		params[name] = value
+		text = text[end+len(literal):]
+	}
+	return text == ""
+}
+
+// compiledRoute is a Route whose path template has been parsed.
+type compiledRoute struct {
+	Route
+	template string // the path template without its leading slash
+	segments []pathSegment
+}
+
+func (c compiledRoute) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(c.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, segment := range c.segments {
+		if !segment.match(segments[i], params) {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// moreSpecific returns whether a should be tried before b, so that
+// /pets/mine is matched before /pets/{id}.
+func moreSpecific(a, b compiledRoute) bool {
+	for i := 0; i < len(a.segments) && i < len(b.segments); i++ {
+		if ra, rb := a.segments[i].rank(), b.segments[i].rank(); ra != rb {
+			return ra < rb
+		}
+	}
+	return len(a.segments) < len(b.segments)
+}
+
+// router is an http.Handler which dispatches requests to routes.
+type router struct {
+	baseURL string
+	routes  []compiledRoute
+}
+
+// NewRouter returns an http.Handler which dispatches requests to the given
+// routes, serving their paths under baseURL. Requests for paths without a
+// route get a 404 response, and requests with a method which none of the
+// routes for their path support get a 405 response, listing the methods
+// which are in the Allow header. HEAD requests are served by GET routes,
+// unless there's a HEAD route for the path. The values of path parameters are available to
+// handlers through PathParam. An empty path is the same as /, and serves
+// baseURL itself. It panics if a path template is malformed.
+func NewRouter(baseURL string, routes []Route) http.Handler {
+	rt := &router{baseURL: strings.TrimSuffix(baseURL, "/")}
+	for _, route := range routes {
+		compiled := compiledRoute{Route: route, template: strings.TrimPrefix(route.Path, "/")}
+		for _, segment := range strings.Split(compiled.template, "/") {
+			parsed, err := parsePathSegment(segment)
+			if err != nil {
+				panic(fmt.Sprintf("invalid path '%s': %s", route.Path, err))
+			}
+			compiled.segments = append(compiled.segments, parsed)
+		}
+		rt.routes = append(rt.routes, compiled)
+	}
+	sort.SliceStable(rt.routes, func(i, j int) bool {
+		return moreSpecific(rt.routes[i], rt.routes[j])
+	})
+	return rt
+}
+
+func (rt *router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.EscapedPath()
+	rest := strings.TrimPrefix(path, rt.baseURL)
+	if !strings.HasPrefix(path, rt.baseURL) || (rest != "" && rest[0] != '/') {
+		http.NotFound(w, r)
+		return
+	}
+	segments := strings.Split(strings.TrimPrefix(rest, "/"), "/")
+
+	route, params, allowed := rt.find(r.Method, segments)
+	if route == nil && r.Method == http.MethodHead {
+		// HEAD requests are served by the GET route of the path, unless
+		// there's a HEAD route for it.
+		route, params, _ = rt.find(http.MethodGet, segments)
+	}
+	if route != nil {
+		ctx := context.WithValue(r.Context(), pathParamsKey{}, params)
+		route.Handler(w, r.WithContext(ctx))
+		return
+	}
+
+	if len(allowed) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+// find returns the most specific route matching the path segments for the
+// given method, with its path parameters. When there's none, it returns the
+// methods of all the routes which match the path, including HEAD when GET is
+// among them, so that /pets/mine is still served by GET /pets/{id} when it
+// only has a POST route of its own.
+func (rt *router) find(method string, segments []string) (*compiledRoute, map[string]string, []string) {
+	var allowed []string
+	seen := make(map[string]bool)
+	allow := func(method string) {
+		if !seen[method] {
+			seen[method] = true
+			allowed = append(allowed, method)
+		}
+	}
+	for i, route := range rt.routes {
+		params, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+		if route.Method == method {
+			return &rt.routes[i], params, nil
+		}
+		allow(route.Method)
+		if route.Method == http.MethodGet {
+			allow(http.MethodHead)
+		}
+	}
+	return nil, nil, allowed
+}
+
+// pathParamsKey is the context key under which the router stores the path
+// parameters of a request.
+type pathParamsKey struct{}
+
+// PathParam returns the value of the named path parameter of a request
+// dispatched by a router created with NewRouter, or an empty string if
+// there's no such parameter.
+func PathParam(r *http.Request, name string) string {
+	params, _ := r.Context().Value(pathParamsKey{}).(map[string]string)
+	return params[name]
+}
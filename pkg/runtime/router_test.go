@@ -0,0 +1,145 @@
+// Copyright 2019 DeepMap, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package runtime
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	handler := func(name string, params ...string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, name)
+			for _, p := range params {
+				fmt.Fprintf(w, " %s=%s", p, PathParam(r, p))
+			}
+		}
+	}
+	router := NewRouter("/api", []Route{
+		{Method: "GET", Path: "/pets/{id}", Handler: handler("getPet", "id")},
+		{Method: "DELETE", Path: "/pets/{id}", Handler: handler("deletePet", "id")},
+		{Method: "GET", Path: "/pets/mine", Handler: handler("myPets")},
+		{Method: "GET", Path: "/pets", Handler: handler("findPets")},
+		{Method: "GET", Path: "/files/{name}.{ext}", Handler: handler("getFile", "name", "ext")},
+		{Method: "GET", Path: "/", Handler: handler("root")},
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{"GET", "/api/pets", http.StatusOK, "findPets"},
+		{"GET", "/api/pets/7", http.StatusOK, "getPet id=7"},
+		{"DELETE", "/api/pets/7", http.StatusOK, "deletePet id=7"},
+		{"GET", "/api/pets/mine", http.StatusOK, "myPets"},
+		{"GET", "/api/pets/a%2Fb", http.StatusOK, "getPet id=a/b"},
+		{"GET", "/api/pets/;id=3", http.StatusOK, "getPet id=;id=3"},
+		{"GET", "/api/files/report.tar.gz", http.StatusOK, "getFile name=report ext=tar.gz"},
+		{"GET", "/api/", http.StatusOK, "root"},
+		{"GET", "/api", http.StatusOK, "root"},
+		{"GET", "/apis", http.StatusNotFound, ""},
+		{"GET", "/api/pets/", http.StatusNotFound, ""},
+		{"GET", "/api/pets/7/photo", http.StatusNotFound, ""},
+		{"GET", "/pets", http.StatusNotFound, ""},
+		{"GET", "/api/files/.txt", http.StatusNotFound, ""},
+		{"POST", "/api/pets/7", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		assert.Equal(t, tt.code, rec.Code, "%s %s", tt.method, tt.path)
+		if tt.body != "" {
+			assert.Equal(t, tt.body, rec.Body.String(), "%s %s", tt.method, tt.path)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("PUT", "/api/pets/7", nil))
+	assert.Equal(t, "GET, HEAD, DELETE", rec.Header().Get("Allow"))
+}
+
+func TestRouterMethodOfLessSpecificPath(t *testing.T) {
+	router := NewRouter("", []Route{
+		{Method: "GET", Path: "/pets/{id}", Handler: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "getPet id=%s", PathParam(r, "id"))
+		}},
+		{Method: "POST", Path: "/pets/mine", Handler: func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "addMyPet")
+		}},
+	})
+
+	// /pets/mine has no GET route of its own, so /pets/{id} serves it.
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/pets/mine", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "getPet id=mine", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("POST", "/pets/mine", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "addMyPet", rec.Body.String())
+
+	// The methods of all the paths matching the request are allowed.
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("DELETE", "/pets/mine", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "POST, GET, HEAD", rec.Header().Get("Allow"))
+}
+
+func TestRouterHead(t *testing.T) {
+	router := NewRouter("", []Route{
+		{Method: "GET", Path: "/pets", Handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Handler", "findPets")
+		}},
+		{Method: "GET", Path: "/pets/{id}", Handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Handler", "getPet")
+		}},
+		{Method: "HEAD", Path: "/pets/{id}", Handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Handler", "headPet")
+		}},
+		{Method: "POST", Path: "/owners", Handler: http.NotFound},
+	})
+
+	tests := []struct {
+		path    string
+		code    int
+		handler string
+	}{
+		{"/pets", http.StatusOK, "findPets"},
+		{"/pets/7", http.StatusOK, "headPet"},
+		{"/owners", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("HEAD", tt.path, nil))
+		assert.Equal(t, tt.code, rec.Code, tt.path)
+		assert.Equal(t, tt.handler, rec.Header().Get("X-Handler"), tt.path)
+	}
+}
+
+func TestNewRouterInvalidPath(t *testing.T) {
+	assert.Panics(t, func() {
+		NewRouter("", []Route{{Method: "GET", Path: "/pets/{id", Handler: http.NotFound}})
+	})
+	assert.Panics(t, func() {
+		NewRouter("", []Route{{Method: "GET", Path: "/pets/{}", Handler: http.NotFound}})
+	})
+}
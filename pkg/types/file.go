@@ -0,0 +1,84 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/textproto"
+)
+
+// File is the Go type of string schemas with format binary in
+// multipart/form-data bodies, which are sent as file parts. The content is
+// read from Reader when it's needed, so that clients can stream large files
+// rather than hold them in memory. Generated servers stream bodies with file
+// parts to handlers with a runtime.MultipartReader instead, while files bound
+// with runtime.BindMultipart read from the parsed form.
+type File struct {
+	// Filename is the name of the file, as sent in the Content-Disposition
+	// header of its part.
+	Filename string
+
+	// ContentType is the media type of the content, eg, image/png.
+	ContentType string
+
+	// Header holds any further headers of the part carrying the file.
+	Header textproto.MIMEHeader
+
+	// Reader provides the content of the file.
+	Reader io.Reader
+}
+
+// NewFile returns a File which reads its content from r.
+func NewFile(filename, contentType string, r io.Reader) File {
+	return File{
+		Filename:    filename,
+		ContentType: contentType,
+		Reader:      r,
+	}
+}
+
+// Read reads the content of the file, so that a File can be used wherever an
+// io.Reader is expected.
+func (f File) Read(p []byte) (int, error) {
+	if f.Reader == nil {
+		return 0, io.EOF
+	}
+	return f.Reader.Read(p)
+}
+
+// Close closes the underlying reader, if it is an io.Closer. Files received
+// by a server should be closed once the handler is done with them.
+func (f File) Close() error {
+	if c, ok := f.Reader.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Bytes reads the whole content of the file into memory.
+func (f File) Bytes() ([]byte, error) {
+	if f.Reader == nil {
+		return nil, nil
+	}
+	return ioutil.ReadAll(f.Reader)
+}
+
+// MarshalJSON encodes the content of the file as a base64 string, which is
+// how binary data is represented in JSON. This consumes the reader.
+func (f File) MarshalJSON() ([]byte, error) {
+	content, err := f.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(content)
+}
+
+func (f *File) UnmarshalJSON(data []byte) error {
+	var content []byte
+	if err := json.Unmarshal(data, &content); err != nil {
+		return err
+	}
+	f.Reader = bytes.NewReader(content)
+	return nil
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile_Read(t *testing.T) {
+	f := NewFile("hello.txt", "text/plain", strings.NewReader("hello"))
+	content, err := ioutil.ReadAll(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+	assert.NoError(t, f.Close())
+
+	// A file without a reader is empty.
+	content, err = ioutil.ReadAll(File{})
+	assert.NoError(t, err)
+	assert.Empty(t, content)
+}
+
+func TestFile_MarshalJSON(t *testing.T) {
+	b := struct {
+		FileField File `json:"file"`
+	}{
+		FileField: NewFile("", "", strings.NewReader("hello")),
+	}
+	jsonBytes, err := json.Marshal(b)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"file":"aGVsbG8="}`, string(jsonBytes))
+}
+
+func TestFile_UnmarshalJSON(t *testing.T) {
+	b := struct {
+		FileField File `json:"file"`
+	}{}
+	err := json.Unmarshal([]byte(`{"file":"aGVsbG8="}`), &b)
+	assert.NoError(t, err)
+	content, err := b.FileField.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+}
@@ -4,5 +4,4 @@ package tools
 
 import (
 	_ "github.com/cyberdelia/templates"
-	_ "github.com/matryer/moq"
 )